@@ -1,10 +1,13 @@
 package tonicpow
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
 )
 
 // permitFields will remove fields that cannot be used
@@ -13,12 +16,75 @@ func (a *AdvertiserProfile) permitFields() {
 	a.UserID = 0
 }
 
+// Validate will check the profile before creating it and return ValidationErrors listing every problem found
+//
+// The name is required, the homepage and icon URLs (if set) must be absolute http(s) URLs
+func (a *AdvertiserProfile) Validate() error {
+	var errs ValidationErrors
+	if len(strings.TrimSpace(a.Name)) == 0 {
+		errs.add(fieldName, "is required")
+	}
+	if len(a.HomepageURL) > 0 {
+		if err := validateURL(a.HomepageURL); err != nil {
+			errs.add(fieldHomepageURL, "%s", err.Error())
+		}
+	}
+	if len(a.IconURL) > 0 {
+		if err := validateURL(a.IconURL); err != nil {
+			errs.add(fieldIconURL, "%s", err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// CreateAdvertiserProfile will make a new advertiser profile (for the user of the API key)
+// Use WithIdempotencyKey() to safely retry the request without creating duplicate profiles
+func (c *Client) CreateAdvertiserProfile(profile *AdvertiserProfile, opts ...RequestOps) (*StandardResponse, error) {
+	return c.CreateAdvertiserProfileWithContext(context.Background(), profile, opts...)
+}
+
+// CreateAdvertiserProfileWithContext is the same as CreateAdvertiserProfile but uses the given context
+func (c *Client) CreateAdvertiserProfileWithContext(ctx context.Context, profile *AdvertiserProfile,
+	opts ...RequestOps) (*StandardResponse, error) {
+
+	// Basic requirements
+	if err := profile.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Permit fields
+	profile.permitFields()
+	profile.ID = 0
+
+	// Fire the Request
+	response, err := c.RequestWithOptions(
+		ctx, http.MethodPost,
+		"/"+modelAdvertiser,
+		profile, http.StatusCreated, append([]RequestOps{withOperation("CreateAdvertiserProfile")}, opts...)...,
+	)
+	if err != nil {
+		return response, err
+	}
+
+	// Convert model response
+	return response, json.Unmarshal(response.Body, &profile)
+}
+
 // GetAdvertiserProfile will get an existing advertiser profile
 // This will return an Error if the profile is not found (404)
 //
 // For more information: https://docs.tonicpow.com/#b3a62d35-7778-4314-9321-01f5266c3b51
 func (c *Client) GetAdvertiserProfile(profileID uint64) (profile *AdvertiserProfile,
 	response *StandardResponse, err error) {
+	return c.GetAdvertiserProfileWithContext(context.Background(), profileID)
+}
+
+// GetAdvertiserProfileWithContext is the same as GetAdvertiserProfile but uses the given context
+func (c *Client) GetAdvertiserProfileWithContext(ctx context.Context, profileID uint64) (profile *AdvertiserProfile,
+	response *StandardResponse, err error) {
 
 	// Must have an ID
 	if profileID == 0 {
@@ -27,10 +93,11 @@ func (c *Client) GetAdvertiserProfile(profileID uint64) (profile *AdvertiserProf
 	}
 
 	// Fire the Request
-	if response, err = c.Request(
-		http.MethodGet,
-		fmt.Sprintf("/%s/details/%d", modelAdvertiser, profileID),
-		nil, http.StatusOK,
+	if response, err = c.RequestWithOptions(
+		ctx, http.MethodGet,
+		advertiserProfileEndpoint(profileID),
+		nil, http.StatusOK, withCacheEndpoint(CacheEndpointAdvertiserProfile),
+		withOperation("GetAdvertiserProfile"), withCallIDs(CallIDs{AdvertiserProfileID: profileID}),
 	); err != nil {
 		return
 	}
@@ -44,6 +111,12 @@ func (c *Client) GetAdvertiserProfile(profileID uint64) (profile *AdvertiserProf
 //
 // For more information: https://docs.tonicpow.com/#0cebd1ff-b1ce-4111-aff6-9d586f632a84
 func (c *Client) UpdateAdvertiserProfile(profile *AdvertiserProfile) (*StandardResponse, error) {
+	return c.UpdateAdvertiserProfileWithContext(context.Background(), profile)
+}
+
+// UpdateAdvertiserProfileWithContext is the same as UpdateAdvertiserProfile but uses the given context
+func (c *Client) UpdateAdvertiserProfileWithContext(ctx context.Context,
+	profile *AdvertiserProfile) (*StandardResponse, error) {
 
 	// Basic requirements
 	if profile.ID == 0 {
@@ -54,24 +127,61 @@ func (c *Client) UpdateAdvertiserProfile(profile *AdvertiserProfile) (*StandardR
 	profile.permitFields()
 
 	// Fire the Request
-	response, err := c.Request(
-		http.MethodPut,
+	response, err := c.RequestWithOptions(
+		ctx, http.MethodPut,
 		"/"+modelAdvertiser,
 		profile, http.StatusOK,
+		withOperation("UpdateAdvertiserProfile"), withCallIDs(CallIDs{AdvertiserProfileID: profile.ID}),
 	)
 	if err != nil {
 		return response, err
 	}
 
+	// Remove the cached profile
+	c.invalidate(advertiserProfileEndpoint(profile.ID))
+
 	// Convert model response
 	return response, json.Unmarshal(response.Body, &profile)
 }
 
-// ListCampaignsByAdvertiserProfile will return a list of campaigns
+// ListCampaignsByAdvertiserProfile will return a list of campaigns (see QueryCampaignsByAdvertiserProfile)
 //
 // For more information: https://docs.tonicpow.com/#98017e9a-37dd-4810-9483-b6c400572e0c
 func (c *Client) ListCampaignsByAdvertiserProfile(profileID uint64, page, resultsPerPage int,
 	sortBy, sortOrder string) (campaigns *CampaignResults, response *StandardResponse, err error) {
+	return c.ListCampaignsByAdvertiserProfileWithContext(
+		context.Background(), profileID, page, resultsPerPage, sortBy, sortOrder,
+	)
+}
+
+// ListCampaignsByAdvertiserProfileWithContext is the same as ListCampaignsByAdvertiserProfile but uses the given context
+func (c *Client) ListCampaignsByAdvertiserProfileWithContext(ctx context.Context, profileID uint64,
+	page, resultsPerPage int, sortBy, sortOrder string) (campaigns *CampaignResults,
+	response *StandardResponse, err error) {
+	options := newPositionalListOptions(page, resultsPerPage, sortBy, sortOrder)
+	return c.queryCampaignsByAdvertiserProfile(ctx, profileID, &options, withOperation("ListCampaignsByAdvertiserProfile"), withCallIDs(CallIDs{AdvertiserProfileID: profileID}))
+}
+
+// QueryCampaignsByAdvertiserProfile will return a list of campaigns using the options
+//
+// For more information: https://docs.tonicpow.com/#98017e9a-37dd-4810-9483-b6c400572e0c
+func (c *Client) QueryCampaignsByAdvertiserProfile(profileID uint64, options *ListOptions) (campaigns *CampaignResults,
+	response *StandardResponse, err error) {
+	return c.QueryCampaignsByAdvertiserProfileWithContext(context.Background(), profileID, options)
+}
+
+// QueryCampaignsByAdvertiserProfileWithContext is the same as QueryCampaignsByAdvertiserProfile but uses the given context
+func (c *Client) QueryCampaignsByAdvertiserProfileWithContext(ctx context.Context, profileID uint64,
+	options *ListOptions) (campaigns *CampaignResults, response *StandardResponse, err error) {
+	return c.queryCampaignsByAdvertiserProfile(
+		ctx, profileID, options,
+		withOperation("QueryCampaignsByAdvertiserProfile"), withCallIDs(CallIDs{AdvertiserProfileID: profileID}),
+	)
+}
+
+// queryCampaignsByAdvertiserProfile will return a list of campaigns for the profile using the options
+func (c *Client) queryCampaignsByAdvertiserProfile(ctx context.Context, profileID uint64, options *ListOptions,
+	opts ...RequestOps) (campaigns *CampaignResults, response *StandardResponse, err error) {
 
 	// Basic requirements
 	if profileID == 0 {
@@ -79,27 +189,20 @@ func (c *Client) ListCampaignsByAdvertiserProfile(profileID uint64, page, result
 		return
 	}
 
-	// Do we know this field?
-	if len(sortBy) > 0 {
-		if !isInList(strings.ToLower(sortBy), campaignSortFields) {
-			err = fmt.Errorf("sort by %s is not valid", sortBy)
-			return
-		}
-	} else {
-		sortBy = SortByFieldCreatedAt
-		sortOrder = SortOrderDesc
+	// Validate the options
+	if options == nil {
+		options = new(ListOptions)
+	}
+	var query queryValues
+	if err = options.encode(&query, campaignSortFields); err != nil {
+		return
 	}
 
 	// Fire the Request
-	if response, err = c.Request(
-		http.MethodGet,
-		fmt.Sprintf("/%s/%s/%d?%s=%d&%s=%d&%s=%s&%s=%s", modelAdvertiser, modelCampaign, profileID,
-			fieldCurrentPage, page,
-			fieldResultsPerPage, resultsPerPage,
-			fieldSortBy, sortBy,
-			fieldSortOrder, sortOrder,
-		),
-		nil, http.StatusOK,
+	if response, err = c.RequestWithOptions(
+		ctx, http.MethodGet,
+		fmt.Sprintf("/%s/%s/%d?%s", modelAdvertiser, modelCampaign, profileID, query),
+		nil, http.StatusOK, opts...,
 	); err != nil {
 		return
 	}
@@ -109,11 +212,45 @@ func (c *Client) ListCampaignsByAdvertiserProfile(profileID uint64, page, result
 	return
 }
 
-// ListAppsByAdvertiserProfile will return a list of apps
+// ListAppsByAdvertiserProfile will return a list of apps (see QueryAppsByAdvertiserProfile)
 //
 // For more information: https://docs.tonicpow.com/#9c9fa8dc-3017-402e-8059-136b0eb85c2e
 func (c *Client) ListAppsByAdvertiserProfile(profileID uint64, page, resultsPerPage int,
 	sortBy, sortOrder string) (apps *AppResults, response *StandardResponse, err error) {
+	return c.ListAppsByAdvertiserProfileWithContext(
+		context.Background(), profileID, page, resultsPerPage, sortBy, sortOrder,
+	)
+}
+
+// ListAppsByAdvertiserProfileWithContext is the same as ListAppsByAdvertiserProfile but uses the given context
+func (c *Client) ListAppsByAdvertiserProfileWithContext(ctx context.Context, profileID uint64,
+	page, resultsPerPage int, sortBy, sortOrder string) (apps *AppResults,
+	response *StandardResponse, err error) {
+	return c.queryAppsByAdvertiserProfile(ctx, profileID, &AppListOptions{
+		ListOptions: newPositionalListOptions(page, resultsPerPage, sortBy, sortOrder),
+	}, withOperation("ListAppsByAdvertiserProfile"), withCallIDs(CallIDs{AdvertiserProfileID: profileID}))
+}
+
+// QueryAppsByAdvertiserProfile will return a list of apps using the options
+//
+// For more information: https://docs.tonicpow.com/#9c9fa8dc-3017-402e-8059-136b0eb85c2e
+func (c *Client) QueryAppsByAdvertiserProfile(profileID uint64, options *AppListOptions) (apps *AppResults,
+	response *StandardResponse, err error) {
+	return c.QueryAppsByAdvertiserProfileWithContext(context.Background(), profileID, options)
+}
+
+// QueryAppsByAdvertiserProfileWithContext is the same as QueryAppsByAdvertiserProfile but uses the given context
+func (c *Client) QueryAppsByAdvertiserProfileWithContext(ctx context.Context, profileID uint64,
+	options *AppListOptions) (apps *AppResults, response *StandardResponse, err error) {
+	return c.queryAppsByAdvertiserProfile(
+		ctx, profileID, options,
+		withOperation("QueryAppsByAdvertiserProfile"), withCallIDs(CallIDs{AdvertiserProfileID: profileID}),
+	)
+}
+
+// queryAppsByAdvertiserProfile will return a list of apps for the profile using the options
+func (c *Client) queryAppsByAdvertiserProfile(ctx context.Context, profileID uint64, options *AppListOptions,
+	opts ...RequestOps) (apps *AppResults, response *StandardResponse, err error) {
 
 	// Basic requirements
 	if profileID == 0 {
@@ -121,30 +258,21 @@ func (c *Client) ListAppsByAdvertiserProfile(profileID uint64, page, resultsPerP
 		return
 	}
 
-	// Do we know this field?
-	if len(sortBy) > 0 {
-		if !isInList(strings.ToLower(sortBy), appSortFields) {
-			err = fmt.Errorf("sort by %s is not valid", sortBy)
-			return
-		}
-	} else {
-		sortBy = SortByFieldCreatedAt
-		sortOrder = SortOrderDesc
+	// Validate the options
+	if options == nil {
+		options = new(AppListOptions)
+	}
+	query := queryValues{}
+	query.add(fieldID, strconv.FormatUint(profileID, 10))
+	if err = options.encode(&query); err != nil {
+		return
 	}
 
 	// Fire the Request
-	if response, err = c.Request(
-		http.MethodGet,
-		fmt.Sprintf(
-			"/%s/%s/?%s=%d&%s=%d&%s=%d&%s=%s&%s=%s",
-			modelAdvertiser, modelApp,
-			fieldID, profileID,
-			fieldCurrentPage, page,
-			fieldResultsPerPage, resultsPerPage,
-			fieldSortBy, sortBy,
-			fieldSortOrder, sortOrder,
-		),
-		nil, http.StatusOK,
+	if response, err = c.RequestWithOptions(
+		ctx, http.MethodGet,
+		fmt.Sprintf("/%s/%s/?%s", modelAdvertiser, modelApp, query),
+		nil, http.StatusOK, opts...,
 	); err != nil {
 		return
 	}
@@ -153,3 +281,96 @@ func (c *Client) ListAppsByAdvertiserProfile(profileID uint64, page, resultsPerP
 	err = json.Unmarshal(response.Body, &apps)
 	return
 }
+
+// ListAdvertiserProfiles will return a list of advertiser profiles (of the user of the API key)
+// This will return an Error if no profiles are found (404)
+func (c *Client) ListAdvertiserProfiles(options *AdvertiserListOptions) (profiles *AdvertiserResults,
+	response *StandardResponse, err error) {
+	return c.ListAdvertiserProfilesWithContext(context.Background(), options)
+}
+
+// ListAdvertiserProfilesWithContext is the same as ListAdvertiserProfiles but uses the given context
+func (c *Client) ListAdvertiserProfilesWithContext(ctx context.Context, options *AdvertiserListOptions) (
+	profiles *AdvertiserResults, response *StandardResponse, err error) {
+
+	// Validate the options
+	if options == nil {
+		options = new(AdvertiserListOptions)
+	}
+	var query queryValues
+	if err = options.encode(&query); err != nil {
+		return
+	}
+
+	// Fire the Request
+	if response, err = c.RequestWithOptions(
+		ctx, http.MethodGet,
+		fmt.Sprintf("/%s/list?%s", modelAdvertiser, query),
+		nil, http.StatusOK,
+		withOperation("ListAdvertiserProfiles"),
+	); err != nil {
+		return
+	}
+
+	// Convert model response
+	err = json.Unmarshal(response.Body, &profiles)
+	return
+}
+
+// AdvertiserProfileDetails is an advertiser profile with all of its apps and campaigns
+type AdvertiserProfileDetails struct {
+	Apps      []*App             `json:"apps"`
+	Campaigns []*Campaign        `json:"campaigns"`
+	Profile   *AdvertiserProfile `json:"profile"`
+}
+
+// GetAdvertiserProfileDetails will get an existing advertiser profile with all of its apps and campaigns
+// (every page is fetched, the requests are made concurrently)
+// This will return an Error if the profile is not found (404)
+func (c *Client) GetAdvertiserProfileDetails(profileID uint64) (*AdvertiserProfileDetails, error) {
+	return c.GetAdvertiserProfileDetailsWithContext(context.Background(), profileID)
+}
+
+// GetAdvertiserProfileDetailsWithContext is the same as GetAdvertiserProfileDetails but uses the given context
+func (c *Client) GetAdvertiserProfileDetailsWithContext(ctx context.Context,
+	profileID uint64) (*AdvertiserProfileDetails, error) {
+
+	// Must have an ID
+	if profileID == 0 {
+		return nil, fmt.Errorf("missing field: %s", fieldID)
+	}
+
+	details := &AdvertiserProfileDetails{Apps: []*App{}, Campaigns: []*Campaign{}}
+	var profileErr, appsErr, campaignsErr error
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		details.Profile, _, profileErr = c.GetAdvertiserProfileWithContext(ctx, profileID)
+	}()
+	go func() {
+		defer wg.Done()
+		apps := c.IterateAppsByAdvertiserProfile(ctx, profileID, "", "")
+		for apps.Next() {
+			details.Apps = append(details.Apps, apps.App())
+		}
+		appsErr = apps.Err()
+	}()
+	go func() {
+		defer wg.Done()
+		campaigns := c.IterateCampaignsByAdvertiserProfile(ctx, profileID, "", "")
+		for campaigns.Next() {
+			details.Campaigns = append(details.Campaigns, campaigns.Campaign())
+		}
+		campaignsErr = campaigns.Err()
+	}()
+	wg.Wait()
+
+	// The profile error first (not found, etc.)
+	for _, err := range []error{profileErr, appsErr, campaignsErr} {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return details, nil
+}
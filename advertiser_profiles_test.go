@@ -1,11 +1,15 @@
 package tonicpow
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"testing"
 
+	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // newTestAdvertiserProfile creates a dummy profile for testing
@@ -703,3 +707,363 @@ func TestClient_ListAppsByAdvertiserProfile(t *testing.T) {
 		assert.Equal(t, apiError.Message, err.Error())
 	})
 }
+
+// TestClient_GetAdvertiserProfileWithContext will test the method GetAdvertiserProfileWithContext()
+func TestClient_GetAdvertiserProfileWithContext(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	endpoint := fmt.Sprintf("%s/%s/details/%d", EnvironmentDevelopment.apiURL, modelAdvertiser, testAdvertiserID)
+
+	t.Run("valid context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestAdvertiserProfile())
+		assert.NoError(t, err)
+
+		var profile *AdvertiserProfile
+		profile, _, err = client.GetAdvertiserProfileWithContext(context.Background(), testAdvertiserID)
+		assert.NoError(t, err)
+		assert.NotNil(t, profile)
+		assert.Equal(t, testAdvertiserID, profile.ID)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		mockResponseBlocking(http.MethodGet, endpoint)
+
+		var profile *AdvertiserProfile
+		profile, _, err = client.GetAdvertiserProfileWithContext(newCanceledContext(), testAdvertiserID)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, profile)
+	})
+}
+
+// TestClient_UpdateAdvertiserProfileWithContext will test the method UpdateAdvertiserProfileWithContext()
+func TestClient_UpdateAdvertiserProfileWithContext(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	endpoint := fmt.Sprintf("%s/%s", EnvironmentDevelopment.apiURL, modelAdvertiser)
+
+	t.Run("valid context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		profile := newTestAdvertiserProfile()
+		err = mockResponseData(http.MethodPut, endpoint, http.StatusOK, profile)
+		assert.NoError(t, err)
+
+		_, err = client.UpdateAdvertiserProfileWithContext(context.Background(), profile)
+		assert.NoError(t, err)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		mockResponseBlocking(http.MethodPut, endpoint)
+
+		_, err = client.UpdateAdvertiserProfileWithContext(newCanceledContext(), newTestAdvertiserProfile())
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+// TestClient_ListCampaignsByAdvertiserProfileWithContext will test the method ListCampaignsByAdvertiserProfileWithContext()
+func TestClient_ListCampaignsByAdvertiserProfileWithContext(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	endpoint := fmt.Sprintf("%s/%s/%s/%d?%s=%d&%s=%d&%s=%s&%s=%s",
+		EnvironmentDevelopment.apiURL,
+		modelAdvertiser, modelCampaign, testAdvertiserID,
+		fieldCurrentPage, 1,
+		fieldResultsPerPage, 25,
+		fieldSortBy, SortByFieldCreatedAt,
+		fieldSortOrder, SortOrderDesc,
+	)
+
+	t.Run("valid context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestCampaignResults(1, 25))
+		assert.NoError(t, err)
+
+		var results *CampaignResults
+		results, _, err = client.ListCampaignsByAdvertiserProfileWithContext(
+			context.Background(), testAdvertiserID, 1, 25, "", "",
+		)
+		assert.NoError(t, err)
+		assert.NotNil(t, results)
+		assert.Equal(t, 1, len(results.Campaigns))
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		mockResponseBlocking(http.MethodGet, endpoint)
+
+		var results *CampaignResults
+		results, _, err = client.ListCampaignsByAdvertiserProfileWithContext(
+			newCanceledContext(), testAdvertiserID, 1, 25, "", "",
+		)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, results)
+	})
+}
+
+// TestClient_ListAppsByAdvertiserProfileWithContext will test the method ListAppsByAdvertiserProfileWithContext()
+func TestClient_ListAppsByAdvertiserProfileWithContext(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	endpoint := fmt.Sprintf("%s/%s/%s/?%s=%d&%s=%d&%s=%d&%s=%s&%s=%s",
+		EnvironmentDevelopment.apiURL,
+		modelAdvertiser, modelApp,
+		fieldID, testAdvertiserID,
+		fieldCurrentPage, 1,
+		fieldResultsPerPage, 25,
+		fieldSortBy, SortByFieldCreatedAt,
+		fieldSortOrder, SortOrderDesc,
+	)
+
+	t.Run("valid context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestAppResults(1, 25))
+		assert.NoError(t, err)
+
+		var results *AppResults
+		results, _, err = client.ListAppsByAdvertiserProfileWithContext(
+			context.Background(), testAdvertiserID, 1, 25, "", "",
+		)
+		assert.NoError(t, err)
+		assert.NotNil(t, results)
+		assert.Equal(t, 1, len(results.Apps))
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		mockResponseBlocking(http.MethodGet, endpoint)
+
+		var results *AppResults
+		results, _, err = client.ListAppsByAdvertiserProfileWithContext(
+			newCanceledContext(), testAdvertiserID, 1, 25, "", "",
+		)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, results)
+	})
+}
+
+// TestAdvertiserProfile_Validate will test the method Validate()
+func TestAdvertiserProfile_Validate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid profiles", func(t *testing.T) {
+		assert.NoError(t, newTestAdvertiserProfile().Validate())
+		assert.NoError(t, (&AdvertiserProfile{Name: "TonicPow"}).Validate())
+	})
+
+	t.Run("every problem is listed", func(t *testing.T) {
+		profile := &AdvertiserProfile{Name: " ", HomepageURL: "tonicpow.com", IconURL: "ftp://tonicpow.com/icon.png"}
+		err := profile.Validate()
+		assert.ErrorIs(t, err, ErrValidation)
+
+		var errs ValidationErrors
+		require.True(t, errors.As(err, &errs))
+		assert.Equal(t, []string{fieldName, fieldHomepageURL, fieldIconURL}, errs.Fields())
+	})
+}
+
+// TestClient_CreateAdvertiserProfile will test the method CreateAdvertiserProfile()
+func TestClient_CreateAdvertiserProfile(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	endpoint := fmt.Sprintf("%s/%s", EnvironmentDevelopment.apiURL, modelAdvertiser)
+
+	t.Run("create an advertiser (success)", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		err = mockResponseData(http.MethodPost, endpoint, http.StatusCreated, newTestAdvertiserProfile())
+		require.NoError(t, err)
+
+		profile := &AdvertiserProfile{Name: "TonicPow", HomepageURL: "https://tonicpow.com", UserID: 999}
+		var response *StandardResponse
+		response, err = client.CreateAdvertiserProfile(profile)
+		require.NoError(t, err)
+		require.NotNil(t, response)
+		assert.Equal(t, http.StatusCreated, response.StatusCode)
+		assert.Equal(t, uint64(testAdvertiserID), profile.ID)
+		assert.Equal(t, uint64(testUserID), profile.UserID)
+	})
+
+	t.Run("invalid profile is not sent", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+		httpmock.Reset()
+
+		var response *StandardResponse
+		response, err = client.CreateAdvertiserProfile(&AdvertiserProfile{IconURL: "icon.png"})
+		assert.ErrorIs(t, err, ErrValidation)
+		assert.Nil(t, response)
+		assert.Equal(t, 0, httpmock.GetTotalCallCount())
+	})
+
+	t.Run("error from api (status code)", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		apiError := &Error{Code: 400, Message: "some error message", RequestGUID: "7f3d97a8fd67ff57861904df6118dcc8"}
+		err = mockResponseData(http.MethodPost, endpoint, http.StatusBadRequest, apiError)
+		require.NoError(t, err)
+
+		var response *StandardResponse
+		response, err = client.CreateAdvertiserProfileWithContext(context.Background(), &AdvertiserProfile{Name: "TonicPow"})
+		assert.Error(t, err)
+		require.NotNil(t, response)
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	})
+}
+
+// TestClient_ListAdvertiserProfiles will test the method ListAdvertiserProfiles()
+func TestClient_ListAdvertiserProfiles(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	t.Run("list advertisers (success)", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		endpoint := fmt.Sprintf("%s/%s/list?current_page=2&results_per_page=10&sort_by=name&sort_order=asc",
+			EnvironmentDevelopment.apiURL, modelAdvertiser)
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, &AdvertiserResults{
+			Advertisers: []*AdvertiserProfile{newTestAdvertiserProfile()}, CurrentPage: 2, Results: 1, ResultsPerPage: 10,
+		})
+		require.NoError(t, err)
+
+		var profiles *AdvertiserResults
+		profiles, _, err = client.ListAdvertiserProfiles(&AdvertiserListOptions{ListOptions{
+			Page: 2, ResultsPerPage: 10, SortBy: SortByFieldName, SortOrder: SortOrderAsc,
+		}})
+		require.NoError(t, err)
+		require.NotNil(t, profiles)
+		require.Len(t, profiles.Advertisers, 1)
+		assert.Equal(t, uint64(testAdvertiserID), profiles.Advertisers[0].ID)
+	})
+
+	t.Run("nil options (first page, default page size & sorting)", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		endpoint := fmt.Sprintf("%s/%s/list?current_page=1&results_per_page=25&sort_by=created_at&sort_order=desc",
+			EnvironmentDevelopment.apiURL, modelAdvertiser)
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, &AdvertiserResults{})
+		require.NoError(t, err)
+
+		_, _, err = client.ListAdvertiserProfiles(nil)
+		require.NoError(t, err)
+		assert.Equal(t, 1, httpmock.GetTotalCallCount())
+	})
+
+	t.Run("invalid sort field", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+		httpmock.Reset()
+
+		_, _, err = client.ListAdvertiserProfiles(&AdvertiserListOptions{ListOptions{SortBy: SortByFieldBalance}})
+		assert.EqualError(t, err, "sort by balance is not valid")
+		assert.Equal(t, 0, httpmock.GetTotalCallCount())
+	})
+}
+
+// TestClient_GetAdvertiserProfileDetails will test the method GetAdvertiserProfileDetails()
+func TestClient_GetAdvertiserProfileDetails(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	profileEndpoint := fmt.Sprintf("%s/%s/details/%d", EnvironmentDevelopment.apiURL, modelAdvertiser, testAdvertiserID)
+	appsEndpoint := fmt.Sprintf(
+		"%s/%s/%s/?%s=%d&current_page=1&results_per_page=25&sort_by=created_at&sort_order=desc",
+		EnvironmentDevelopment.apiURL, modelAdvertiser, modelApp, fieldID, testAdvertiserID,
+	)
+	campaignsEndpoint := fmt.Sprintf(
+		"%s/%s/%s/%d?current_page=1&results_per_page=25&sort_by=created_at&sort_order=desc",
+		EnvironmentDevelopment.apiURL, modelAdvertiser, modelCampaign, testAdvertiserID,
+	)
+
+	// mockDetails will mock the profile, apps and campaigns responses
+	notFound := &Error{Code: 404, Message: "not found", RequestGUID: "7f3d97a8fd67ff57861904df6118dcc8"}
+	mockDetails := func(profile, apps, campaigns interface{}) {
+		httpmock.Reset()
+		for endpoint, model := range map[string]interface{}{
+			profileEndpoint: profile, appsEndpoint: apps, campaignsEndpoint: campaigns,
+		} {
+			statusCode := http.StatusOK
+			if model == notFound {
+				statusCode = http.StatusNotFound
+			}
+			httpmock.RegisterResponder(http.MethodGet, endpoint, httpmock.NewJsonResponderOrPanic(statusCode, model))
+		}
+	}
+
+	t.Run("profile with apps and campaigns", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		mockDetails(newTestAdvertiserProfile(), newTestAppResults(1, 25), newTestCampaignResults(1, 25))
+
+		var details *AdvertiserProfileDetails
+		details, err = client.GetAdvertiserProfileDetails(testAdvertiserID)
+		require.NoError(t, err)
+		require.NotNil(t, details)
+		assert.Equal(t, uint64(testAdvertiserID), details.Profile.ID)
+		require.Len(t, details.Apps, 1)
+		assert.Equal(t, uint64(testAppID), details.Apps[0].ID)
+		require.Len(t, details.Campaigns, 1)
+		assert.Equal(t, uint64(testCampaignID), details.Campaigns[0].ID)
+	})
+
+	t.Run("no apps or campaigns", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		mockDetails(newTestAdvertiserProfile(), notFound, notFound)
+
+		var details *AdvertiserProfileDetails
+		details, err = client.GetAdvertiserProfileDetails(testAdvertiserID)
+		require.NoError(t, err)
+		assert.Empty(t, details.Apps)
+		assert.Empty(t, details.Campaigns)
+	})
+
+	t.Run("profile not found", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		mockDetails(notFound, notFound, notFound)
+
+		var details *AdvertiserProfileDetails
+		details, err = client.GetAdvertiserProfileDetails(testAdvertiserID)
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Nil(t, details)
+	})
+
+	t.Run("missing profile id", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		_, err = client.GetAdvertiserProfileDetails(0)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,303 @@
+package tonicpow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// webhookEventTypes are the event types that can be delivered to the App.WebhookURL
+var webhookEventTypes = []WebhookEventType{
+	WebhookEventCampaignLowBalance,
+	WebhookEventConversionCanceled,
+	WebhookEventConversionCreated,
+	WebhookEventConversionPaid,
+}
+
+// WebhookTestResult is the result of a test delivery to the App.WebhookURL (see SendTestWebhook)
+type WebhookTestResult struct {
+	Error      string           `json:"error"`       // Delivery error (connection refused, timeout, etc.)
+	EventType  WebhookEventType `json:"event_type"`  // Type of the sample event delivered
+	StatusCode int              `json:"status_code"` // Status code returned by the webhook url (0 if not delivered)
+	Success    bool             `json:"success"`     // True if the webhook url responded with a 2xx status code
+	WebhookURL string           `json:"webhook_url"` // Url the event was delivered to
+}
+
+// permitFields will remove fields that cannot be used
+func (a *App) permitFields() {
+	a.AdvertiserProfileID = 0
+	a.UserID = 0
+}
+
+// Validate will check the app before creating or updating it and return ValidationErrors listing every problem found
+//
+// The name is required (and the advertiser profile for a new app), the webhook URL (if set) must be
+// an absolute https URL (http is only allowed for localhost during development)
+func (a *App) Validate() error {
+	var errs ValidationErrors
+	if a.ID == 0 && a.AdvertiserProfileID == 0 {
+		errs.add(fieldAdvertiserProfileID, "is required")
+	}
+	if len(strings.TrimSpace(a.Name)) == 0 {
+		errs.add(fieldName, "is required")
+	}
+	if len(a.WebhookURL) > 0 {
+		if err := validateWebhookURL(a.WebhookURL); err != nil {
+			errs.add(fieldWebhookURL, "%s", err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validateWebhookURL will return an error if the value is not an absolute https URL
+// (http is only allowed for localhost) or contains credentials or a fragment
+func validateWebhookURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil || len(u.Hostname()) == 0 {
+		return fmt.Errorf("must be an absolute https URL, got %q", value)
+	} else if u.Scheme != "https" && (u.Scheme != "http" || !isLoopbackHost(u.Hostname())) {
+		return fmt.Errorf("must use https (http is only allowed for localhost), got %q", value)
+	} else if u.User != nil {
+		return fmt.Errorf("must not contain credentials, got %q", u.Redacted())
+	} else if len(u.Fragment) > 0 {
+		return fmt.Errorf("must not contain a fragment, got %q", value)
+	}
+	return nil
+}
+
+// isLoopbackHost will return true if the host is localhost or a loopback ip address
+func isLoopbackHost(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// CreateApp will make a new app for the advertiser profile
+// Use WithIdempotencyKey() to safely retry the request without creating duplicate apps
+func (c *Client) CreateApp(app *App, opts ...RequestOps) (*StandardResponse, error) {
+	return c.CreateAppWithContext(context.Background(), app, opts...)
+}
+
+// CreateAppWithContext is the same as CreateApp but uses the given context
+func (c *Client) CreateAppWithContext(ctx context.Context, app *App, opts ...RequestOps) (*StandardResponse, error) {
+
+	// Basic requirements
+	app.ID = 0
+	app.UserID = 0
+	if err := app.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Fire the Request
+	response, err := c.RequestWithOptions(
+		ctx, http.MethodPost,
+		"/"+modelApp,
+		app, http.StatusCreated, append([]RequestOps{
+			withOperation("CreateApp"), withCallIDs(CallIDs{AdvertiserProfileID: app.AdvertiserProfileID}),
+		}, opts...)...,
+	)
+	if err != nil {
+		return response, err
+	}
+
+	return response, json.Unmarshal(response.Body, &app)
+}
+
+// GetApp will get an existing app
+// This will return an Error if the app is not found (404)
+func (c *Client) GetApp(appID uint64) (app *App, response *StandardResponse, err error) {
+	return c.GetAppWithContext(context.Background(), appID)
+}
+
+// GetAppWithContext is the same as GetApp but uses the given context
+func (c *Client) GetAppWithContext(ctx context.Context, appID uint64) (app *App,
+	response *StandardResponse, err error) {
+
+	// Must have an ID
+	if appID == 0 {
+		err = fmt.Errorf("missing required attribute: %s", fieldID)
+		return
+	}
+
+	// Fire the Request
+	if response, err = c.RequestWithOptions(
+		ctx, http.MethodGet,
+		appEndpoint(appID),
+		nil, http.StatusOK, withCacheEndpoint(CacheEndpointApp),
+		withOperation("GetApp"),
+	); err != nil {
+		return
+	}
+
+	err = json.Unmarshal(response.Body, &app)
+	return
+}
+
+// UpdateApp will update an existing app (the advertiser profile of an app cannot be changed)
+func (c *Client) UpdateApp(app *App) (*StandardResponse, error) {
+	return c.UpdateAppWithContext(context.Background(), app)
+}
+
+// UpdateAppWithContext is the same as UpdateApp but uses the given context
+func (c *Client) UpdateAppWithContext(ctx context.Context, app *App) (*StandardResponse, error) {
+
+	// Basic requirements
+	if app.ID == 0 {
+		return nil, fmt.Errorf("missing required attribute: %s", fieldID)
+	} else if err := app.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Permit fields
+	app.permitFields()
+
+	// Fire the Request
+	response, err := c.RequestWithOptions(
+		ctx, http.MethodPut,
+		"/"+modelApp,
+		app, http.StatusOK,
+		withOperation("UpdateApp"),
+	)
+	if err != nil {
+		return response, err
+	}
+
+	// Remove the cached app
+	c.invalidate(appEndpoint(app.ID))
+
+	return response, json.Unmarshal(response.Body, &app)
+}
+
+// PatchApp will update only the fields of the app in the change set
+// (UpdateApp sends every field, including zero values)
+func (c *Client) PatchApp(appID uint64, changes ChangeSet) (app *App, response *StandardResponse, err error) {
+	return c.PatchAppWithContext(context.Background(), appID, changes)
+}
+
+// PatchAppWithContext is the same as PatchApp but uses the given context
+func (c *Client) PatchAppWithContext(ctx context.Context, appID uint64,
+	changes ChangeSet) (app *App, response *StandardResponse, err error) {
+	return c.patchApp(ctx, appID, changes, withOperation("PatchApp"))
+}
+
+// patchApp will update only the fields of the app in the change set
+func (c *Client) patchApp(ctx context.Context, appID uint64, changes ChangeSet,
+	opts ...RequestOps) (app *App, response *StandardResponse, err error) {
+
+	// Validate the changes
+	var payload map[string]interface{}
+	if payload, err = changes.payload(appID, new(App), readOnlyAppFields); err != nil {
+		return
+	} else if webhookURL, ok := payload[fieldWebhookURL].(string); ok && len(webhookURL) > 0 {
+		if err = validateWebhookURL(webhookURL); err != nil {
+			var errs ValidationErrors
+			errs.add(fieldWebhookURL, "%s", err.Error())
+			err = errs
+			return
+		}
+	}
+
+	// Fire the Request
+	if response, err = c.RequestWithOptions(
+		ctx, http.MethodPut,
+		"/"+modelApp,
+		payload, http.StatusOK, opts...,
+	); err != nil {
+		return
+	}
+
+	// Remove the cached app
+	c.invalidate(appEndpoint(appID))
+
+	err = json.Unmarshal(response.Body, &app)
+	return
+}
+
+// UpdateAppWebhookURL will change the webhook url of the app (an empty url removes the webhook)
+func (c *Client) UpdateAppWebhookURL(appID uint64, webhookURL string) (app *App, response *StandardResponse, err error) {
+	return c.UpdateAppWebhookURLWithContext(context.Background(), appID, webhookURL)
+}
+
+// UpdateAppWebhookURLWithContext is the same as UpdateAppWebhookURL but uses the given context
+func (c *Client) UpdateAppWebhookURLWithContext(ctx context.Context, appID uint64,
+	webhookURL string) (app *App, response *StandardResponse, err error) {
+	return c.patchApp(ctx, appID, ChangeSet{}.Set(fieldWebhookURL, webhookURL), withOperation("UpdateAppWebhookURL"))
+}
+
+// DeleteApp will delete an existing app
+func (c *Client) DeleteApp(appID uint64) (bool, *StandardResponse, error) {
+	return c.DeleteAppWithContext(context.Background(), appID)
+}
+
+// DeleteAppWithContext is the same as DeleteApp but uses the given context
+func (c *Client) DeleteAppWithContext(ctx context.Context, appID uint64) (bool, *StandardResponse, error) {
+
+	// Basic requirements
+	if appID == 0 {
+		return false, nil, fmt.Errorf("missing required attribute: %s", fieldID)
+	}
+
+	// Fire the Request
+	response, err := c.RequestWithOptions(
+		ctx, http.MethodDelete,
+		fmt.Sprintf("/%s?%s=%d", modelApp, fieldID, appID),
+		nil, http.StatusOK,
+		withOperation("DeleteApp"),
+	)
+	if err != nil {
+		return false, response, err
+	}
+
+	// Remove the cached app
+	c.invalidate(appEndpoint(appID))
+
+	// Flag for deleted if no error and good response
+	return true, response, err
+}
+
+// SendTestWebhook will deliver a signed sample event of the given type to the webhook url of the app
+// (conversion_created if no type is given), use it to check a receiver (see WebhookHandler) is reachable
+//
+// The delivery result is returned even if the webhook url did not respond with a 2xx (see WebhookTestResult.Success)
+func (c *Client) SendTestWebhook(appID uint64, eventType WebhookEventType) (result *WebhookTestResult,
+	response *StandardResponse, err error) {
+	return c.SendTestWebhookWithContext(context.Background(), appID, eventType)
+}
+
+// SendTestWebhookWithContext is the same as SendTestWebhook but uses the given context
+func (c *Client) SendTestWebhookWithContext(ctx context.Context, appID uint64,
+	eventType WebhookEventType) (result *WebhookTestResult, response *StandardResponse, err error) {
+
+	// Basic requirements
+	if appID == 0 {
+		err = fmt.Errorf("missing required attribute: %s", fieldID)
+		return
+	} else if len(eventType) == 0 {
+		eventType = WebhookEventConversionCreated
+	} else if !isInList(eventType, webhookEventTypes) {
+		err = fmt.Errorf("webhook event type %s is not valid", eventType)
+		return
+	}
+
+	// Fire the Request
+	if response, err = c.RequestWithOptions(
+		ctx, http.MethodPost,
+		fmt.Sprintf("/%s/webhook/test", modelApp),
+		map[string]interface{}{fieldID: appID, fieldEventType: eventType}, http.StatusOK,
+		withOperation("SendTestWebhook"),
+	); err != nil {
+		return
+	}
+
+	err = json.Unmarshal(response.Body, &result)
+	return
+}
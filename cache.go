@@ -0,0 +1,265 @@
+package tonicpow
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// CacheEndpoint is a group of read endpoints that share the same cache TTL
+type CacheEndpoint string
+
+const (
+	// CacheEndpointAdvertiserProfile is used for caching GetAdvertiserProfile
+	CacheEndpointAdvertiserProfile CacheEndpoint = "advertiser_profile"
+
+	// CacheEndpointApp is used for caching GetApp
+	CacheEndpointApp CacheEndpoint = "app"
+
+	// CacheEndpointCampaign is used for caching GetCampaign & GetCampaignBySlug
+	CacheEndpointCampaign CacheEndpoint = "campaign"
+
+	// CacheEndpointFeed is used for caching CampaignsFeed
+	CacheEndpointFeed CacheEndpoint = "feed"
+
+	// CacheEndpointGoal is used for caching GetGoal
+	CacheEndpointGoal CacheEndpoint = "goal"
+
+	// CacheEndpointRate is used for caching GetCurrentRate
+	CacheEndpointRate CacheEndpoint = "rate"
+
+	// headerETag is the response header holding the entity tag
+	headerETag = "ETag"
+
+	// headerIfNoneMatch is the request header used to revalidate a cached entity tag
+	headerIfNoneMatch = "If-None-Match"
+
+	// defaultCacheSize is the default maximum number of entries in the LRU cache
+	defaultCacheSize = 1000
+)
+
+// defaultCacheTTLs are the default TTLs per endpoint (used by WithCache)
+var defaultCacheTTLs = map[CacheEndpoint]time.Duration{
+	CacheEndpointAdvertiserProfile: 5 * time.Minute,
+	CacheEndpointApp:               5 * time.Minute,
+	CacheEndpointCampaign:          time.Minute,
+	CacheEndpointFeed:              5 * time.Minute,
+	CacheEndpointGoal:              time.Minute,
+	CacheEndpointRate:              30 * time.Second,
+}
+
+// Cache is used for caching the responses of read endpoints (see WithCache)
+//
+// Implementations must be safe for concurrent use. Expired entries should be kept
+// (until evicted) so they can be revalidated using the ETag.
+type Cache interface {
+	Delete(key string)
+	Get(key string) (entry *CacheEntry, ok bool)
+	Set(key string, entry *CacheEntry)
+}
+
+// CacheEntry is a cached response
+type CacheEntry struct {
+	Body      []byte    `json:"body"`       // Body of the response
+	ETag      string    `json:"etag"`       // Entity tag of the response (used for revalidation)
+	ExpiresAt time.Time `json:"expires_at"` // Entry is fresh until this time
+}
+
+// fresh will return true if the entry has not expired
+func (e *CacheEntry) fresh(now time.Time) bool {
+	return now.Before(e.ExpiresAt)
+}
+
+// LRUCache is an in-memory least recently used Cache
+type LRUCache struct {
+	entries  map[string]*list.Element
+	mu       sync.Mutex
+	order    *list.List
+	capacity int
+}
+
+// lruItem is an item in the LRU list
+type lruItem struct {
+	entry *CacheEntry
+	key   string
+}
+
+// NewLRUCache will return a new LRU cache holding up to size entries (default is 1000)
+func NewLRUCache(size int) *LRUCache {
+	if size <= 0 {
+		size = defaultCacheSize
+	}
+	return &LRUCache{
+		capacity: size,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Get will return the entry for the key (implements Cache)
+func (l *LRUCache) Get(key string) (*CacheEntry, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	element, ok := l.entries[key]
+	if !ok {
+		return nil, false
+	}
+	l.order.MoveToFront(element)
+	return element.Value.(*lruItem).entry, true
+}
+
+// Set will store the entry for the key, evicting the least recently used entry if full (implements Cache)
+func (l *LRUCache) Set(key string, entry *CacheEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if element, ok := l.entries[key]; ok {
+		element.Value.(*lruItem).entry = entry
+		l.order.MoveToFront(element)
+		return
+	}
+	l.entries[key] = l.order.PushFront(&lruItem{entry: entry, key: key})
+	if l.order.Len() > l.capacity {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.entries, oldest.Value.(*lruItem).key)
+	}
+}
+
+// Delete will remove the entry for the key (implements Cache)
+func (l *LRUCache) Delete(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if element, ok := l.entries[key]; ok {
+		l.order.Remove(element)
+		delete(l.entries, key)
+	}
+}
+
+// Len will return the number of entries in the cache
+func (l *LRUCache) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.order.Len()
+}
+
+// Cached endpoints (also used for invalidation)
+
+// advertiserProfileEndpoint will return the endpoint for GetAdvertiserProfile
+func advertiserProfileEndpoint(profileID uint64) string {
+	return fmt.Sprintf("/%s/details/%d", modelAdvertiser, profileID)
+}
+
+// appEndpoint will return the endpoint for GetApp
+func appEndpoint(appID uint64) string {
+	return fmt.Sprintf("/%s/details/%d", modelApp, appID)
+}
+
+// campaignEndpoint will return the endpoint for GetCampaign
+func campaignEndpoint(campaignID uint64) string {
+	return fmt.Sprintf("/%s/details/?%s=%d", modelCampaign, fieldID, campaignID)
+}
+
+// campaignBySlugEndpoint will return the endpoint for GetCampaignBySlug
+func campaignBySlugEndpoint(slug string) string {
+	return fmt.Sprintf("/%s/details/?%s=%s", modelCampaign, fieldSlug, url.QueryEscape(slug))
+}
+
+// campaignsFeedEndpoint will return the endpoint for CampaignsFeed
+func campaignsFeedEndpoint(feedType FeedType) string {
+	return fmt.Sprintf("/%s/feed/?%s=%s", modelCampaign, fieldFeedType, feedType)
+}
+
+// goalEndpoint will return the endpoint for GetGoal
+func goalEndpoint(goalID uint64) string {
+	return fmt.Sprintf("/%s/details/%d", modelGoal, goalID)
+}
+
+// withCacheEndpoint will cache the response of a GET request using the TTL of the endpoint
+func withCacheEndpoint(endpoint CacheEndpoint) RequestOps {
+	return func(r *requestOptions) {
+		r.cacheEndpoint = endpoint
+	}
+}
+
+// cacheKey will return the cache key and TTL for the request (empty if the request is not cached)
+func (c *Client) cacheKey(httpMethod, requestEndpoint string, options *requestOptions) (string, time.Duration) {
+	if c.options.cache == nil || len(options.cacheEndpoint) == 0 || httpMethod != http.MethodGet {
+		return "", 0
+	}
+	ttl := c.options.cacheTTLs[options.cacheEndpoint]
+	if ttl <= 0 {
+		return "", 0
+	}
+	return c.endpointCacheKey(requestEndpoint), ttl
+}
+
+// endpointCacheKey will return the cache key of the endpoint, scoped to the environment and a hash of the API key
+// (a Cache shared by clients using different API keys never serves the responses of another key)
+func (c *Client) endpointCacheKey(endpoint string) string {
+	hash := sha256.Sum256([]byte(c.options.apiKey))
+	return hex.EncodeToString(hash[:]) + ":" + c.options.env.URL() + endpoint
+}
+
+// invalidate will remove the cached responses for the endpoints
+func (c *Client) invalidate(endpoints ...string) {
+	if c.options.cache == nil {
+		return
+	}
+	for _, endpoint := range endpoints {
+		c.options.cache.Delete(c.endpointCacheKey(endpoint))
+	}
+}
+
+// invalidateCampaign will remove the cached campaign (by id & slug) and the campaign feeds
+//
+// The slug of the cached campaign (by id) is also removed, in case the slug has changed
+func (c *Client) invalidateCampaign(campaignID uint64, slug string) {
+	if c.options.cache == nil {
+		return
+	}
+	endpoints := []string{
+		campaignsFeedEndpoint(FeedTypeAtom),
+		campaignsFeedEndpoint(FeedTypeJSON),
+		campaignsFeedEndpoint(FeedTypeRSS),
+	}
+	if campaignID > 0 {
+		endpoints = append(endpoints, campaignEndpoint(campaignID))
+		if entry, ok := c.options.cache.Get(c.endpointCacheKey(campaignEndpoint(campaignID))); ok {
+			cached := new(Campaign)
+			if err := json.Unmarshal(entry.Body, cached); err == nil && len(cached.Slug) > 0 {
+				endpoints = append(endpoints, campaignBySlugEndpoint(cached.Slug))
+			}
+		}
+	}
+	if len(slug) > 0 {
+		endpoints = append(endpoints, campaignBySlugEndpoint(slug))
+	}
+	c.invalidate(endpoints...)
+}
+
+// invalidateGoal will remove the cached goal and the cached campaign of the goal (campaigns include the goals)
+//
+// If the campaign is unknown, the campaign id is taken from the cached goal (if found)
+func (c *Client) invalidateGoal(goalID, campaignID uint64) {
+	if c.options.cache == nil {
+		return
+	}
+	if campaignID == 0 {
+		if entry, ok := c.options.cache.Get(c.endpointCacheKey(goalEndpoint(goalID))); ok {
+			goal := new(Goal)
+			if err := json.Unmarshal(entry.Body, goal); err == nil {
+				campaignID = goal.CampaignID
+			}
+		}
+	}
+	c.invalidate(goalEndpoint(goalID))
+	if campaignID > 0 {
+		c.invalidateCampaign(campaignID, "")
+	}
+}
@@ -0,0 +1,400 @@
+package tonicpow
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockResponseETag is used for mocking a response with an ETag (responds 304 if the ETag matches)
+//
+// The returned func will return the number of requests and the number of 304 responses
+func mockResponseETag(method, endpoint, etag string, model interface{}) func() (int, int) {
+	data, _ := json.Marshal(model)
+	calls, notModified := 0, 0
+	httpmock.RegisterResponder(method, endpoint, func(req *http.Request) (*http.Response, error) {
+		calls++
+		if len(etag) > 0 && req.Header.Get(headerIfNoneMatch) == etag {
+			notModified++
+			return httpmock.NewStringResponse(http.StatusNotModified, ""), nil
+		}
+		resp := httpmock.NewStringResponse(http.StatusOK, string(data))
+		if len(etag) > 0 {
+			resp.Header.Set(headerETag, etag)
+		}
+		return resp, nil
+	})
+	return func() (int, int) { return calls, notModified }
+}
+
+// TestLRUCache will test the LRU cache
+func TestLRUCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("get, set and delete", func(t *testing.T) {
+		cache := NewLRUCache(0)
+		_, ok := cache.Get("key")
+		assert.False(t, ok)
+
+		cache.Set("key", &CacheEntry{Body: []byte("value")})
+		entry, ok := cache.Get("key")
+		require.True(t, ok)
+		assert.Equal(t, []byte("value"), entry.Body)
+
+		cache.Set("key", &CacheEntry{Body: []byte("updated")})
+		entry, _ = cache.Get("key")
+		assert.Equal(t, []byte("updated"), entry.Body)
+		assert.Equal(t, 1, cache.Len())
+
+		cache.Delete("key")
+		_, ok = cache.Get("key")
+		assert.False(t, ok)
+		assert.Equal(t, 0, cache.Len())
+	})
+
+	t.Run("evict least recently used", func(t *testing.T) {
+		cache := NewLRUCache(2)
+		cache.Set("first", &CacheEntry{})
+		cache.Set("second", &CacheEntry{})
+		_, _ = cache.Get("first")
+		cache.Set("third", &CacheEntry{})
+
+		assert.Equal(t, 2, cache.Len())
+		_, ok := cache.Get("second")
+		assert.False(t, ok)
+		_, ok = cache.Get("first")
+		assert.True(t, ok)
+		_, ok = cache.Get("third")
+		assert.True(t, ok)
+	})
+}
+
+// TestCacheEntry_fresh will test the method fresh()
+func TestCacheEntry_fresh(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	assert.True(t, (&CacheEntry{ExpiresAt: now.Add(time.Second)}).fresh(now))
+	assert.False(t, (&CacheEntry{ExpiresAt: now}).fresh(now))
+}
+
+// TestClient_Cache will test caching the responses of the read endpoints
+func TestClient_Cache(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	campaignURL := EnvironmentDevelopment.apiURL + campaignEndpoint(testCampaignID)
+
+	t.Run("disabled by default", func(t *testing.T) {
+		client, err := newRetryTestClient()
+		require.NoError(t, err)
+
+		httpmock.Reset()
+		calls := mockResponseETag(http.MethodGet, campaignURL, "", newTestCampaign())
+		for i := 0; i < 2; i++ {
+			var response *StandardResponse
+			_, response, err = client.GetCampaign(testCampaignID)
+			require.NoError(t, err)
+			assert.False(t, response.Cached)
+		}
+		count, _ := calls()
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("fresh response is served from the cache", func(t *testing.T) {
+		client, err := newRetryTestClient(WithCache(nil))
+		require.NoError(t, err)
+
+		httpmock.Reset()
+		calls := mockResponseETag(http.MethodGet, campaignURL, "", newTestCampaign())
+
+		var campaign *Campaign
+		var response *StandardResponse
+		_, response, err = client.GetCampaign(testCampaignID)
+		require.NoError(t, err)
+		assert.False(t, response.Cached)
+
+		campaign, response, err = client.GetCampaign(testCampaignID)
+		require.NoError(t, err)
+		assert.True(t, response.Cached)
+		assert.Equal(t, testCampaignID, campaign.ID)
+
+		count, _ := calls()
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("changing a response body does not change the cache", func(t *testing.T) {
+		client, err := newRetryTestClient(WithCache(nil))
+		require.NoError(t, err)
+
+		httpmock.Reset()
+		_ = mockResponseETag(http.MethodGet, campaignURL, "", newTestCampaign())
+
+		var response *StandardResponse
+		_, response, err = client.GetCampaign(testCampaignID)
+		require.NoError(t, err)
+		expected := string(response.Body)
+		for i := range response.Body {
+			response.Body[i] = ' '
+		}
+
+		for i := 0; i < 2; i++ {
+			_, response, err = client.GetCampaign(testCampaignID)
+			require.NoError(t, err)
+			assert.True(t, response.Cached)
+			assert.Equal(t, expected, string(response.Body))
+			for j := range response.Body {
+				response.Body[j] = ' '
+			}
+		}
+	})
+
+	t.Run("expired response is revalidated using the etag", func(t *testing.T) {
+		client, err := newRetryTestClient(WithCache(nil), WithCacheTTL(CacheEndpointCampaign, time.Nanosecond))
+		require.NoError(t, err)
+
+		httpmock.Reset()
+		calls := mockResponseETag(http.MethodGet, campaignURL, `"v1"`, newTestCampaign())
+
+		_, _, err = client.GetCampaign(testCampaignID)
+		require.NoError(t, err)
+
+		var campaign *Campaign
+		var response *StandardResponse
+		campaign, response, err = client.GetCampaign(testCampaignID)
+		require.NoError(t, err)
+		assert.True(t, response.Cached)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, testCampaignID, campaign.ID)
+
+		count, notModified := calls()
+		assert.Equal(t, 2, count)
+		assert.Equal(t, 1, notModified)
+	})
+
+	t.Run("ttl of zero disables the endpoint", func(t *testing.T) {
+		client, err := newRetryTestClient(WithCache(nil), WithCacheTTL(CacheEndpointCampaign, 0))
+		require.NoError(t, err)
+
+		httpmock.Reset()
+		calls := mockResponseETag(http.MethodGet, campaignURL, `"v1"`, newTestCampaign())
+		for i := 0; i < 2; i++ {
+			_, _, err = client.GetCampaign(testCampaignID)
+			require.NoError(t, err)
+		}
+		count, notModified := calls()
+		assert.Equal(t, 2, count)
+		assert.Equal(t, 0, notModified)
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		client, err := newRetryTestClient(WithCache(nil), WithRetryCount(0))
+		require.NoError(t, err)
+
+		calls := mockResponseSequence(http.MethodGet, campaignURL, nil, http.StatusNotFound)
+		for i := 0; i < 2; i++ {
+			_, _, err = client.GetCampaign(testCampaignID)
+			assert.ErrorIs(t, err, ErrNotFound)
+		}
+		assert.Equal(t, 2, calls())
+	})
+
+	t.Run("update campaign invalidates the campaign and feeds", func(t *testing.T) {
+		cache := NewLRUCache(10)
+		client, err := newRetryTestClient(WithCache(cache))
+		require.NoError(t, err)
+
+		campaign := newTestCampaign()
+		httpmock.Reset()
+		campaignCalls := mockResponseETag(http.MethodGet, campaignURL, "", campaign)
+		slugCalls := mockResponseETag(
+			http.MethodGet, EnvironmentDevelopment.apiURL+campaignBySlugEndpoint(campaign.Slug), "", campaign,
+		)
+		feedCalls := mockResponseETag(
+			http.MethodGet, EnvironmentDevelopment.apiURL+campaignsFeedEndpoint(FeedTypeRSS), "", "<rss></rss>",
+		)
+		mockResponseETag(http.MethodPut, EnvironmentDevelopment.apiURL+"/"+modelCampaign, "", campaign)
+
+		for i := 0; i < 2; i++ {
+			_, _, err = client.GetCampaign(testCampaignID)
+			require.NoError(t, err)
+			_, _, err = client.GetCampaignBySlug(campaign.Slug)
+			require.NoError(t, err)
+			_, _, err = client.CampaignsFeed(FeedTypeRSS)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 3, cache.Len())
+
+		_, err = client.UpdateCampaign(newTestCampaign())
+		require.NoError(t, err)
+		assert.Equal(t, 0, cache.Len())
+
+		_, _, err = client.GetCampaign(testCampaignID)
+		require.NoError(t, err)
+		count, _ := campaignCalls()
+		assert.Equal(t, 2, count)
+		count, _ = slugCalls()
+		assert.Equal(t, 1, count)
+		count, _ = feedCalls()
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("goal changes invalidate the goal and campaign", func(t *testing.T) {
+		cache := NewLRUCache(10)
+		client, err := newRetryTestClient(WithCache(cache))
+		require.NoError(t, err)
+
+		httpmock.Reset()
+		mockResponseETag(http.MethodGet, campaignURL, "", newTestCampaign())
+		mockResponseETag(http.MethodGet, EnvironmentDevelopment.apiURL+goalEndpoint(testGoalID), "", newTestGoal())
+		mockResponseETag(http.MethodPut, EnvironmentDevelopment.apiURL+"/"+modelGoal, "", newTestGoal())
+		mockResponseETag(
+			http.MethodDelete, fmt.Sprintf("%s/%s?%s=%d", EnvironmentDevelopment.apiURL, modelGoal, fieldID, testGoalID),
+			"", nil,
+		)
+
+		// Update goal
+		_, _, err = client.GetCampaign(testCampaignID)
+		require.NoError(t, err)
+		_, _, err = client.GetGoal(testGoalID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, cache.Len())
+		_, err = client.UpdateGoal(newTestGoal())
+		require.NoError(t, err)
+		assert.Equal(t, 0, cache.Len())
+
+		// Delete goal (campaign is found using the cached goal)
+		_, _, err = client.GetCampaign(testCampaignID)
+		require.NoError(t, err)
+		_, _, err = client.GetGoal(testGoalID)
+		require.NoError(t, err)
+		_, _, err = client.DeleteGoal(testGoalID)
+		require.NoError(t, err)
+		assert.Equal(t, 0, cache.Len())
+	})
+
+	t.Run("patches invalidate the cache even if the response cannot be parsed", func(t *testing.T) {
+		cache := NewLRUCache(10)
+		client, err := newRetryTestClient(WithCache(cache))
+		require.NoError(t, err)
+
+		httpmock.Reset()
+		mockResponseETag(http.MethodGet, campaignURL, "", newTestCampaign())
+		mockResponseETag(http.MethodGet, EnvironmentDevelopment.apiURL+goalEndpoint(testGoalID), "", newTestGoal())
+
+		for _, body := range []string{"null", "not json"} {
+			httpmock.RegisterResponder(http.MethodPut, EnvironmentDevelopment.apiURL+"/"+modelCampaign,
+				httpmock.NewStringResponder(http.StatusOK, body))
+			httpmock.RegisterResponder(http.MethodPut, EnvironmentDevelopment.apiURL+"/"+modelGoal,
+				httpmock.NewStringResponder(http.StatusOK, body))
+
+			// Patch campaign
+			_, _, err = client.GetCampaign(testCampaignID)
+			require.NoError(t, err)
+			assert.Equal(t, 1, cache.Len())
+			var campaign *Campaign
+			campaign, _, err = client.PatchCampaign(testCampaignID, ChangeSet{}.Set("title", "test"))
+			assert.Equal(t, body != "null", err != nil, body)
+			assert.Nil(t, campaign)
+			assert.Equal(t, 0, cache.Len(), body)
+
+			// Patch goal (campaign is found using the cached goal)
+			_, _, err = client.GetCampaign(testCampaignID)
+			require.NoError(t, err)
+			_, _, err = client.GetGoal(testGoalID)
+			require.NoError(t, err)
+			assert.Equal(t, 2, cache.Len())
+			var goal *Goal
+			goal, _, err = client.PatchGoal(testGoalID, ChangeSet{}.Set("title", "test"))
+			assert.Equal(t, body != "null", err != nil, body)
+			assert.Nil(t, goal)
+			assert.Equal(t, 0, cache.Len(), body)
+		}
+	})
+
+	t.Run("update advertiser profile invalidates the profile", func(t *testing.T) {
+		cache := NewLRUCache(10)
+		client, err := newRetryTestClient(WithCache(cache))
+		require.NoError(t, err)
+
+		profile := newTestAdvertiserProfile()
+		httpmock.Reset()
+		mockResponseETag(
+			http.MethodGet, EnvironmentDevelopment.apiURL+advertiserProfileEndpoint(testAdvertiserID), "", profile,
+		)
+		mockResponseETag(http.MethodPut, EnvironmentDevelopment.apiURL+"/"+modelAdvertiser, "", profile)
+
+		_, _, err = client.GetAdvertiserProfile(testAdvertiserID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, cache.Len())
+		_, err = client.UpdateAdvertiserProfile(newTestAdvertiserProfile())
+		require.NoError(t, err)
+		assert.Equal(t, 0, cache.Len())
+	})
+
+	t.Run("shared cache is scoped by api key", func(t *testing.T) {
+		cache := NewLRUCache(10)
+		first, err := newRetryTestClient(WithAPIKey("first-api-key"), WithCache(cache))
+		require.NoError(t, err)
+		var second ClientInterface
+		second, err = newRetryTestClient(WithAPIKey("second-api-key"), WithCache(cache))
+		require.NoError(t, err)
+
+		// The profile returned depends on the api key
+		httpmock.Reset()
+		calls := 0
+		httpmock.RegisterResponder(http.MethodGet,
+			EnvironmentDevelopment.apiURL+advertiserProfileEndpoint(testAdvertiserID),
+			func(req *http.Request) (*http.Response, error) {
+				calls++
+				profile := newTestAdvertiserProfile()
+				profile.Name = req.Header.Get(fieldAPIKey)
+				return httpmock.NewJsonResponse(http.StatusOK, profile)
+			},
+		)
+		mockResponseETag(http.MethodPut, EnvironmentDevelopment.apiURL+"/"+modelAdvertiser, "", newTestAdvertiserProfile())
+
+		for _, client := range []ClientInterface{first, second, first, second} {
+			profile, _, getErr := client.GetAdvertiserProfile(testAdvertiserID)
+			require.NoError(t, getErr)
+			assert.Equal(t, client.Options().apiKey, profile.Name)
+		}
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, 2, cache.Len())
+
+		// Only the cached profile of the api key is removed
+		_, err = first.UpdateAdvertiserProfile(newTestAdvertiserProfile())
+		require.NoError(t, err)
+		assert.Equal(t, 1, cache.Len())
+		var response *StandardResponse
+		_, response, err = second.GetAdvertiserProfile(testAdvertiserID)
+		require.NoError(t, err)
+		assert.True(t, response.Cached)
+	})
+}
+
+// TestNewClient_CacheOptions will test the cache options of NewClient()
+func TestNewClient_CacheOptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("default ttls", func(t *testing.T) {
+		client, err := NewClient(WithAPIKey(testAPIKey))
+		require.NoError(t, err)
+		assert.Nil(t, client.Options().cache)
+		assert.Equal(t, defaultCacheTTLs, client.Options().cacheTTLs)
+	})
+
+	t.Run("custom cache and ttl", func(t *testing.T) {
+		cache := NewLRUCache(5)
+		client, err := NewClient(WithAPIKey(testAPIKey), WithCacheTTL(CacheEndpointRate, time.Second), WithCache(cache))
+		require.NoError(t, err)
+		assert.Equal(t, cache, client.Options().cache)
+		assert.Equal(t, time.Second, client.Options().cacheTTLs[CacheEndpointRate])
+		assert.Equal(t, defaultCacheTTLs[CacheEndpointGoal], client.Options().cacheTTLs[CacheEndpointGoal])
+	})
+}
@@ -0,0 +1,378 @@
+package tonicpow
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// slugPattern is the pattern of a valid campaign slug (lowercase letters and digits, separated by single dashes)
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+// countryCodes are the ISO 3166-1 alpha-2 country codes
+var countryCodes = strings.Fields(`
+	AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ BA BB BD BE BF BG BH BI BJ BL BM BN BO BQ BR BS
+	BT BV BW BY BZ CA CC CD CF CG CH CI CK CL CM CN CO CR CU CV CW CX CY CZ DE DJ DK DM DO DZ EC EE
+	EG EH ER ES ET FI FJ FK FM FO FR GA GB GD GE GF GG GH GI GL GM GN GP GQ GR GS GT GU GW GY HK HM
+	HN HR HT HU ID IE IL IM IN IO IQ IR IS IT JE JM JO JP KE KG KH KI KM KN KP KR KW KY KZ LA LB LC
+	LI LK LR LS LT LU LV LY MA MC MD ME MF MG MH MK ML MM MN MO MP MQ MR MS MT MU MV MW MX MY MZ NA
+	NC NE NF NG NI NL NO NP NR NU NZ OM PA PE PF PG PH PK PL PM PN PR PS PT PW PY QA RE RO RS RU RW
+	SA SB SC SD SE SG SH SI SJ SK SL SM SN SO SR SS ST SV SX SY SZ TC TD TF TG TH TJ TK TL TM TN TO
+	TR TT TV TW TZ UA UG UM US UY UZ VA VC VE VG VI VN VU WF WS YE YT ZA ZM ZW
+`)
+
+// FieldError is a problem with a field of a campaign (see Campaign.Validate)
+type FieldError struct {
+	Field   string // The field (json name, goals[0].payout_rate, etc.)
+	Message string // The problem
+}
+
+// Error will return the field and the problem (implements the error interface)
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationErrors is every problem found when validating a campaign (see Campaign.Validate)
+//
+// errors.Is(err, ErrValidation) is true for ValidationErrors
+type ValidationErrors []*FieldError
+
+// Error will return all the problems (implements the error interface)
+func (v ValidationErrors) Error() string {
+	messages := make([]string, 0, len(v))
+	for _, e := range v {
+		messages = append(messages, e.Error())
+	}
+	return ErrValidation.Error() + ": " + strings.Join(messages, "; ")
+}
+
+// Is will return true for ErrValidation
+func (v ValidationErrors) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// Unwrap will return the problems (as errors)
+func (v ValidationErrors) Unwrap() []error {
+	errs := make([]error, 0, len(v))
+	for _, e := range v {
+		errs = append(errs, e)
+	}
+	return errs
+}
+
+// Fields will return the fields with problems (in order)
+func (v ValidationErrors) Fields() []string {
+	fields := make([]string, 0, len(v))
+	for _, e := range v {
+		fields = append(fields, e.Field)
+	}
+	return fields
+}
+
+// add will add a problem with the field
+func (v *ValidationErrors) add(field, format string, args ...interface{}) {
+	*v = append(*v, &FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Validate will check the campaign before creating it and return ValidationErrors listing every problem found
+//
+// The required fields (advertiser profile, title, description and target) are checked, as well as:
+// the target and image URLs, the slug format, ExpiresAt (in the future), the visitor countries
+// (ISO 3166-1 alpha-2 codes), the rates (BalanceAlertThreshold must be at least PayPerClickRate),
+// the payout mode and the goals
+func (c *Campaign) Validate() error {
+	return c.validateAt(time.Now())
+}
+
+// validateAt will validate the campaign at the given time (see Validate)
+func (c *Campaign) validateAt(now time.Time) error {
+	var errs ValidationErrors
+
+	// Basic requirements
+	if c.AdvertiserProfileID == 0 {
+		errs.add(fieldAdvertiserProfileID, "is required")
+	}
+	if len(strings.TrimSpace(c.Title)) == 0 {
+		errs.add(fieldTitle, "is required")
+	}
+	if len(strings.TrimSpace(c.Description)) == 0 {
+		errs.add(fieldDescription, "is required")
+	}
+
+	// Target
+	switch c.TargetType {
+	case TargetTypeURL:
+		if len(c.TargetURL) == 0 {
+			errs.add(fieldTargetURL, "is required for target type %s", TargetTypeURL)
+		} else if err := validateURL(c.TargetURL); err != nil {
+			errs.add(fieldTargetURL, "%s", err.Error())
+		}
+	case TargetTypeHosted:
+		if len(c.TargetData) == 0 {
+			errs.add(fieldTargetData, "is required for target type %s", TargetTypeHosted)
+		}
+	case "":
+		errs.add(fieldTargetType, "is required")
+	default:
+		errs.add(fieldTargetType, "must be %s or %s, got %q", TargetTypeURL, TargetTypeHosted, c.TargetType)
+	}
+
+	// Optional fields
+	if len(c.ImageURL) > 0 {
+		if err := validateURL(c.ImageURL); err != nil {
+			errs.add(fieldImageURL, "%s", err.Error())
+		}
+	}
+	if len(c.Slug) > 0 && !slugPattern.MatchString(c.Slug) {
+		errs.add(fieldSlug, "must be lowercase letters and digits separated by dashes, got %q", c.Slug)
+	}
+	if len(c.ExpiresAt) > 0 {
+		if expiresAt, err := parseTimestamp(c.ExpiresAt); err != nil {
+			errs.add(fieldExpiresAt, "must be formatted as %s or RFC3339, got %q", timestampLayout, c.ExpiresAt)
+		} else if !expiresAt.After(now) {
+			errs.add(fieldExpiresAt, "must be in the future, got %s", c.ExpiresAt)
+		}
+	}
+
+	// Requirements
+	if c.Requirements != nil {
+		for i, country := range c.Requirements.VisitorCountries {
+			if !isInList(country, countryCodes) {
+				errs.add(fmt.Sprintf("%s[%d]", fieldVisitorCountries, i),
+					"must be an ISO 3166-1 alpha-2 code (US, GB, etc.), got %q", country)
+			}
+		}
+		if c.Requirements.VisitorRestrictions && len(c.Requirements.VisitorCountries) == 0 {
+			errs.add(fieldVisitorCountries, "is required when visitor restrictions are enabled")
+		}
+	}
+
+	// Rates
+	if c.PayPerClickRate.Sign() < 0 {
+		errs.add(fieldPayPerClickRate, "must not be negative")
+	}
+	if c.BalanceAlertThreshold.Sign() < 0 {
+		errs.add(fieldBalanceAlert, "must not be negative")
+	} else if c.BalanceAlertThreshold.Sign() > 0 && c.BalanceAlertThreshold.Cmp(c.PayPerClickRate) < 0 {
+		errs.add(fieldBalanceAlert, "must be at least the %s (%s), got %s",
+			fieldPayPerClickRate, c.PayPerClickRate.String(), c.BalanceAlertThreshold.String())
+	}
+
+	// Payouts and goals
+	switch c.PayoutMode {
+	case PayoutModeClicksAndGoals:
+	case PayoutModeGoalsOnly:
+		if len(c.Goals) == 0 {
+			errs.add(fieldGoals, "at least one goal is required for payout mode %d (goals only)", c.PayoutMode)
+		}
+	default:
+		errs.add(fieldPayoutMode, "must be %d (clicks and goals) or %d (goals only), got %d",
+			PayoutModeClicksAndGoals, PayoutModeGoalsOnly, c.PayoutMode)
+	}
+	names := make(map[string]bool, len(c.Goals))
+	for i, goal := range c.Goals {
+		field := fmt.Sprintf("%s[%d]", fieldGoals, i)
+		if goal == nil {
+			errs.add(field, "must not be empty")
+			continue
+		}
+		goal.validate(field, names, &errs)
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validate will add the problems with the goal (of a campaign) to the errors
+func (g *Goal) validate(field string, names map[string]bool, errs *ValidationErrors) {
+	if len(g.Name) == 0 {
+		errs.add(field+"."+fieldName, "is required")
+	} else if names[g.Name] {
+		errs.add(field+"."+fieldName, "must be unique, %q is used by another goal", g.Name)
+	}
+	names[g.Name] = true
+
+	switch g.PayoutType {
+	case PayoutTypeFlat:
+	case PayoutTypePercent:
+		if g.PayoutRate.Cmp(NewDecimal(100, 0)) > 0 {
+			errs.add(field+"."+fieldPayoutRate, "must be at most 100 for payout type %s", PayoutTypePercent)
+		}
+	default:
+		errs.add(field+"."+fieldPayoutType, "must be %s or %s, got %q", PayoutTypeFlat, PayoutTypePercent, g.PayoutType)
+	}
+	if g.PayoutRate.Sign() <= 0 {
+		errs.add(field+"."+fieldPayoutRate, "must be greater than zero")
+	}
+	if g.MaxPerPromoter < 0 {
+		errs.add(field+"."+fieldMaxPerPromoter, "must not be negative")
+	}
+	if g.MaxPerVisitor < 0 {
+		errs.add(field+"."+fieldMaxPerVisitor, "must not be negative")
+	}
+}
+
+// validateURL will check that the value is an absolute http(s) URL
+func validateURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		return fmt.Errorf("must be an absolute http(s) URL, got %q", value)
+	}
+	return nil
+}
+
+// CampaignBuilder builds a campaign and validates it (see Campaign.Validate) before it's created
+//
+// Example: NewCampaignBuilder(profileID).Title("...").Description("...").TargetURL("https://...").Build()
+type CampaignBuilder struct {
+	campaign *Campaign
+}
+
+// NewCampaignBuilder will start a new campaign for the advertiser profile
+func NewCampaignBuilder(advertiserProfileID uint64) *CampaignBuilder {
+	return &CampaignBuilder{campaign: &Campaign{AdvertiserProfileID: advertiserProfileID}}
+}
+
+// Title will set the title
+func (b *CampaignBuilder) Title(title string) *CampaignBuilder {
+	b.campaign.Title = title
+	return b
+}
+
+// Description will set the description
+func (b *CampaignBuilder) Description(description string) *CampaignBuilder {
+	b.campaign.Description = description
+	return b
+}
+
+// Slug will set the slug (lowercase letters and digits separated by dashes)
+func (b *CampaignBuilder) Slug(slug string) *CampaignBuilder {
+	b.campaign.Slug = slug
+	return b
+}
+
+// TargetURL will target the URL (TargetTypeURL)
+func (b *CampaignBuilder) TargetURL(targetURL string) *CampaignBuilder {
+	b.campaign.TargetType, b.campaign.TargetURL, b.campaign.TargetData = TargetTypeURL, targetURL, ""
+	return b
+}
+
+// HostedTarget will target content hosted by TonicPow (TargetTypeHosted)
+func (b *CampaignBuilder) HostedTarget(data string) *CampaignBuilder {
+	b.campaign.TargetType, b.campaign.TargetData, b.campaign.TargetURL = TargetTypeHosted, data, ""
+	return b
+}
+
+// ImageURL will set the image URL
+func (b *CampaignBuilder) ImageURL(imageURL string) *CampaignBuilder {
+	b.campaign.ImageURL = imageURL
+	return b
+}
+
+// Currency will set the currency of the rates
+func (b *CampaignBuilder) Currency(currency Currency) *CampaignBuilder {
+	b.campaign.Currency = currency
+	return b
+}
+
+// PayPerClickRate will set the rate paid per click
+func (b *CampaignBuilder) PayPerClickRate(rate Decimal) *CampaignBuilder {
+	b.campaign.PayPerClickRate = rate
+	return b
+}
+
+// BalanceAlertThreshold will set the balance to send an alert at (at least the PayPerClickRate)
+func (b *CampaignBuilder) BalanceAlertThreshold(threshold Decimal) *CampaignBuilder {
+	b.campaign.BalanceAlertThreshold = threshold
+	return b
+}
+
+// PayoutMode will set the payout mode
+func (b *CampaignBuilder) PayoutMode(mode PayoutMode) *CampaignBuilder {
+	b.campaign.PayoutMode = mode
+	return b
+}
+
+// ExpiresAt will set when the campaign expires
+func (b *CampaignBuilder) ExpiresAt(expiresAt time.Time) *CampaignBuilder {
+	b.campaign.ExpiresAt = expiresAt.UTC().Format(timestampLayout)
+	return b
+}
+
+// VisitorCountries will restrict the visitors to the countries (ISO 3166-1 alpha-2 codes)
+func (b *CampaignBuilder) VisitorCountries(countries ...string) *CampaignBuilder {
+	requirements := b.requirements()
+	requirements.VisitorCountries, requirements.VisitorRestrictions = countries, len(countries) > 0
+	return b
+}
+
+// Requirements will set the requirements (replacing any visitor countries already set)
+func (b *CampaignBuilder) Requirements(requirements CampaignRequirements) *CampaignBuilder {
+	b.campaign.Requirements = &requirements
+	return b
+}
+
+// BotProtection will enable or disable the bot protection
+func (b *CampaignBuilder) BotProtection(enabled bool) *CampaignBuilder {
+	b.campaign.BotProtection = enabled
+	return b
+}
+
+// MatchDomain will enable or disable matching the domain of the target URL
+func (b *CampaignBuilder) MatchDomain(enabled bool) *CampaignBuilder {
+	b.campaign.MatchDomain = enabled
+	return b
+}
+
+// Unlisted will hide or show the campaign in the listings
+func (b *CampaignBuilder) Unlisted(unlisted bool) *CampaignBuilder {
+	b.campaign.Unlisted = unlisted
+	return b
+}
+
+// Goal will add a goal paying the rate (PayoutTypeFlat or PayoutTypePercent)
+func (b *CampaignBuilder) Goal(name string, payoutType PayoutType, rate Decimal) *CampaignBuilder {
+	return b.AddGoal(&Goal{Name: name, PayoutType: payoutType, PayoutRate: rate})
+}
+
+// AddGoal will add the goal
+func (b *CampaignBuilder) AddGoal(goal *Goal) *CampaignBuilder {
+	b.campaign.Goals = append(b.campaign.Goals, goal)
+	return b
+}
+
+// Build will validate the campaign and return it, or return ValidationErrors listing every problem
+//
+// The campaign returned is a copy (including the goals and requirements),
+// changing the builder afterwards does not change the campaigns already built
+func (b *CampaignBuilder) Build() (*Campaign, error) {
+	if err := b.campaign.Validate(); err != nil {
+		return nil, err
+	}
+	campaign := *b.campaign
+	if b.campaign.Goals != nil {
+		campaign.Goals = make([]*Goal, 0, len(b.campaign.Goals))
+		for _, goal := range b.campaign.Goals {
+			goalCopy := *goal
+			campaign.Goals = append(campaign.Goals, &goalCopy)
+		}
+	}
+	if b.campaign.Requirements != nil {
+		requirements := *b.campaign.Requirements
+		requirements.VisitorCountries = append([]string(nil), requirements.VisitorCountries...)
+		campaign.Requirements = &requirements
+	}
+	return &campaign, nil
+}
+
+// requirements will return the requirements of the campaign (created if not set)
+func (b *CampaignBuilder) requirements() *CampaignRequirements {
+	if b.campaign.Requirements == nil {
+		b.campaign.Requirements = new(CampaignRequirements)
+	}
+	return b.campaign.Requirements
+}
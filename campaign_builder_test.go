@@ -0,0 +1,228 @@
+package tonicpow
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestCampaignBuilder will return a builder for a valid campaign
+func newTestCampaignBuilder() *CampaignBuilder {
+	return NewCampaignBuilder(testAdvertiserID).
+		Title("TonicPow").
+		Description("This is a test campaign").
+		Slug("tonicpow-2").
+		TargetURL("https://tonicpow.com").
+		ImageURL("https://res.cloudinary.com/tonicpow/image/upload/v1611266301/test.jpg").
+		PayPerClickRate(NewDecimal(1, 0)).
+		BalanceAlertThreshold(NewDecimal(5, 0)).
+		ExpiresAt(time.Now().Add(24*time.Hour)).
+		VisitorCountries("US", "GB").
+		Goal("purchase", PayoutTypePercent, NewDecimal(10, 0))
+}
+
+// TestCampaign_Validate will test the method Validate()
+func TestCampaign_Validate(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("valid campaigns", func(t *testing.T) {
+		assert.NoError(t, newTestCampaign().validateAt(now))
+
+		campaign := newTestCampaign()
+		campaign.TargetType, campaign.TargetURL, campaign.TargetData = TargetTypeHosted, "", "<p>hosted</p>"
+		campaign.ExpiresAt = "2021-01-02 00:00:00"
+		campaign.PayoutMode = PayoutModeGoalsOnly
+		assert.NoError(t, campaign.validateAt(now))
+
+		campaign.ExpiresAt = "2021-01-02T00:00:00Z"
+		assert.NoError(t, campaign.validateAt(now))
+	})
+
+	t.Run("every problem is listed", func(t *testing.T) {
+		campaign := &Campaign{
+			TargetType:            TargetTypeURL,
+			TargetURL:             "tonicpow.com",
+			ImageURL:              "ftp://tonicpow.com/image.jpg",
+			Slug:                  "Not A Slug",
+			ExpiresAt:             "2020-12-31 23:59:59",
+			PayPerClickRate:       NewDecimal(5, 0),
+			BalanceAlertThreshold: NewDecimal(1, 0),
+			PayoutMode:            PayoutModeGoalsOnly,
+			Requirements:          &CampaignRequirements{VisitorCountries: []string{"US", "usa", "XX"}},
+		}
+		err := campaign.validateAt(now)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrValidation)
+
+		var errs ValidationErrors
+		require.True(t, errors.As(err, &errs))
+		assert.Equal(t, []string{
+			fieldAdvertiserProfileID, fieldTitle, fieldDescription, fieldTargetURL, fieldImageURL, fieldSlug,
+			fieldExpiresAt, fieldVisitorCountries + "[1]", fieldVisitorCountries + "[2]", fieldBalanceAlert, fieldGoals,
+		}, errs.Fields())
+		assert.Contains(t, err.Error(), `visitor_countries[1]: must be an ISO 3166-1 alpha-2 code (US, GB, etc.), got "usa"`)
+	})
+
+	t.Run("target", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			campaign *Campaign
+			field    string
+		}{
+			{"missing target type", &Campaign{}, fieldTargetType},
+			{"invalid target type", &Campaign{TargetType: "page"}, fieldTargetType},
+			{"missing target url", &Campaign{TargetType: TargetTypeURL}, fieldTargetURL},
+			{"relative target url", &Campaign{TargetType: TargetTypeURL, TargetURL: "/campaign"}, fieldTargetURL},
+			{"missing target data", &Campaign{TargetType: TargetTypeHosted}, fieldTargetData},
+		}
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				var errs ValidationErrors
+				require.True(t, errors.As(test.campaign.validateAt(now), &errs))
+				assert.Contains(t, errs.Fields(), test.field)
+			})
+		}
+	})
+
+	t.Run("expires at", func(t *testing.T) {
+		campaign := newTestCampaign()
+		campaign.ExpiresAt = "tomorrow"
+		var errs ValidationErrors
+		require.True(t, errors.As(campaign.validateAt(now), &errs))
+		assert.Equal(t, []string{fieldExpiresAt}, errs.Fields())
+	})
+
+	t.Run("visitor restrictions require countries", func(t *testing.T) {
+		campaign := newTestCampaign()
+		campaign.Requirements = &CampaignRequirements{VisitorRestrictions: true}
+		var errs ValidationErrors
+		require.True(t, errors.As(campaign.validateAt(now), &errs))
+		assert.Equal(t, []string{fieldVisitorCountries}, errs.Fields())
+	})
+
+	t.Run("rates", func(t *testing.T) {
+		campaign := newTestCampaign()
+		campaign.PayPerClickRate = NewDecimal(-1, 0)
+		campaign.BalanceAlertThreshold = NewDecimal(-1, 0)
+		campaign.PayoutMode = 2
+		var errs ValidationErrors
+		require.True(t, errors.As(campaign.validateAt(now), &errs))
+		assert.Equal(t, []string{fieldPayPerClickRate, fieldBalanceAlert, fieldPayoutMode}, errs.Fields())
+
+		campaign = newTestCampaign()
+		campaign.BalanceAlertThreshold = campaign.PayPerClickRate
+		assert.NoError(t, campaign.validateAt(now))
+	})
+
+	t.Run("goals", func(t *testing.T) {
+		campaign := newTestCampaign()
+		campaign.Goals = []*Goal{
+			{Name: "purchase", PayoutType: PayoutTypeFlat, PayoutRate: NewDecimal(1, 0)},
+			{Name: "purchase", PayoutType: PayoutTypePercent, PayoutRate: NewDecimal(101, 0)},
+			{PayoutType: "fixed", MaxPerPromoter: -1, MaxPerVisitor: -1},
+			nil,
+		}
+		var errs ValidationErrors
+		require.True(t, errors.As(campaign.validateAt(now), &errs))
+		assert.Equal(t, []string{
+			"goals[1].name", "goals[1].payout_rate",
+			"goals[2].name", "goals[2].payout_type", "goals[2].payout_rate",
+			"goals[2].max_per_promoter", "goals[2].max_per_visitor",
+			"goals[3]",
+		}, errs.Fields())
+	})
+}
+
+// TestCampaignBuilder_Build will test the method Build()
+func TestCampaignBuilder_Build(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid campaign", func(t *testing.T) {
+		campaign, err := newTestCampaignBuilder().Build()
+		require.NoError(t, err)
+		require.NotNil(t, campaign)
+		assert.Equal(t, uint64(testAdvertiserID), campaign.AdvertiserProfileID)
+		assert.Equal(t, TargetTypeURL, campaign.TargetType)
+		assert.Equal(t, []string{"US", "GB"}, campaign.Requirements.VisitorCountries)
+		assert.True(t, campaign.Requirements.VisitorRestrictions)
+		require.Len(t, campaign.Goals, 1)
+		assert.Equal(t, PayoutTypePercent, campaign.Goals[0].PayoutType)
+	})
+
+	t.Run("built campaigns are not changed by the builder", func(t *testing.T) {
+		builder := newTestCampaignBuilder()
+		first, err := builder.Build()
+		require.NoError(t, err)
+
+		builder.Goal("signup", PayoutTypeFlat, NewDecimal(1, 0)).VisitorCountries("CA")
+		first.Goals[0].Name = "changed"
+		first.Requirements.VisitorCountries[0] = "FR"
+
+		var second *Campaign
+		second, err = builder.Build()
+		require.NoError(t, err)
+		require.Len(t, first.Goals, 1)
+		assert.Equal(t, []string{"FR", "GB"}, first.Requirements.VisitorCountries)
+
+		require.Len(t, second.Goals, 2)
+		assert.Equal(t, "purchase", second.Goals[0].Name)
+		assert.Equal(t, []string{"CA"}, second.Requirements.VisitorCountries)
+	})
+
+	t.Run("hosted target replaces the url", func(t *testing.T) {
+		campaign, err := newTestCampaignBuilder().HostedTarget("<p>hosted</p>").Build()
+		require.NoError(t, err)
+		assert.Equal(t, TargetTypeHosted, campaign.TargetType)
+		assert.Empty(t, campaign.TargetURL)
+	})
+
+	t.Run("expires at is formatted in utc", func(t *testing.T) {
+		expiresAt := time.Now().Add(48 * time.Hour).In(time.FixedZone("EST", -5*60*60))
+		campaign, err := newTestCampaignBuilder().ExpiresAt(expiresAt).Build()
+		require.NoError(t, err)
+		assert.Equal(t, expiresAt.UTC().Format(timestampLayout), campaign.ExpiresAt)
+	})
+
+	t.Run("invalid campaign", func(t *testing.T) {
+		campaign, err := NewCampaignBuilder(0).
+			Slug("-bad-").
+			TargetURL("https://").
+			ExpiresAt(time.Now().Add(-time.Hour)).
+			VisitorCountries("ZZ").
+			PayoutMode(PayoutModeGoalsOnly).
+			Build()
+		assert.Nil(t, campaign)
+		assert.ErrorIs(t, err, ErrValidation)
+
+		var errs ValidationErrors
+		require.True(t, errors.As(err, &errs))
+		assert.Equal(t, []string{
+			fieldAdvertiserProfileID, fieldTitle, fieldDescription, fieldTargetURL,
+			fieldSlug, fieldExpiresAt, fieldVisitorCountries + "[0]", fieldGoals,
+		}, errs.Fields())
+	})
+}
+
+// ExampleCampaignBuilder_Build example using Build()
+//
+// See more examples in /examples/
+func ExampleCampaignBuilder_Build() {
+	_, err := NewCampaignBuilder(testAdvertiserID).
+		Title("TonicPow").
+		Description("This is a test campaign").
+		TargetURL("tonicpow.com").
+		Slug("TonicPow").
+		Build()
+	if err != nil {
+		fmt.Printf("error building campaign: %s", err.Error())
+		return
+	}
+	fmt.Print("campaign is valid")
+	// Output:error building campaign: validation failed: target_url: must be an absolute http(s) URL, got "tonicpow.com"; slug: must be lowercase letters and digits separated by dashes, got "TonicPow"
+}
@@ -1,10 +1,10 @@
 package tonicpow
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"strings"
 )
 
 // permitFields will remove fields that cannot be used
@@ -13,9 +13,16 @@ func (c *Campaign) permitFields() {
 }
 
 // CreateCampaign will make a new campaign for the associated advertiser profile
+// Use WithIdempotencyKey() to safely retry the request without creating duplicate campaigns
 //
 // For more information: https://docs.tonicpow.com/#b67e92bf-a481-44f6-a31d-26e6e0c521b1
-func (c *Client) CreateCampaign(campaign *Campaign) (*StandardResponse, error) {
+func (c *Client) CreateCampaign(campaign *Campaign, opts ...RequestOps) (*StandardResponse, error) {
+	return c.CreateCampaignWithContext(context.Background(), campaign, opts...)
+}
+
+// CreateCampaignWithContext is the same as CreateCampaign but uses the given context
+func (c *Client) CreateCampaignWithContext(ctx context.Context, campaign *Campaign,
+	opts ...RequestOps) (*StandardResponse, error) {
 
 	// Basic requirements
 	if campaign.AdvertiserProfileID == 0 {
@@ -26,19 +33,21 @@ func (c *Client) CreateCampaign(campaign *Campaign) (*StandardResponse, error) {
 		return nil, fmt.Errorf("missing required attribute: %s", fieldDescription)
 	} else if len(campaign.TargetType) == 0 {
 		return nil, fmt.Errorf("missing required attribute: %s", fieldTargetType)
-	} else if campaign.TargetType == "url" && len(campaign.TargetURL) == 0 {
+	} else if campaign.TargetType == TargetTypeURL && len(campaign.TargetURL) == 0 {
 		return nil, fmt.Errorf("missing required attribute: %s", fieldTargetURL)
-	} else if campaign.TargetType == "hosted" && len(campaign.TargetData) == 0 {
+	} else if campaign.TargetType == TargetTypeHosted && len(campaign.TargetData) == 0 {
 		return nil, fmt.Errorf("missing required attribute: %s", fieldTargetData)
 	}
 
 	// Fire the Request
 	var response *StandardResponse
 	var err error
-	if response, err = c.Request(
-		http.MethodPost,
+	if response, err = c.RequestWithOptions(
+		ctx, http.MethodPost,
 		"/"+modelCampaign,
-		campaign, http.StatusCreated,
+		campaign, http.StatusCreated, append([]RequestOps{
+			withOperation("CreateCampaign"), withCallIDs(CallIDs{AdvertiserProfileID: campaign.AdvertiserProfileID}),
+		}, opts...)...,
 	); err != nil {
 		return response, err
 	}
@@ -53,6 +62,12 @@ func (c *Client) CreateCampaign(campaign *Campaign) (*StandardResponse, error) {
 // For more information: https://docs.tonicpow.com/#b827446b-be34-4678-b347-33c4f63dbf9e
 func (c *Client) GetCampaign(campaignID uint64) (campaign *Campaign,
 	response *StandardResponse, err error) {
+	return c.GetCampaignWithContext(context.Background(), campaignID)
+}
+
+// GetCampaignWithContext is the same as GetCampaign but uses the given context
+func (c *Client) GetCampaignWithContext(ctx context.Context, campaignID uint64) (campaign *Campaign,
+	response *StandardResponse, err error) {
 
 	// Must have an ID
 	if campaignID == 0 {
@@ -61,10 +76,11 @@ func (c *Client) GetCampaign(campaignID uint64) (campaign *Campaign,
 	}
 
 	// Fire the Request
-	if response, err = c.Request(
-		http.MethodGet,
-		fmt.Sprintf("/%s/details/?%s=%d", modelCampaign, fieldID, campaignID),
-		nil, http.StatusOK,
+	if response, err = c.RequestWithOptions(
+		ctx, http.MethodGet,
+		campaignEndpoint(campaignID),
+		nil, http.StatusOK, withCacheEndpoint(CacheEndpointCampaign),
+		withOperation("GetCampaign"), withCallIDs(CallIDs{CampaignID: campaignID}),
 	); err != nil {
 		return
 	}
@@ -80,6 +96,12 @@ func (c *Client) GetCampaign(campaignID uint64) (campaign *Campaign,
 // For more information: https://docs.tonicpow.com/#b827446b-be34-4678-b347-33c4f63dbf9e
 func (c *Client) GetCampaignBySlug(slug string) (campaign *Campaign,
 	response *StandardResponse, err error) {
+	return c.GetCampaignBySlugWithContext(context.Background(), slug)
+}
+
+// GetCampaignBySlugWithContext is the same as GetCampaignBySlug but uses the given context
+func (c *Client) GetCampaignBySlugWithContext(ctx context.Context, slug string) (campaign *Campaign,
+	response *StandardResponse, err error) {
 
 	// Must have a slug
 	if len(slug) == 0 {
@@ -88,10 +110,11 @@ func (c *Client) GetCampaignBySlug(slug string) (campaign *Campaign,
 	}
 
 	// Fire the Request
-	if response, err = c.Request(
-		http.MethodGet,
-		fmt.Sprintf("/%s/details/?%s=%s", modelCampaign, fieldSlug, slug),
-		nil, http.StatusOK,
+	if response, err = c.RequestWithOptions(
+		ctx, http.MethodGet,
+		campaignBySlugEndpoint(slug),
+		nil, http.StatusOK, withCacheEndpoint(CacheEndpointCampaign),
+		withOperation("GetCampaignBySlug"),
 	); err != nil {
 		return
 	}
@@ -105,6 +128,12 @@ func (c *Client) GetCampaignBySlug(slug string) (campaign *Campaign,
 //
 // For more information: https://docs.tonicpow.com/#665eefd6-da42-4ca9-853c-fd8ca1bf66b2
 func (c *Client) UpdateCampaign(campaign *Campaign) (response *StandardResponse, err error) {
+	return c.UpdateCampaignWithContext(context.Background(), campaign)
+}
+
+// UpdateCampaignWithContext is the same as UpdateCampaign but uses the given context
+func (c *Client) UpdateCampaignWithContext(ctx context.Context,
+	campaign *Campaign) (response *StandardResponse, err error) {
 
 	// Basic requirements
 	if campaign.ID == 0 {
@@ -116,15 +145,19 @@ func (c *Client) UpdateCampaign(campaign *Campaign) (response *StandardResponse,
 	campaign.permitFields()
 
 	// Fire the Request
-	if response, err = c.Request(
-		http.MethodPut,
+	if response, err = c.RequestWithOptions(
+		ctx, http.MethodPut,
 		"/"+modelCampaign,
 		campaign, http.StatusOK,
+		withOperation("UpdateCampaign"), withCallIDs(CallIDs{CampaignID: campaign.ID}),
 	); err != nil {
 		return
 	}
 
 	err = json.Unmarshal(response.Body, &campaign)
+
+	// Remove the cached campaign & feeds
+	c.invalidateCampaign(campaign.ID, campaign.Slug)
 	return
 }
 
@@ -133,12 +166,19 @@ func (c *Client) UpdateCampaign(campaign *Campaign) (response *StandardResponse,
 //
 // For more information: https://docs.tonicpow.com/#b3fe69d3-24ba-4c2a-a485-affbb0a738de
 func (c *Client) CampaignsFeed(feedType FeedType) (feed string, response *StandardResponse, err error) {
+	return c.CampaignsFeedWithContext(context.Background(), feedType)
+}
+
+// CampaignsFeedWithContext is the same as CampaignsFeed but uses the given context
+func (c *Client) CampaignsFeedWithContext(ctx context.Context, feedType FeedType) (feed string,
+	response *StandardResponse, err error) {
 
 	// Fire the Request
-	if response, err = c.Request(
-		http.MethodGet,
-		fmt.Sprintf("/%s/feed/?%s=%s", modelCampaign, fieldFeedType, feedType),
-		nil, http.StatusOK,
+	if response, err = c.RequestWithOptions(
+		ctx, http.MethodGet,
+		campaignsFeedEndpoint(feedType),
+		nil, http.StatusOK, withCacheEndpoint(CacheEndpointFeed),
+		withOperation("CampaignsFeed"),
 	); err != nil {
 		return
 	}
@@ -147,53 +187,43 @@ func (c *Client) CampaignsFeed(feedType FeedType) (feed string, response *Standa
 	return
 }
 
-// ListCampaigns will return a list of campaigns
+// ListCampaigns will return a list of campaigns (see QueryCampaigns)
 // This will return an Error if the campaign is not found (404)
 //
 // For more information: https://docs.tonicpow.com/#c1b17be6-cb10-48b3-a519-4686961ff41c
 func (c *Client) ListCampaigns(page, resultsPerPage int, sortBy, sortOrder, searchQuery string,
 	minimumBalance uint64, includeExpired bool) (results *CampaignResults, response *StandardResponse, err error) {
+	return c.ListCampaignsWithContext(
+		context.Background(), page, resultsPerPage, sortBy, sortOrder, searchQuery, minimumBalance, includeExpired,
+	)
+}
 
-	// Do we know this field?
-	if len(sortBy) > 0 {
-		if !isInList(strings.ToLower(sortBy), campaignSortFields) {
-			err = fmt.Errorf("sort by %s is not valid", sortBy)
-			return
-		}
-	} else {
-		sortBy = SortByFieldCreatedAt
-		sortOrder = SortOrderDesc
-	}
-
-	// Fire the Request
-	if response, err = c.Request(
-		http.MethodGet,
-		fmt.Sprintf(
-			"/%s/list?%s=%d&%s=%d&%s=%s&%s=%s&%s=%s&%s=%d&%s=%t",
-			modelCampaign,
-			fieldCurrentPage, page,
-			fieldResultsPerPage, resultsPerPage,
-			fieldSortBy, sortBy,
-			fieldSortOrder, sortOrder,
-			fieldSearchQuery, searchQuery,
-			fieldMinimumBalance, minimumBalance,
-			fieldExpired, includeExpired,
-		),
-		nil, http.StatusOK,
-	); err != nil {
-		return
-	}
-
-	err = json.Unmarshal(response.Body, &results)
-	return
+// ListCampaignsWithContext is the same as ListCampaigns but uses the given context
+func (c *Client) ListCampaignsWithContext(ctx context.Context, page, resultsPerPage int,
+	sortBy, sortOrder, searchQuery string, minimumBalance uint64,
+	includeExpired bool) (results *CampaignResults, response *StandardResponse, err error) {
+	return c.queryCampaigns(ctx, &CampaignListOptions{
+		ListOptions:    newPositionalListOptions(page, resultsPerPage, sortBy, sortOrder),
+		IncludeExpired: includeExpired,
+		MinimumBalance: minimumBalance,
+		SearchQuery:    searchQuery,
+	}, withOperation("ListCampaigns"))
 }
 
-// ListCampaignsByURL will return a list of campaigns using the target url
+// ListCampaignsByURL will return a list of campaigns using the target url (see QueryCampaigns)
 // This will return an Error if the url is not found (404)
 //
 // For more information: https://docs.tonicpow.com/#30a15b69-7912-4e25-ba41-212529fba5ff
 func (c *Client) ListCampaignsByURL(targetURL string, page, resultsPerPage int,
 	sortBy, sortOrder string) (results *CampaignResults, response *StandardResponse, err error) {
+	return c.ListCampaignsByURLWithContext(
+		context.Background(), targetURL, page, resultsPerPage, sortBy, sortOrder,
+	)
+}
+
+// ListCampaignsByURLWithContext is the same as ListCampaignsByURL but uses the given context
+func (c *Client) ListCampaignsByURLWithContext(ctx context.Context, targetURL string, page, resultsPerPage int,
+	sortBy, sortOrder string) (results *CampaignResults, response *StandardResponse, err error) {
 
 	// Must have a value
 	if len(targetURL) == 0 {
@@ -201,29 +231,45 @@ func (c *Client) ListCampaignsByURL(targetURL string, page, resultsPerPage int,
 		return
 	}
 
-	// Do we know this field?
-	if len(sortBy) > 0 {
-		if !isInList(strings.ToLower(sortBy), campaignSortFields) {
-			err = fmt.Errorf("sort by %s is not valid", sortBy)
-			return
-		}
-	} else {
-		sortBy = SortByFieldCreatedAt
-		sortOrder = SortOrderDesc
+	return c.queryCampaigns(ctx, &CampaignListOptions{
+		ListOptions: newPositionalListOptions(page, resultsPerPage, sortBy, sortOrder),
+		TargetURL:   targetURL,
+	}, withOperation("ListCampaignsByURL"))
+}
+
+// QueryCampaigns will return a list of campaigns using the options (searching or by target url)
+// This will return an Error if the campaign is not found (404)
+//
+// For more information: https://docs.tonicpow.com/#c1b17be6-cb10-48b3-a519-4686961ff41c
+func (c *Client) QueryCampaigns(options *CampaignListOptions) (results *CampaignResults,
+	response *StandardResponse, err error) {
+	return c.QueryCampaignsWithContext(context.Background(), options)
+}
+
+// QueryCampaignsWithContext is the same as QueryCampaigns but uses the given context
+func (c *Client) QueryCampaignsWithContext(ctx context.Context, options *CampaignListOptions) (results *CampaignResults,
+	response *StandardResponse, err error) {
+	return c.queryCampaigns(ctx, options, withOperation("QueryCampaigns"))
+}
+
+// queryCampaigns will return a list of campaigns using the options
+func (c *Client) queryCampaigns(ctx context.Context, options *CampaignListOptions,
+	opts ...RequestOps) (results *CampaignResults, response *StandardResponse, err error) {
+
+	// Validate the options
+	if options == nil {
+		options = new(CampaignListOptions)
+	}
+	var query queryValues
+	if err = options.encode(&query); err != nil {
+		return
 	}
 
 	// Fire the Request
-	if response, err = c.Request(
-		http.MethodGet,
-		fmt.Sprintf("/%s/list?%s=%s&%s=%d&%s=%d&%s=%s&%s=%s",
-			modelCampaign,
-			fieldTargetURL, targetURL,
-			fieldCurrentPage, page,
-			fieldResultsPerPage, resultsPerPage,
-			fieldSortBy, sortBy,
-			fieldSortOrder, sortOrder,
-		),
-		nil, http.StatusOK,
+	if response, err = c.RequestWithOptions(
+		ctx, http.MethodGet,
+		"/"+modelCampaign+"/list?"+query.String(),
+		nil, http.StatusOK, opts...,
 	); err != nil {
 		return
 	}
@@ -1,10 +1,13 @@
 package tonicpow
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 	"testing"
 
+	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -25,8 +28,8 @@ func newTestCampaign() *Campaign {
 		Title:               "TonicPow",
 		AdvertiserProfileID: testAdvertiserID,
 		AdvertiserProfile:   newTestAdvertiserProfile(),
-		Balance:             13.37,
-		PayPerClickRate:     1,
+		Balance:             NewDecimal(1337, 2),
+		PayPerClickRate:     NewDecimal(1, 0),
 		BalanceSatoshis:     11333377,
 		ID:                  testCampaignID,
 		LinksCreated:        1,
@@ -859,6 +862,50 @@ func TestClient_ListCampaigns(t *testing.T) {
 		assert.Equal(t, testCampaignID, newResults.Campaigns[0].ID)
 	})
 
+	t.Run("list campaigns (zero page & page size are sent as given)", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		endpoint := fmt.Sprintf(
+			"%s/%s/list?current_page=0&results_per_page=0&sort_by=created_at&sort_order=desc&query=&minimum_balance=0&expired=false",
+			EnvironmentDevelopment.apiURL, modelCampaign,
+		)
+
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestCampaignResults(1, 25))
+		assert.NoError(t, err)
+
+		var newResults *CampaignResults
+		newResults, _, err = client.ListCampaigns(
+			0, 0, "", "", "", 0, false,
+		)
+		assert.NoError(t, err)
+		assert.NotNil(t, newResults)
+		assert.Equal(t, 1, httpmock.GetTotalCallCount())
+	})
+
+	t.Run("list campaigns (sort order is sent as given)", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		endpoint := fmt.Sprintf(
+			"%s/%s/list?current_page=1&results_per_page=25&sort_by=Balance&sort_order=DESCENDING&query=&minimum_balance=0&expired=false",
+			EnvironmentDevelopment.apiURL, modelCampaign,
+		)
+
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestCampaignResults(1, 25))
+		assert.NoError(t, err)
+
+		var newResults *CampaignResults
+		newResults, _, err = client.ListCampaigns(
+			1, 25, "Balance", "DESCENDING", "", 0, false,
+		)
+		assert.NoError(t, err)
+		assert.NotNil(t, newResults)
+		assert.Equal(t, 1, httpmock.GetTotalCallCount())
+	})
+
 	t.Run("invalid sort by", func(t *testing.T) {
 		client, err := newTestClient()
 		assert.NoError(t, err)
@@ -975,7 +1022,7 @@ func TestClient_ListCampaignsByURL(t *testing.T) {
 
 		endpoint := fmt.Sprintf("%s/%s/list?%s=%s&%s=%d&%s=%d&%s=%s&%s=%s",
 			EnvironmentDevelopment.apiURL, modelCampaign,
-			fieldTargetURL, testCampaignTargetURL,
+			fieldTargetURL, url.QueryEscape(testCampaignTargetURL),
 			fieldCurrentPage, 1,
 			fieldResultsPerPage, 25,
 			fieldSortBy, SortByFieldBalance,
@@ -1003,7 +1050,7 @@ func TestClient_ListCampaignsByURL(t *testing.T) {
 
 		endpoint := fmt.Sprintf("%s/%s/list?%s=%s&%s=%d&%s=%d&%s=%s&%s=%s",
 			EnvironmentDevelopment.apiURL, modelCampaign,
-			fieldTargetURL, testCampaignTargetURL,
+			fieldTargetURL, url.QueryEscape(testCampaignTargetURL),
 			fieldCurrentPage, 2,
 			fieldResultsPerPage, 5,
 			fieldSortBy, SortByFieldCreatedAt,
@@ -1031,7 +1078,7 @@ func TestClient_ListCampaignsByURL(t *testing.T) {
 
 		endpoint := fmt.Sprintf("%s/%s/list?%s=%s&%s=%d&%s=%d&%s=%s&%s=%s",
 			EnvironmentDevelopment.apiURL, modelCampaign,
-			fieldTargetURL, testCampaignTargetURL,
+			fieldTargetURL, url.QueryEscape(testCampaignTargetURL),
 			fieldCurrentPage, 2,
 			fieldResultsPerPage, 5,
 			fieldSortBy, SortByFieldCreatedAt,
@@ -1058,7 +1105,7 @@ func TestClient_ListCampaignsByURL(t *testing.T) {
 
 		endpoint := fmt.Sprintf("%s/%s/list?%s=%s&%s=%d&%s=%d&%s=%s&%s=%s",
 			EnvironmentDevelopment.apiURL, modelCampaign,
-			fieldTargetURL, testCampaignTargetURL,
+			fieldTargetURL, url.QueryEscape(testCampaignTargetURL),
 			fieldCurrentPage, 2,
 			fieldResultsPerPage, 5,
 			fieldSortBy, SortByFieldCreatedAt,
@@ -1085,7 +1132,7 @@ func TestClient_ListCampaignsByURL(t *testing.T) {
 
 		endpoint := fmt.Sprintf("%s/%s/list?%s=%s&%s=%d&%s=%d&%s=%s&%s=%s",
 			EnvironmentDevelopment.apiURL, modelCampaign,
-			fieldTargetURL, testCampaignTargetURL,
+			fieldTargetURL, url.QueryEscape(testCampaignTargetURL),
 			fieldCurrentPage, 2,
 			fieldResultsPerPage, 5,
 			fieldSortBy, SortByFieldCreatedAt,
@@ -1110,7 +1157,7 @@ func TestClient_ListCampaignsByURL(t *testing.T) {
 
 		endpoint := fmt.Sprintf("%s/%s/list?%s=%s&%s=%d&%s=%d&%s=%s&%s=%s",
 			EnvironmentDevelopment.apiURL, modelCampaign,
-			fieldTargetURL, testCampaignTargetURL,
+			fieldTargetURL, url.QueryEscape(testCampaignTargetURL),
 			fieldCurrentPage, 2,
 			fieldResultsPerPage, 5,
 			fieldSortBy, SortByFieldCreatedAt,
@@ -1260,3 +1307,274 @@ func TestClient_CampaignsFeed(t *testing.T) {
 		assert.Equal(t, apiError.Message, err.Error())
 	})
 }
+
+// TestClient_CreateCampaignWithContext will test the method CreateCampaignWithContext()
+func TestClient_CreateCampaignWithContext(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	endpoint := fmt.Sprintf("%s/%s", EnvironmentDevelopment.apiURL, modelCampaign)
+
+	t.Run("valid context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		campaign := newTestCampaign()
+		err = mockResponseData(http.MethodPost, endpoint, http.StatusCreated, campaign)
+		assert.NoError(t, err)
+
+		_, err = client.CreateCampaignWithContext(context.Background(), campaign)
+		assert.NoError(t, err)
+		assert.Equal(t, testCampaignID, campaign.ID)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		mockResponseBlocking(http.MethodPost, endpoint)
+
+		_, err = client.CreateCampaignWithContext(newCanceledContext(), newTestCampaign())
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+// TestClient_GetCampaignWithContext will test the method GetCampaignWithContext()
+func TestClient_GetCampaignWithContext(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	endpoint := fmt.Sprintf(
+		"%s/%s/details/?%s=%d", EnvironmentDevelopment.apiURL,
+		modelCampaign, fieldID, testCampaignID,
+	)
+
+	t.Run("valid context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestCampaign())
+		assert.NoError(t, err)
+
+		var campaign *Campaign
+		campaign, _, err = client.GetCampaignWithContext(context.Background(), testCampaignID)
+		assert.NoError(t, err)
+		assert.NotNil(t, campaign)
+		assert.Equal(t, testCampaignID, campaign.ID)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		mockResponseBlocking(http.MethodGet, endpoint)
+
+		var campaign *Campaign
+		campaign, _, err = client.GetCampaignWithContext(newCanceledContext(), testCampaignID)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, campaign)
+	})
+}
+
+// TestClient_GetCampaignBySlugWithContext will test the method GetCampaignBySlugWithContext()
+func TestClient_GetCampaignBySlugWithContext(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	slug := newTestCampaign().Slug
+	endpoint := fmt.Sprintf(
+		"%s/%s/details/?%s=%s", EnvironmentDevelopment.apiURL,
+		modelCampaign, fieldSlug, slug,
+	)
+
+	t.Run("valid context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestCampaign())
+		assert.NoError(t, err)
+
+		var campaign *Campaign
+		campaign, _, err = client.GetCampaignBySlugWithContext(context.Background(), slug)
+		assert.NoError(t, err)
+		assert.NotNil(t, campaign)
+		assert.Equal(t, slug, campaign.Slug)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		mockResponseBlocking(http.MethodGet, endpoint)
+
+		var campaign *Campaign
+		campaign, _, err = client.GetCampaignBySlugWithContext(newCanceledContext(), slug)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, campaign)
+	})
+}
+
+// TestClient_UpdateCampaignWithContext will test the method UpdateCampaignWithContext()
+func TestClient_UpdateCampaignWithContext(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	endpoint := fmt.Sprintf("%s/%s", EnvironmentDevelopment.apiURL, modelCampaign)
+
+	t.Run("valid context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		campaign := newTestCampaign()
+		err = mockResponseData(http.MethodPut, endpoint, http.StatusOK, campaign)
+		assert.NoError(t, err)
+
+		_, err = client.UpdateCampaignWithContext(context.Background(), campaign)
+		assert.NoError(t, err)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		mockResponseBlocking(http.MethodPut, endpoint)
+
+		_, err = client.UpdateCampaignWithContext(newCanceledContext(), newTestCampaign())
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+// TestClient_ListCampaignsWithContext will test the method ListCampaignsWithContext()
+func TestClient_ListCampaignsWithContext(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	endpoint := fmt.Sprintf(
+		"%s/%s/list?%s=%d&%s=%d&%s=%s&%s=%s&%s=%s&%s=%d&%s=%t",
+		EnvironmentDevelopment.apiURL, modelCampaign,
+		fieldCurrentPage, 1,
+		fieldResultsPerPage, 25,
+		fieldSortBy, SortByFieldBalance,
+		fieldSortOrder, SortOrderAsc,
+		fieldSearchQuery, "",
+		fieldMinimumBalance, 0,
+		fieldExpired, false,
+	)
+
+	t.Run("valid context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestCampaignResults(1, 25))
+		assert.NoError(t, err)
+
+		var results *CampaignResults
+		results, _, err = client.ListCampaignsWithContext(
+			context.Background(), 1, 25, SortByFieldBalance, SortOrderAsc, "", 0, false,
+		)
+		assert.NoError(t, err)
+		assert.NotNil(t, results)
+		assert.Equal(t, testCampaignID, results.Campaigns[0].ID)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		mockResponseBlocking(http.MethodGet, endpoint)
+
+		var results *CampaignResults
+		results, _, err = client.ListCampaignsWithContext(
+			newCanceledContext(), 1, 25, SortByFieldBalance, SortOrderAsc, "", 0, false,
+		)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, results)
+	})
+}
+
+// TestClient_ListCampaignsByURLWithContext will test the method ListCampaignsByURLWithContext()
+func TestClient_ListCampaignsByURLWithContext(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	endpoint := fmt.Sprintf("%s/%s/list?%s=%s&%s=%d&%s=%d&%s=%s&%s=%s",
+		EnvironmentDevelopment.apiURL, modelCampaign,
+		fieldTargetURL, url.QueryEscape(testCampaignTargetURL),
+		fieldCurrentPage, 1,
+		fieldResultsPerPage, 25,
+		fieldSortBy, SortByFieldBalance,
+		fieldSortOrder, SortOrderDesc,
+	)
+
+	t.Run("valid context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestCampaignResults(1, 25))
+		assert.NoError(t, err)
+
+		var results *CampaignResults
+		results, _, err = client.ListCampaignsByURLWithContext(
+			context.Background(), testCampaignTargetURL, 1, 25, SortByFieldBalance, SortOrderDesc,
+		)
+		assert.NoError(t, err)
+		assert.NotNil(t, results)
+		assert.Equal(t, testCampaignID, results.Campaigns[0].ID)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		mockResponseBlocking(http.MethodGet, endpoint)
+
+		var results *CampaignResults
+		results, _, err = client.ListCampaignsByURLWithContext(
+			newCanceledContext(), testCampaignTargetURL, 1, 25, SortByFieldBalance, SortOrderDesc,
+		)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, results)
+	})
+}
+
+// TestClient_CampaignsFeedWithContext will test the method CampaignsFeedWithContext()
+func TestClient_CampaignsFeedWithContext(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	endpoint := fmt.Sprintf(
+		"%s/%s/feed/?%s=%s", EnvironmentDevelopment.apiURL,
+		modelCampaign, fieldFeedType, FeedTypeRSS,
+	)
+
+	t.Run("valid context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		mockResponseFeed(endpoint, http.StatusOK, newTestCampaignFeedRSS())
+
+		var feed string
+		feed, _, err = client.CampaignsFeedWithContext(context.Background(), FeedTypeRSS)
+		assert.NoError(t, err)
+		assert.Equal(t, newTestCampaignFeedRSS(), feed)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		mockResponseBlocking(http.MethodGet, endpoint)
+
+		var feed string
+		feed, _, err = client.CampaignsFeedWithContext(newCanceledContext(), FeedTypeRSS)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, "", feed)
+	})
+}
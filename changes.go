@@ -0,0 +1,243 @@
+package tonicpow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// ChangeSet is a partial update: only the fields set (by json name, "title", "unlisted", etc.) are sent,
+// so the fields not in the change set are never overwritten (zero values included)
+//
+// Use Set() to build a change set by hand, or DiffCampaign(), DiffGoal(), DiffAdvertiserProfile() and DiffApp()
+// to compute the changes between a fetched model and a modified copy
+type ChangeSet map[string]interface{}
+
+// Set will set the new value of the field (json name) and return the change set (for chaining)
+func (c ChangeSet) Set(field string, value interface{}) ChangeSet {
+	c[field] = value
+	return c
+}
+
+// Has will return true if the field (json name) is changed
+func (c ChangeSet) Has(field string) bool {
+	_, ok := c[field]
+	return ok
+}
+
+// Fields will return the changed fields (sorted)
+func (c ChangeSet) Fields() []string {
+	fields := make([]string, 0, len(c))
+	for field := range c {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// readOnlyFields are the fields (json names) of each model that cannot be changed
+var (
+	readOnlyAdvertiserFields = []string{"domain_verified", fieldID, "public_guid", fieldUserID}
+	readOnlyAppFields        = []string{fieldID, fieldAdvertiserProfileID, fieldUserID}
+	readOnlyCampaignFields   = []string{
+		fieldID, "advertiser_profile", fieldAdvertiserProfileID, "balance", "balance_satoshis", "created_at",
+		"domain_verified", "funding_address", "funding_paymail_address", fieldGoals, "images", "last_event_at",
+		"links_created", "paid_clicks", "paid_conversions", "public_guid",
+	}
+	readOnlyGoalFields = []string{fieldID, fieldCampaignID, "last_converted_at", "payouts"}
+)
+
+// DiffAdvertiserProfile will return the changes from the original to the modified profile (read-only fields are ignored)
+func DiffAdvertiserProfile(original, modified *AdvertiserProfile) (ChangeSet, error) {
+	return diff(original, modified, readOnlyAdvertiserFields)
+}
+
+// DiffApp will return the changes from the original to the modified app (read-only fields are ignored)
+func DiffApp(original, modified *App) (ChangeSet, error) {
+	return diff(original, modified, readOnlyAppFields)
+}
+
+// DiffCampaign will return the changes from the original to the modified campaign (read-only fields are ignored)
+func DiffCampaign(original, modified *Campaign) (ChangeSet, error) {
+	return diff(original, modified, readOnlyCampaignFields)
+}
+
+// DiffGoal will return the changes from the original to the modified goal (read-only fields are ignored)
+func DiffGoal(original, modified *Goal) (ChangeSet, error) {
+	return diff(original, modified, readOnlyGoalFields)
+}
+
+// diff will compare the json fields of the models and return the changed (writable) fields
+func diff(original, modified interface{}, readOnly []string) (ChangeSet, error) {
+	before, err := modelFields(original)
+	if err != nil {
+		return nil, err
+	}
+	var after map[string]json.RawMessage
+	if after, err = modelFields(modified); err != nil {
+		return nil, err
+	}
+
+	changes := ChangeSet{}
+	for field, value := range after {
+		if !isInList(field, readOnly) && !bytes.Equal(before[field], value) {
+			changes[field] = value
+		}
+	}
+	return changes, nil
+}
+
+// modelFields will return the json encoded fields of the model
+func modelFields(model interface{}) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(model)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// payload will validate the change set against the fields of the model and return the request payload
+func (c ChangeSet) payload(id uint64, model interface{}, readOnly []string) (map[string]interface{}, error) {
+
+	// Must have an ID and changes
+	if id == 0 {
+		return nil, fmt.Errorf("missing required attribute: %s", fieldID)
+	} else if len(c) == 0 {
+		return nil, errors.New("missing required attribute: changes")
+	}
+
+	// Only known & writable fields
+	fields, err := modelFields(model)
+	if err != nil {
+		return nil, err
+	}
+	payload := make(map[string]interface{}, len(c)+1)
+	for _, field := range c.Fields() {
+		if isInList(field, readOnly) {
+			return nil, fmt.Errorf("attribute cannot be changed: %s", field)
+		} else if _, ok := fields[field]; !ok {
+			return nil, fmt.Errorf("unknown attribute: %s", field)
+		}
+		payload[field] = c[field]
+	}
+	payload[fieldID] = id
+	return payload, nil
+}
+
+// PatchAdvertiserProfile will update only the fields of the profile in the change set
+// (UpdateAdvertiserProfile sends every field, including zero values)
+//
+// For more information: https://docs.tonicpow.com/#0cebd1ff-b1ce-4111-aff6-9d586f632a84
+func (c *Client) PatchAdvertiserProfile(profileID uint64, changes ChangeSet) (profile *AdvertiserProfile,
+	response *StandardResponse, err error) {
+	return c.PatchAdvertiserProfileWithContext(context.Background(), profileID, changes)
+}
+
+// PatchAdvertiserProfileWithContext is the same as PatchAdvertiserProfile but uses the given context
+func (c *Client) PatchAdvertiserProfileWithContext(ctx context.Context, profileID uint64,
+	changes ChangeSet) (profile *AdvertiserProfile, response *StandardResponse, err error) {
+
+	// Validate the changes
+	var payload map[string]interface{}
+	if payload, err = changes.payload(profileID, new(AdvertiserProfile), readOnlyAdvertiserFields); err != nil {
+		return
+	}
+
+	// Fire the Request
+	if response, err = c.RequestWithOptions(
+		ctx, http.MethodPut,
+		"/"+modelAdvertiser,
+		payload, http.StatusOK,
+		withOperation("PatchAdvertiserProfile"), withCallIDs(CallIDs{AdvertiserProfileID: profileID}),
+	); err != nil {
+		return
+	}
+
+	// Remove the cached profile
+	c.invalidate(advertiserProfileEndpoint(profileID))
+
+	err = json.Unmarshal(response.Body, &profile)
+	return
+}
+
+// PatchCampaign will update only the fields of the campaign in the change set
+// (UpdateCampaign sends every field, including zero values)
+//
+// For more information: https://docs.tonicpow.com/#665eefd6-da42-4ca9-853c-fd8ca1bf66b2
+func (c *Client) PatchCampaign(campaignID uint64, changes ChangeSet) (campaign *Campaign,
+	response *StandardResponse, err error) {
+	return c.PatchCampaignWithContext(context.Background(), campaignID, changes)
+}
+
+// PatchCampaignWithContext is the same as PatchCampaign but uses the given context
+func (c *Client) PatchCampaignWithContext(ctx context.Context, campaignID uint64,
+	changes ChangeSet) (campaign *Campaign, response *StandardResponse, err error) {
+
+	// Validate the changes
+	var payload map[string]interface{}
+	if payload, err = changes.payload(campaignID, new(Campaign), readOnlyCampaignFields); err != nil {
+		return
+	}
+
+	// Fire the Request
+	if response, err = c.RequestWithOptions(
+		ctx, http.MethodPut,
+		"/"+modelCampaign,
+		payload, http.StatusOK,
+		withOperation("PatchCampaign"), withCallIDs(CallIDs{CampaignID: campaignID}),
+	); err != nil {
+		return
+	}
+
+	// Remove the cached campaign & feeds (and the new slug, if changed)
+	c.invalidateCampaign(campaignID, "")
+	if err = json.Unmarshal(response.Body, &campaign); err == nil && campaign != nil && len(campaign.Slug) > 0 {
+		c.invalidate(campaignBySlugEndpoint(campaign.Slug))
+	}
+	return
+}
+
+// PatchGoal will update only the fields of the goal in the change set
+// (UpdateGoal sends every field, including zero values)
+//
+// For more information: https://docs.tonicpow.com/#395f5b7d-6a5d-49c8-b1ae-abf7f90b42a2
+func (c *Client) PatchGoal(goalID uint64, changes ChangeSet) (goal *Goal,
+	response *StandardResponse, err error) {
+	return c.PatchGoalWithContext(context.Background(), goalID, changes)
+}
+
+// PatchGoalWithContext is the same as PatchGoal but uses the given context
+func (c *Client) PatchGoalWithContext(ctx context.Context, goalID uint64,
+	changes ChangeSet) (goal *Goal, response *StandardResponse, err error) {
+
+	// Validate the changes
+	var payload map[string]interface{}
+	if payload, err = changes.payload(goalID, new(Goal), readOnlyGoalFields); err != nil {
+		return
+	}
+
+	// Fire the Request
+	if response, err = c.RequestWithOptions(
+		ctx, http.MethodPut,
+		"/"+modelGoal,
+		payload, http.StatusOK,
+		withOperation("PatchGoal"), withCallIDs(CallIDs{GoalID: goalID}),
+	); err != nil {
+		return
+	}
+
+	// Remove the cached goal & campaign (the campaign of the cached goal, or of the response)
+	c.invalidateGoal(goalID, 0)
+	if err = json.Unmarshal(response.Body, &goal); err == nil && goal != nil && goal.CampaignID > 0 {
+		c.invalidateCampaign(goal.CampaignID, "")
+	}
+	return
+}
@@ -0,0 +1,245 @@
+package tonicpow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockPatchResponse will mock a PUT returning the response and capture the request payload
+func mockPatchResponse(endpoint string, response interface{}) *map[string]interface{} {
+	payload := make(map[string]interface{})
+	httpmock.Reset()
+	httpmock.RegisterResponder(http.MethodPut, endpoint, func(req *http.Request) (*http.Response, error) {
+		if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+			return nil, err
+		}
+		return httpmock.NewJsonResponse(http.StatusOK, response)
+	})
+	return &payload
+}
+
+// TestChangeSet will test the methods Set(), Has() and Fields()
+func TestChangeSet(t *testing.T) {
+	t.Parallel()
+
+	changes := ChangeSet{}.Set("unlisted", false).Set("description", "")
+	assert.True(t, changes.Has("unlisted"))
+	assert.False(t, changes.Has("title"))
+	assert.Equal(t, []string{"description", "unlisted"}, changes.Fields())
+}
+
+// TestDiffCampaign will test the method DiffCampaign()
+func TestDiffCampaign(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no changes", func(t *testing.T) {
+		changes, err := DiffCampaign(newTestCampaign(), newTestCampaign())
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+
+	t.Run("changed fields (zero values included)", func(t *testing.T) {
+		original := newTestCampaign()
+		modified := newTestCampaign()
+		modified.Description = ""
+		modified.MatchDomain = false
+		modified.PayPerClickRate = NewDecimal(15, 1)
+		modified.Requirements.VisitorCountries = []string{"US"}
+
+		changes, err := DiffCampaign(original, modified)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"description", "match_domain", "pay_per_click_rate", "requirements"}, changes.Fields())
+
+		var data []byte
+		data, err = json.Marshal(changes)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"description":""`)
+		assert.Contains(t, string(data), `"match_domain":false`)
+		assert.Contains(t, string(data), `"pay_per_click_rate":1.5`)
+	})
+
+	t.Run("read-only fields are ignored", func(t *testing.T) {
+		original := newTestCampaign()
+		modified := newTestCampaign()
+		modified.Balance = NewDecimal(1, 0)
+		modified.AdvertiserProfileID = 999
+		modified.Goals = nil
+		modified.PaidClicks = 10
+
+		changes, err := DiffCampaign(original, modified)
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+}
+
+// TestDiffGoal will test the method DiffGoal()
+func TestDiffGoal(t *testing.T) {
+	t.Parallel()
+
+	original := newTestGoal()
+	modified := newTestGoal()
+	modified.Title = "Updated Title"
+	modified.PayoutInstant = !original.PayoutInstant
+	modified.Payouts = 99
+
+	changes, err := DiffGoal(original, modified)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"payout_instant", "title"}, changes.Fields())
+}
+
+// TestDiffAdvertiserProfile will test the method DiffAdvertiserProfile()
+func TestDiffAdvertiserProfile(t *testing.T) {
+	t.Parallel()
+
+	original := newTestAdvertiserProfile()
+	modified := newTestAdvertiserProfile()
+	modified.DomainVerified = !original.DomainVerified
+	modified.Unlisted = true
+	modified.UserID = 999
+
+	changes, err := DiffAdvertiserProfile(original, modified)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"unlisted"}, changes.Fields())
+}
+
+// TestClient_PatchCampaign will test the method PatchCampaign()
+func TestClient_PatchCampaign(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	endpoint := fmt.Sprintf("%s/%s", EnvironmentDevelopment.apiURL, modelCampaign)
+
+	t.Run("only the changes are sent", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		updated := newTestCampaign()
+		updated.Unlisted = true
+		payload := mockPatchResponse(endpoint, updated)
+
+		var campaign *Campaign
+		campaign, _, err = client.PatchCampaign(testCampaignID, ChangeSet{}.Set("unlisted", true))
+		require.NoError(t, err)
+		require.NotNil(t, campaign)
+		assert.True(t, campaign.Unlisted)
+		assert.Equal(t, map[string]interface{}{
+			fieldID: float64(testCampaignID), "unlisted": true,
+		}, *payload)
+	})
+
+	t.Run("invalid changes", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		err = mockResponseData(http.MethodPut, endpoint, http.StatusOK, newTestCampaign())
+		require.NoError(t, err)
+
+		tests := []struct {
+			name       string
+			campaignID uint64
+			changes    ChangeSet
+			expected   string
+		}{
+			{"missing id", 0, ChangeSet{}.Set("title", "test"), "missing required attribute: id"},
+			{"no changes", testCampaignID, ChangeSet{}, "missing required attribute: changes"},
+			{"read-only field", testCampaignID, ChangeSet{}.Set(fieldAdvertiserProfileID, 1),
+				"attribute cannot be changed: advertiser_profile_id"},
+			{"unknown field", testCampaignID, ChangeSet{}.Set("titel", "test"), "unknown attribute: titel"},
+		}
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				campaign, response, err := client.PatchCampaign(test.campaignID, test.changes)
+				assert.EqualError(t, err, test.expected)
+				assert.Nil(t, campaign)
+				assert.Nil(t, response)
+			})
+		}
+		assert.Equal(t, 0, httpmock.GetTotalCallCount())
+	})
+
+	t.Run("error from api (status code)", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		apiError := &Error{Code: 400, Message: "some error message", RequestGUID: "7f3d97a8fd67ff57861904df6118dcc8"}
+		err = mockResponseData(http.MethodPut, endpoint, http.StatusBadRequest, apiError)
+		require.NoError(t, err)
+
+		var response *StandardResponse
+		_, response, err = client.PatchCampaign(testCampaignID, ChangeSet{}.Set("title", "test"))
+		assert.ErrorIs(t, err, ErrValidation)
+		require.NotNil(t, response)
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	})
+}
+
+// TestClient_PatchGoal will test the method PatchGoal()
+func TestClient_PatchGoal(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	client, err := newTestClient()
+	require.NoError(t, err)
+
+	updated := newTestGoal()
+	updated.Title = "Updated Title"
+	payload := mockPatchResponse(fmt.Sprintf("%s/%s", EnvironmentDevelopment.apiURL, modelGoal), updated)
+
+	var goal *Goal
+	goal, _, err = client.PatchGoal(testGoalID, ChangeSet{}.Set("title", "Updated Title"))
+	require.NoError(t, err)
+	require.NotNil(t, goal)
+	assert.Equal(t, "Updated Title", goal.Title)
+	assert.Equal(t, map[string]interface{}{
+		fieldID: float64(testGoalID), "title": "Updated Title",
+	}, *payload)
+
+	_, _, err = client.PatchGoal(testGoalID, ChangeSet{}.Set(fieldCampaignID, 1))
+	assert.EqualError(t, err, "attribute cannot be changed: campaign_id")
+}
+
+// TestClient_PatchAdvertiserProfile will test the method PatchAdvertiserProfile()
+func TestClient_PatchAdvertiserProfile(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	client, err := newTestClient()
+	require.NoError(t, err)
+
+	updated := newTestAdvertiserProfile()
+	updated.Unlisted = true
+	payload := mockPatchResponse(fmt.Sprintf("%s/%s", EnvironmentDevelopment.apiURL, modelAdvertiser), updated)
+
+	var profile *AdvertiserProfile
+	profile, _, err = client.PatchAdvertiserProfileWithContext(
+		context.Background(), testAdvertiserID, ChangeSet{}.Set("unlisted", true),
+	)
+	require.NoError(t, err)
+	require.NotNil(t, profile)
+	assert.True(t, profile.Unlisted)
+	assert.Equal(t, map[string]interface{}{
+		fieldID: float64(testAdvertiserID), "unlisted": true,
+	}, *payload)
+}
+
+// ExampleDiffCampaign example using DiffCampaign()
+//
+// See more examples in /examples/
+func ExampleDiffCampaign() {
+	original := newTestCampaign()
+	modified := *original
+	modified.Title = "TonicPow (updated)"
+	modified.BotProtection = false
+
+	changes, err := DiffCampaign(original, &modified)
+	if err != nil {
+		fmt.Printf("error computing changes: %s", err.Error())
+		return
+	}
+	fmt.Printf("changed fields: %v", changes.Fields())
+	// Output:changed fields: [bot_protection title]
+}
@@ -1,8 +1,12 @@
 package tonicpow
 
 import (
+	"bytes"
+	"context"
+	"crypto/rand"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
@@ -14,26 +18,38 @@ type (
 	// Client is the TonicPow client/configuration
 	Client struct {
 		httpClient *resty.Client
+		limiter    *rateLimiter   // Client-side rate limiter (if enabled)
 		options    *ClientOptions // Options are all the default settings / configuration
 	}
 
 	// ClientOptions holds all the configuration for client requests and default resources
 	ClientOptions struct {
-		apiKey         string              // API key
-		env            Environment         // Environment
-		customHeaders  map[string][]string // Custom headers on outgoing requests
-		httpTimeout    time.Duration       // Default timeout in seconds for GET requests
-		requestTracing bool                // If enabled, it will trace the request timing
-		retryCount     int                 // Default retry count for HTTP requests
-		userAgent      string              // User agent for all outgoing requests
+		apiKey             string                          // API key
+		cache              Cache                           // Cache for the responses of read endpoints (if enabled)
+		cacheTTLs          map[CacheEndpoint]time.Duration // Cache TTL per endpoint
+		env                Environment                     // Environment
+		hooks              []*Hooks                        // Hooks called for every request attempt
+		middleware         []RequestMiddleware             // Middleware wrapping every API call
+		customHeaders      map[string][]string             // Custom headers on outgoing requests
+		httpTimeout        time.Duration                   // Default timeout in seconds for GET requests
+		rateLimit          float64                         // Maximum requests per second (0 is disabled)
+		rateLimitBurst     int                             // Maximum burst of requests for the rate limit
+		requestTracing     bool                            // If enabled, it will trace the request timing
+		retryCount         int                             // Default retry count for HTTP requests
+		retryNonIdempotent bool                            // If enabled, non-idempotent requests (POST) are also retried
+		retryPolicy        RetryPolicy                     // Retry policy for failed requests
+		userAgent          string                          // User agent for all outgoing requests
 	}
 
 	// StandardResponse is the standard fields returned on all responses
 	StandardResponse struct {
-		Body       []byte          `json:"-"` // Body of the response request
-		Error      *Error          `json:"-"` // API error response
-		StatusCode int             `json:"-"` // Status code returned on the request
-		Tracing    resty.TraceInfo `json:"-"` // Trace information if enabled on the request
+		Body           []byte          `json:"-"` // Body of the response request
+		Cached         bool            `json:"-"` // True if the response was served from the cache
+		Error          *Error          `json:"-"` // API error response
+		Header         http.Header     `json:"-"` // Headers of the response (nil for cached responses)
+		IdempotencyKey string          `json:"-"` // Idempotency key sent with the request (if any)
+		StatusCode     int             `json:"-"` // Status code returned on the request
+		Tracing        resty.TraceInfo `json:"-"` // Trace information if enabled on the request
 	}
 )
 
@@ -56,19 +72,29 @@ func NewClient(opts ...ClientOps) (ClientInterface, error) {
 	if client.options.apiKey == "" {
 		return nil, errors.New("missing an API Key")
 	}
+	// Set the retry policy (retries are handled by the client, not by Resty)
+	if client.options.retryPolicy == nil {
+		client.options.retryPolicy = NewBackoffPolicy(client.options.retryCount)
+	}
+	// Set the rate limiter
+	if client.options.rateLimit > 0 {
+		client.limiter = newRateLimiter(client.options.rateLimit, client.options.rateLimitBurst)
+	}
 	// Set the Resty HTTP client
 	if client.httpClient == nil {
 		client.httpClient = resty.New()
 		// Set defaults (for GET requests)
 		client.httpClient.SetTimeout(client.options.httpTimeout)
-		client.httpClient.SetRetryCount(client.options.retryCount)
 	}
 	return client, nil
 }
 
 // WithCustomHTTPClient will overwrite the default client with a custom client.
+//
+// Retries of the custom client are turned off (SetRetryCount(0)), failed requests are
+// retried by the RetryPolicy of the client instead (see WithRetryPolicy and WithRetryCount)
 func (c *Client) WithCustomHTTPClient(client *resty.Client) *Client {
-	c.httpClient = client
+	c.httpClient = client.SetRetryCount(0)
 	return c
 }
 
@@ -89,20 +115,188 @@ func (c *Client) Options() *ClientOptions {
 
 // Request is a standard GET / POST / PUT / DELETE request for all outgoing HTTP requests
 // Omit the data attribute if using a GET request
+//
+// If the API responds with an unexpected status code, the error returned is an *Error
+// which can be checked using errors.Is() (ErrNotFound, ErrUnauthorized, etc.) or errors.As()
 func (c *Client) Request(httpMethod string, requestEndpoint string,
 	data interface{}, expectedCode int) (response *StandardResponse, err error) {
+	return c.RequestWithOptions(context.Background(), httpMethod, requestEndpoint, data, expectedCode)
+}
 
-	// Set the user agent
-	req := c.httpClient.R().SetHeader("User-Agent", c.options.userAgent)
+// RequestWithContext is the same as Request but uses the given context for the outgoing HTTP request,
+// cancellation and deadlines on the context are honored by the underlying HTTP client
+//
+// Failed requests are retried using the RetryPolicy of the client (see WithRetryPolicy),
+// non-idempotent requests (POST) are only retried if enabled (see WithRetryNonIdempotent)
+func (c *Client) RequestWithContext(ctx context.Context, httpMethod string, requestEndpoint string,
+	data interface{}, expectedCode int) (response *StandardResponse, err error) {
+	return c.RequestWithOptions(ctx, httpMethod, requestEndpoint, data, expectedCode)
+}
 
-	// Set the body if (PUT || POST)
+// RequestWithOptions is the same as RequestWithContext but with options for the single request
+// (see WithIdempotencyKey), it is not part of ClientInterface so existing implementations are unaffected
+//
+// Non-idempotent requests (POST) with an idempotency key are also retried
+// using the RetryPolicy of the client (see WithRetryPolicy)
+func (c *Client) RequestWithOptions(ctx context.Context, httpMethod string, requestEndpoint string,
+	data interface{}, expectedCode int, opts ...RequestOps) (response *StandardResponse, err error) {
+
+	// Set the request options
+	options := new(requestOptions)
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	call := &Call{
+		Data:         data,
+		Endpoint:     requestEndpoint,
+		ExpectedCode: expectedCode,
+		IDs:          options.ids,
+		Method:       httpMethod,
+		Operation:    options.operation,
+	}
+	if len(call.Operation) == 0 {
+		call.Operation = "Request"
+	}
+	if len(c.options.middleware) == 0 {
+		return c.request(ctx, call, options)
+	}
+
+	// Wrap the request with the middleware (the first middleware is the outermost)
+	handler := func(ctx context.Context, call *Call) (*StandardResponse, error) {
+		return c.request(ctx, call, options)
+	}
+	for i := len(c.options.middleware) - 1; i >= 0; i-- {
+		handler = c.options.middleware[i](handler)
+	}
+	return handler(ctx, call)
+}
+
+// request will fire the request of the call (retrying failed attempts)
+func (c *Client) request(ctx context.Context, call *Call, options *requestOptions) (response *StandardResponse,
+	err error) {
+
+	httpMethod, requestEndpoint, expectedCode := call.Method, call.Endpoint, call.ExpectedCode
+
+	// Set the body if (PUT || POST), the same body is used on every attempt
+	var body []byte
 	if httpMethod != http.MethodGet && httpMethod != http.MethodDelete {
-		var j []byte
-		if j, err = json.Marshal(data); err != nil {
+		if body, err = json.Marshal(call.Data); err != nil {
+			return
+		}
+	}
+
+	// Generate an idempotency key if non-idempotent requests are retried
+	if len(options.idempotencyKey) == 0 && c.options.retryNonIdempotent && !isIdempotent(httpMethod) {
+		if options.idempotencyKey, err = newIdempotencyKey(); err != nil {
+			return
+		}
+	}
+
+	idempotent := c.options.retryNonIdempotent || isIdempotent(httpMethod) || len(options.idempotencyKey) > 0
+
+	// Serve the response from the cache (if fresh), otherwise revalidate using the ETag
+	cacheKey, cacheTTL := c.cacheKey(httpMethod, requestEndpoint, options)
+	var cached *CacheEntry
+	if len(cacheKey) > 0 {
+		if cached, _ = c.options.cache.Get(cacheKey); cached != nil && cached.fresh(time.Now()) {
+			return newCachedResponse(cached), nil
+		} else if cached != nil && len(cached.ETag) > 0 {
+			options.ifNoneMatch = cached.ETag
+		} else {
+			cached = nil
+		}
+	}
+	for attempt := 1; ; attempt++ {
+		call.Attempts = attempt
+
+		// Wait for the rate limiter
+		if c.limiter != nil {
+			if err = c.limiter.wait(ctx); err != nil {
+				return
+			}
+		}
+
+		// Fire the request (calling the hooks if set)
+		var resp *resty.Response
+		req := c.newRequest(ctx, body, options)
+		if len(c.options.hooks) == 0 {
+			resp, err = c.send(req, httpMethod, requestEndpoint)
+		} else {
+			info := newRequestInfo(
+				attempt, httpMethod, requestEndpoint, c.options.env.URL()+requestEndpoint, req.Header, options,
+			)
+			c.beforeRequest(ctx, info)
+			start := time.Now()
+			resp, err = c.send(req, httpMethod, requestEndpoint)
+			if err == nil {
+				c.afterAttempt(
+					ctx, info, resp.StatusCode(), resp.Header(), resp.Body(), time.Since(start), expectedCode, nil,
+				)
+			} else {
+				c.afterAttempt(ctx, info, 0, nil, nil, time.Since(start), expectedCode, err)
+			}
+		}
+
+		// Check if the attempt failed
+		failed := &RetryAttempt{Attempt: attempt, Err: err, Idempotent: idempotent, Method: httpMethod}
+		if err == nil {
+			failed.Header = resp.Header()
+			failed.StatusCode = resp.StatusCode()
+		}
+
+		// Rate limited by the API? (pause all requests)
+		if c.limiter != nil && failed.StatusCode == http.StatusTooManyRequests {
+			if delay, ok := retryAfter(failed.Header, time.Now()); ok {
+				c.limiter.pause(delay)
+			}
+		}
+
+		// Not modified (the cached response is still valid)
+		if cached != nil && failed.StatusCode == http.StatusNotModified {
+			cached = &CacheEntry{Body: cached.Body, ETag: cached.ETag, ExpiresAt: time.Now().Add(cacheTTL)}
+			c.options.cache.Set(cacheKey, cached)
+			return newCachedResponse(cached), nil
+		}
+
+		// Retry the request?
+		if ctx.Err() == nil && (err != nil || (expectedCode > 0 && failed.StatusCode != expectedCode)) {
+			if delay, retry := c.options.retryPolicy.Backoff(failed); retry {
+				if err = sleepContext(ctx, delay); err != nil {
+					return
+				}
+				continue
+			}
+		}
+		if err != nil {
 			return
 		}
-		req = req.SetBody(string(j))
-		req.Header.Add("Content-Length", strconv.Itoa(len(j)))
+		if response, err = c.newResponse(resp, expectedCode); response != nil {
+			response.IdempotencyKey = options.idempotencyKey
+		}
+
+		// Store the response in the cache
+		if err == nil && len(cacheKey) > 0 {
+			c.options.cache.Set(cacheKey, &CacheEntry{
+				Body:      bytes.Clone(response.Body),
+				ETag:      resp.Header().Get(headerETag),
+				ExpiresAt: time.Now().Add(cacheTTL),
+			})
+		}
+		return
+	}
+}
+
+// newRequest will create a single attempt of the request
+func (c *Client) newRequest(ctx context.Context, body []byte, options *requestOptions) *resty.Request {
+
+	// Set the context & user agent
+	req := c.httpClient.R().SetContext(ctx).SetHeader("User-Agent", c.options.userAgent)
+
+	// Set the body if (PUT || POST)
+	if body != nil {
+		req = req.SetBody(string(body))
+		req.Header.Add("Content-Length", strconv.Itoa(len(body)))
 		req.Header.Set("Content-Type", "application/json")
 	}
 
@@ -121,21 +315,33 @@ func (c *Client) Request(httpMethod string, requestEndpoint string,
 		}
 	}
 
-	// Fire the request
-	var resp *resty.Response
+	// Set the idempotency key (the same key is sent on every attempt)
+	if len(options.idempotencyKey) > 0 {
+		req.Header.Set(IdempotencyKeyHeader, options.idempotencyKey)
+	}
+
+	// Revalidate the cached response
+	if len(options.ifNoneMatch) > 0 {
+		req.Header.Set(headerIfNoneMatch, options.ifNoneMatch)
+	}
+	return req
+}
+
+// send will fire a single attempt of the request
+func (c *Client) send(req *resty.Request, httpMethod string, requestEndpoint string) (*resty.Response, error) {
 	switch httpMethod {
 	case http.MethodPost:
-		resp, err = req.Post(c.options.env.URL() + requestEndpoint)
+		return req.Post(c.options.env.URL() + requestEndpoint)
 	case http.MethodPut:
-		resp, err = req.Put(c.options.env.URL() + requestEndpoint)
+		return req.Put(c.options.env.URL() + requestEndpoint)
 	case http.MethodDelete:
-		resp, err = req.Delete(c.options.env.URL() + requestEndpoint)
-	case http.MethodGet:
-		resp, err = req.Get(c.options.env.URL() + requestEndpoint)
-	}
-	if err != nil {
-		return
+		return req.Delete(c.options.env.URL() + requestEndpoint)
 	}
+	return req.Get(c.options.env.URL() + requestEndpoint)
+}
+
+// newResponse will create the StandardResponse and check the expected status code
+func (c *Client) newResponse(resp *resty.Response, expectedCode int) (response *StandardResponse, err error) {
 
 	// Start the response
 	response = new(StandardResponse)
@@ -145,17 +351,15 @@ func (c *Client) Request(httpMethod string, requestEndpoint string,
 		response.Tracing = resp.Request.TraceInfo()
 	}
 
-	// Set the status code & body
+	// Set the status code, headers & body
 	response.StatusCode = resp.StatusCode()
+	response.Header = resp.Header()
 	response.Body = resp.Body()
 
 	// Check expected code if set
 	if expectedCode > 0 && response.StatusCode != expectedCode {
-		response.Error = new(Error)
-		if err = json.Unmarshal(response.Body, &response.Error); err != nil {
-			return
-		}
-		err = errors.New(response.Error.Message)
+		response.Error = newError(response.StatusCode, response.Body)
+		err = response.Error
 		if response.StatusCode == 0 { // If a 200 is expected, but you get a 201, this case occurs (improper status code check)
 			response.StatusCode = http.StatusInternalServerError
 		}
@@ -163,3 +367,23 @@ func (c *Client) Request(httpMethod string, requestEndpoint string,
 
 	return
 }
+
+// newCachedResponse will create the StandardResponse for a cached response (using a copy of the cached body)
+func newCachedResponse(entry *CacheEntry) *StandardResponse {
+	return &StandardResponse{
+		Body:       bytes.Clone(entry.Body),
+		Cached:     true,
+		StatusCode: http.StatusOK,
+	}
+}
+
+// newIdempotencyKey will return a random idempotency key (UUID v4)
+func newIdempotencyKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // Version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // Variant RFC 4122
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
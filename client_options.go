@@ -9,18 +9,43 @@ import (
 // that overwrite default client options.
 type ClientOps func(c *ClientOptions)
 
+// RequestOps allow functional options to be supplied
+// that overwrite default options of a single request.
+type RequestOps func(r *requestOptions)
+
+// requestOptions holds all the configuration for a single request
+type requestOptions struct {
+	cacheEndpoint  CacheEndpoint // (optional) cache the response using the TTL of the endpoint
+	idempotencyKey string        // (optional) idempotency key sent in the Idempotency-Key header
+	ids            CallIDs       // (optional) IDs of the models the request is made for (Call.IDs)
+	ifNoneMatch    string        // (optional) entity tag of the cached response (revalidation)
+	operation      string        // (optional) name of the service method making the request (Call.Operation)
+}
+
+// WithIdempotencyKey will send the key in the Idempotency-Key header of the request.
+// The API will only process the request once per key, so the request is also safe to retry.
+func WithIdempotencyKey(key string) RequestOps {
+	return func(r *requestOptions) {
+		r.idempotencyKey = key
+	}
+}
+
 // defaultClientOptions will return a ClientOptions struct with the default settings
 //
 // Useful for starting with the default and then modifying as needed
 func defaultClientOptions() (opts *ClientOptions) {
 	// Set the default options
 	opts = &ClientOptions{
+		cacheTTLs:      make(map[CacheEndpoint]time.Duration, len(defaultCacheTTLs)),
 		env:            EnvironmentLive,
 		httpTimeout:    defaultHTTPTimeout,
 		requestTracing: false,
 		retryCount:     defaultRetryCount,
 		userAgent:      defaultUserAgent,
 	}
+	for endpoint, ttl := range defaultCacheTTLs {
+		opts.cacheTTLs[endpoint] = ttl
+	}
 	return
 }
 
@@ -76,6 +101,53 @@ func WithRetryCount(retries int) ClientOps {
 	}
 }
 
+// WithRetryPolicy will overwrite the default retry policy (BackoffPolicy using the retry count).
+func WithRetryPolicy(policy RetryPolicy) ClientOps {
+	return func(c *ClientOptions) {
+		c.retryPolicy = policy
+	}
+}
+
+// WithRetryNonIdempotent will allow retrying non-idempotent requests (POST) such as CreateConversion.
+// An idempotency key is generated for every POST request without one (see WithIdempotencyKey),
+// by default only idempotent requests (or requests with an idempotency key) are retried.
+func WithRetryNonIdempotent() ClientOps {
+	return func(c *ClientOptions) {
+		c.retryNonIdempotent = true
+	}
+}
+
+// WithRateLimit will limit the outgoing requests using a token bucket (requests per second & burst).
+// Rate limiting is disabled by default.
+func WithRateLimit(requestsPerSecond float64, burst int) ClientOps {
+	return func(c *ClientOptions) {
+		c.rateLimit = requestsPerSecond
+		c.rateLimitBurst = burst
+	}
+}
+
+// WithCache will cache the responses of the read endpoints (GetCampaign, GetCampaignBySlug, GetGoal,
+// GetAdvertiserProfile, CampaignsFeed and GetCurrentRate) using the given cache (nil uses a new LRUCache).
+// Expired responses are revalidated using the ETag (If-None-Match) and cached responses are invalidated
+// when an update or delete request succeeds.
+// Caching is disabled by default.
+func WithCache(cache Cache) ClientOps {
+	return func(c *ClientOptions) {
+		if cache == nil {
+			cache = NewLRUCache(defaultCacheSize)
+		}
+		c.cache = cache
+	}
+}
+
+// WithCacheTTL will overwrite the cache TTL of the endpoint (0 disables caching for the endpoint).
+// Default TTL is 1 minute for campaigns & goals, 5 minutes for advertiser profiles & feeds and 30 seconds for rates.
+func WithCacheTTL(endpoint CacheEndpoint, ttl time.Duration) ClientOps {
+	return func(c *ClientOptions) {
+		c.cacheTTLs[endpoint] = ttl
+	}
+}
+
 // WithUserAgent will overwrite the default useragent.
 // Default is package name + version.
 func WithUserAgent(userAgent string) ClientOps {
@@ -1,7 +1,9 @@
 package tonicpow
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"testing"
 	"time"
 
@@ -240,3 +242,170 @@ func BenchmarkDefaultClientOptions(b *testing.B) {
 		_ = defaultClientOptions()
 	}
 }
+
+// TestClient_RequestWithContext will test the method RequestWithContext()
+func TestClient_RequestWithContext(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	endpoint := fmt.Sprintf("%s/%s/details/%d", EnvironmentDevelopment.apiURL, modelGoal, testGoalID)
+
+	t.Run("valid context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestGoal())
+		assert.NoError(t, err)
+
+		var response *StandardResponse
+		response, err = client.RequestWithContext(
+			context.Background(), http.MethodGet,
+			fmt.Sprintf("/%s/details/%d", modelGoal, testGoalID),
+			nil, http.StatusOK,
+		)
+		assert.NoError(t, err)
+		assert.NotNil(t, response)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		mockResponseBlocking(http.MethodGet, endpoint)
+
+		var response *StandardResponse
+		response, err = client.RequestWithContext(
+			newCanceledContext(), http.MethodGet,
+			fmt.Sprintf("/%s/details/%d", modelGoal, testGoalID),
+			nil, http.StatusOK,
+		)
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, response)
+	})
+
+	t.Run("deadline exceeded", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		mockResponseBlocking(http.MethodGet, endpoint)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		var response *StandardResponse
+		response, err = client.RequestWithContext(
+			ctx, http.MethodGet,
+			fmt.Sprintf("/%s/details/%d", modelGoal, testGoalID),
+			nil, http.StatusOK,
+		)
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Nil(t, response)
+	})
+}
+
+// mockResponseHeaders is used for mocking a sequence of status codes while recording the request headers
+func mockResponseHeaders(method, endpoint string, statusCodes ...int) *[]http.Header {
+	headers := &[]http.Header{}
+	httpmock.Reset()
+	httpmock.RegisterResponder(method, endpoint, func(req *http.Request) (*http.Response, error) {
+		statusCode := statusCodes[len(statusCodes)-1]
+		if len(*headers) < len(statusCodes) {
+			statusCode = statusCodes[len(*headers)]
+		}
+		*headers = append(*headers, req.Header.Clone())
+		return httpmock.NewStringResponse(statusCode, `{}`), nil
+	})
+	return headers
+}
+
+// TestClient_RequestIdempotencyKey will test the idempotency key of the method RequestWithOptions()
+func TestClient_RequestIdempotencyKey(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	endpoint := fmt.Sprintf("%s/%s", EnvironmentDevelopment.apiURL, modelGoal)
+
+	t.Run("no idempotency key by default", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+
+		headers := mockResponseHeaders(http.MethodPost, endpoint, http.StatusCreated)
+		var response *StandardResponse
+		response, err = client.CreateGoal(newTestGoal())
+		assert.NoError(t, err)
+		assert.Empty(t, response.IdempotencyKey)
+		assert.Empty(t, (*headers)[0].Get(IdempotencyKeyHeader))
+	})
+
+	t.Run("custom idempotency key is sent and retried", func(t *testing.T) {
+		client, err := newRetryTestClient(WithRetryPolicy(newTestRetryPolicy(2)))
+		assert.NoError(t, err)
+
+		headers := mockResponseHeaders(http.MethodPost, endpoint, http.StatusServiceUnavailable, http.StatusCreated)
+		var response *StandardResponse
+		response, err = client.CreateGoal(newTestGoal(), WithIdempotencyKey("goal-key-123"))
+		assert.NoError(t, err)
+		assert.Equal(t, "goal-key-123", response.IdempotencyKey)
+		assert.Len(t, *headers, 2)
+		for _, header := range *headers {
+			assert.Equal(t, "goal-key-123", header.Get(IdempotencyKeyHeader))
+		}
+	})
+
+	t.Run("generated idempotency key when retrying non-idempotent requests", func(t *testing.T) {
+		client, err := newRetryTestClient(WithRetryPolicy(newTestRetryPolicy(2)), WithRetryNonIdempotent())
+		assert.NoError(t, err)
+
+		headers := mockResponseHeaders(http.MethodPost, endpoint, http.StatusBadGateway, http.StatusCreated)
+		var response *StandardResponse
+		response, err = client.CreateGoal(newTestGoal())
+		assert.NoError(t, err)
+		assert.Len(t, response.IdempotencyKey, 36)
+		assert.Len(t, *headers, 2)
+		assert.Equal(t, response.IdempotencyKey, (*headers)[0].Get(IdempotencyKeyHeader))
+		assert.Equal(t, response.IdempotencyKey, (*headers)[1].Get(IdempotencyKeyHeader))
+	})
+
+	t.Run("idempotency key of a custom request", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+
+		headers := mockResponseHeaders(http.MethodPost, endpoint, http.StatusCreated)
+		var response *StandardResponse
+		response, err = client.(*Client).RequestWithOptions(
+			context.Background(), http.MethodPost, "/"+modelGoal, newTestGoal(), http.StatusCreated,
+			WithIdempotencyKey("custom-key-123"),
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, "custom-key-123", response.IdempotencyKey)
+		assert.Equal(t, "custom-key-123", (*headers)[0].Get(IdempotencyKeyHeader))
+	})
+
+	t.Run("no generated idempotency key for idempotent requests", func(t *testing.T) {
+		client, err := newRetryTestClient(WithRetryNonIdempotent())
+		assert.NoError(t, err)
+
+		headers := mockResponseHeaders(http.MethodPut, endpoint, http.StatusOK)
+		_, err = client.UpdateGoal(newTestGoal())
+		assert.NoError(t, err)
+		assert.Empty(t, (*headers)[0].Get(IdempotencyKeyHeader))
+	})
+}
+
+// TestNewIdempotencyKey will test the method newIdempotencyKey()
+func TestNewIdempotencyKey(t *testing.T) {
+	t.Parallel()
+
+	key, err := newIdempotencyKey()
+	assert.NoError(t, err)
+	assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, key)
+
+	var another string
+	another, err = newIdempotencyKey()
+	assert.NoError(t, err)
+	assert.NotEqual(t, key, another)
+}
@@ -0,0 +1,359 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/tonicpow/go-tonicpow"
+)
+
+// listFlags are the sort & pagination flags of the list commands
+type listFlags struct {
+	all       bool
+	page      int
+	perPage   int
+	sortBy    string
+	sortOrder string
+}
+
+// register will add the list flags to the flag set
+func (l *listFlags) register(fs *flag.FlagSet) {
+	fs.BoolVar(&l.all, "all", false, "fetch every page of results")
+	fs.IntVar(&l.page, "page", 1, "page of results")
+	fs.IntVar(&l.perPage, "per-page", 20, "results per page")
+	fs.StringVar(&l.sortBy, "sort-by", "", "field to sort by")
+	fs.StringVar(&l.sortOrder, "sort-order", "", "sort order: asc or desc")
+}
+
+// iteratorOps will return the iterator options for fetching every page
+func (l *listFlags) iteratorOps() []tonicpow.IteratorOps {
+	return []tonicpow.IteratorOps{tonicpow.WithPageSize(l.perPage)}
+}
+
+// parseFlags will parse the flags (allowing flags after the arguments) and return the arguments
+func parseFlags(fs *flag.FlagSet, args []string, expected int) ([]string, error) {
+	fs.SetOutput(io.Discard)
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, fmt.Errorf("%w: %s", errUsage, err.Error())
+		}
+		if args = fs.Args(); len(args) == 0 {
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+	if len(positional) != expected {
+		return nil, errUsage
+	}
+	return positional, nil
+}
+
+// parseID will parse an id argument
+func parseID(value string) (uint64, error) {
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("%w: invalid id: %s", errUsage, value)
+	}
+	return id, nil
+}
+
+// parseIDArgs will parse the flags and a single id argument
+func parseIDArgs(fs *flag.FlagSet, args []string) (uint64, error) {
+	positional, err := parseFlags(fs, args, 1)
+	if err != nil {
+		return 0, err
+	}
+	return parseID(positional[0])
+}
+
+// getAdvertiser will print an advertiser profile
+func getAdvertiser(c *cli, args []string) error {
+	id, err := parseIDArgs(flag.NewFlagSet("get", flag.ContinueOnError), args)
+	if err != nil {
+		return err
+	}
+	profile, _, err := c.client.GetAdvertiserProfileWithContext(c.ctx, id)
+	if err != nil {
+		return err
+	}
+	return printItem(c.out, advertiserTable, profile)
+}
+
+// listAdvertiserApps will print the apps of an advertiser profile
+func listAdvertiserApps(c *cli, args []string) error {
+	fs := flag.NewFlagSet("apps", flag.ContinueOnError)
+	list := new(listFlags)
+	list.register(fs)
+	id, err := parseIDArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if list.all {
+		var apps []*tonicpow.App
+		iter := c.client.IterateAppsByAdvertiserProfile(c.ctx, id, list.sortBy, list.sortOrder, list.iteratorOps()...)
+		for iter.Next() {
+			apps = append(apps, iter.App())
+		}
+		if err = iter.Err(); err != nil {
+			return err
+		}
+		return printItems(c.out, appTable, apps)
+	}
+
+	results, _, err := c.client.ListAppsByAdvertiserProfileWithContext(
+		c.ctx, id, list.page, list.perPage, list.sortBy, list.sortOrder,
+	)
+	if err != nil {
+		return err
+	}
+	return printItems(c.out, appTable, results.Apps)
+}
+
+// listAdvertiserCampaigns will print the campaigns of an advertiser profile
+func listAdvertiserCampaigns(c *cli, args []string) error {
+	fs := flag.NewFlagSet("campaigns", flag.ContinueOnError)
+	list := new(listFlags)
+	list.register(fs)
+	id, err := parseIDArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	if list.all {
+		return printCampaigns(c, c.client.IterateCampaignsByAdvertiserProfile(
+			c.ctx, id, list.sortBy, list.sortOrder, list.iteratorOps()...,
+		))
+	}
+
+	results, _, err := c.client.ListCampaignsByAdvertiserProfileWithContext(
+		c.ctx, id, list.page, list.perPage, list.sortBy, list.sortOrder,
+	)
+	if err != nil {
+		return err
+	}
+	return printItems(c.out, campaignTable, results.Campaigns)
+}
+
+// getCampaign will print a campaign by id or slug
+func getCampaign(c *cli, args []string) error {
+	positional, err := parseFlags(flag.NewFlagSet("get", flag.ContinueOnError), args, 1)
+	if err != nil {
+		return err
+	}
+
+	var campaign *tonicpow.Campaign
+	if id, parseErr := strconv.ParseUint(positional[0], 10, 64); parseErr == nil {
+		campaign, _, err = c.client.GetCampaignWithContext(c.ctx, id)
+	} else {
+		campaign, _, err = c.client.GetCampaignBySlugWithContext(c.ctx, positional[0])
+	}
+	if err != nil {
+		return err
+	}
+	return printItem(c.out, campaignTable, campaign)
+}
+
+// listCampaigns will print the campaigns
+func listCampaigns(c *cli, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	list := new(listFlags)
+	list.register(fs)
+	query := fs.String("query", "", "search query")
+	minimumBalance := fs.Uint64("min-balance", 0, "minimum balance in satoshis")
+	expired := fs.Bool("expired", false, "include expired campaigns")
+	if _, err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	if list.all {
+		return printCampaigns(c, c.client.IterateCampaigns(
+			c.ctx, list.sortBy, list.sortOrder, *query, *minimumBalance, *expired, list.iteratorOps()...,
+		))
+	}
+
+	results, _, err := c.client.ListCampaignsWithContext(
+		c.ctx, list.page, list.perPage, list.sortBy, list.sortOrder, *query, *minimumBalance, *expired,
+	)
+	if err != nil {
+		return err
+	}
+	return printItems(c.out, campaignTable, results.Campaigns)
+}
+
+// listCampaignsByURL will print the campaigns for a target url
+func listCampaignsByURL(c *cli, args []string) error {
+	fs := flag.NewFlagSet("by-url", flag.ContinueOnError)
+	list := new(listFlags)
+	list.register(fs)
+	positional, err := parseFlags(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	if list.all {
+		return printCampaigns(c, c.client.IterateCampaignsByURL(
+			c.ctx, positional[0], list.sortBy, list.sortOrder, list.iteratorOps()...,
+		))
+	}
+
+	results, _, err := c.client.ListCampaignsByURLWithContext(
+		c.ctx, positional[0], list.page, list.perPage, list.sortBy, list.sortOrder,
+	)
+	if err != nil {
+		return err
+	}
+	return printItems(c.out, campaignTable, results.Campaigns)
+}
+
+// printCampaigns will print every campaign of the iterator
+func printCampaigns(c *cli, iter *tonicpow.CampaignIterator) error {
+	var campaigns []*tonicpow.Campaign
+	for iter.Next() {
+		campaigns = append(campaigns, iter.Campaign())
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	return printItems(c.out, campaignTable, campaigns)
+}
+
+// campaignsFeed will return a command that prints the campaigns feed (raw feed, ignores the output format)
+func campaignsFeed(feedType tonicpow.FeedType) func(c *cli, args []string) error {
+	return func(c *cli, args []string) error {
+		if _, err := parseFlags(flag.NewFlagSet(string(feedType), flag.ContinueOnError), args, 0); err != nil {
+			return err
+		}
+		feed, _, err := c.client.CampaignsFeedWithContext(c.ctx, feedType)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(c.out.w, feed)
+		return err
+	}
+}
+
+// campaignsFeedItems will print the parsed items of the campaigns feed
+func campaignsFeedItems(c *cli, args []string) error {
+	fs := flag.NewFlagSet("items", flag.ContinueOnError)
+	feedType := fs.String("type", string(tonicpow.FeedTypeRSS), "feed type (rss, atom or json)")
+	if _, err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+	feed, _, err := c.client.CampaignsFeedParsedWithContext(c.ctx, tonicpow.FeedType(*feedType))
+	if err != nil {
+		return err
+	}
+	return printItems(c.out, feedItemTable, feed.Items)
+}
+
+// getGoal will print a goal
+func getGoal(c *cli, args []string) error {
+	id, err := parseIDArgs(flag.NewFlagSet("get", flag.ContinueOnError), args)
+	if err != nil {
+		return err
+	}
+	goal, _, err := c.client.GetGoalWithContext(c.ctx, id)
+	if err != nil {
+		return err
+	}
+	return printItem(c.out, goalTable, goal)
+}
+
+// deleteGoal will delete a goal
+func deleteGoal(c *cli, args []string) error {
+	id, err := parseIDArgs(flag.NewFlagSet("delete", flag.ContinueOnError), args)
+	if err != nil {
+		return err
+	}
+	if _, _, err = c.client.DeleteGoalWithContext(c.ctx, id); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(c.out.w, "deleted goal: %d\n", id)
+	return err
+}
+
+// createConversion will create a conversion and print it
+func createConversion(c *cli, args []string) error {
+	fs := flag.NewFlagSet("create", flag.ContinueOnError)
+	goalID := fs.Uint64("goal-id", 0, "goal id")
+	goalName := fs.String("goal-name", "", "goal name")
+	session := fs.String("session", "", "visitor tncpw_session")
+	userID := fs.Uint64("user-id", 0, "tonicpow user id")
+	shortCode := fs.String("short-code", "", "link short code")
+	twitterID := fs.String("twitter-id", "", "twitter user id")
+	delay := fs.Uint64("delay", 0, "delay in minutes before the payout (allows canceling)")
+	amount := new(tonicpow.Decimal)
+	fs.TextVar(amount, "amount", tonicpow.Decimal{}, "purchase amount (e-commerce)")
+	dimensions := fs.String("dimensions", "", "custom dimensions")
+	idempotencyKey := fs.String("idempotency-key", "", "idempotency key (prevents duplicate conversions)")
+	if _, err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	opts := []tonicpow.ConversionOps{
+		tonicpow.WithGoalID(*goalID),
+		tonicpow.WithGoalName(*goalName),
+		tonicpow.WithTncpwSession(*session),
+		tonicpow.WithUserID(*userID),
+		tonicpow.WithShortCode(*shortCode),
+		tonicpow.WithTwitterID(*twitterID),
+		tonicpow.WithDelay(*delay),
+		tonicpow.WithPurchaseAmountDecimal(*amount),
+		tonicpow.WithCustomDimensions(*dimensions),
+		tonicpow.WithConversionIdempotencyKey(*idempotencyKey),
+	}
+	conversion, _, err := c.client.CreateConversionWithContext(c.ctx, opts...)
+	if err != nil {
+		return err
+	}
+	return printItem(c.out, conversionTable, conversion)
+}
+
+// getConversion will print a conversion
+func getConversion(c *cli, args []string) error {
+	id, err := parseIDArgs(flag.NewFlagSet("get", flag.ContinueOnError), args)
+	if err != nil {
+		return err
+	}
+	conversion, _, err := c.client.GetConversionWithContext(c.ctx, id)
+	if err != nil {
+		return err
+	}
+	return printItem(c.out, conversionTable, conversion)
+}
+
+// cancelConversion will cancel a (delayed) conversion and print it
+func cancelConversion(c *cli, args []string) error {
+	fs := flag.NewFlagSet("cancel", flag.ContinueOnError)
+	reason := fs.String("reason", "", "reason for canceling")
+	id, err := parseIDArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	conversion, _, err := c.client.CancelConversionWithContext(c.ctx, id, *reason)
+	if err != nil {
+		return err
+	}
+	return printItem(c.out, conversionTable, conversion)
+}
+
+// getRate will print the current rate of a currency
+func getRate(c *cli, args []string) error {
+	fs := flag.NewFlagSet("get", flag.ContinueOnError)
+	amount := new(tonicpow.Decimal)
+	fs.TextVar(amount, "amount", tonicpow.Decimal{}, "amount of the currency to convert")
+	positional, err := parseFlags(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	rate, _, err := c.client.GetCurrentRateDecimalWithContext(c.ctx, tonicpow.Currency(positional[0]), *amount)
+	if err != nil {
+		return err
+	}
+	return printItem(c.out, rateTable, rate)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/tonicpow/go-tonicpow"
+)
+
+// Environment variables
+const (
+	envAPIKey      = "TONICPOW_API_KEY"
+	envAPIURL      = "TONICPOW_API_URL"
+	envConfig      = "TONICPOW_CONFIG"
+	envEnvironment = "TONICPOW_ENVIRONMENT"
+	envOutput      = "TONICPOW_OUTPUT"
+
+	defaultConfigFile = ".tonicpow.json" // Default config file (in the home directory)
+)
+
+// config is the configuration of the tool (loaded from the config file, environment and flags)
+type config struct {
+	APIKey      string `json:"api_key"`
+	APIURL      string `json:"api_url"`
+	Environment string `json:"environment"`
+	Output      string `json:"output"`
+}
+
+// loadConfig will load the config file and override it with the environment
+//
+// A missing default config file is ignored, a missing custom config file is an error
+func loadConfig(filename string, getenv func(string) string) (*config, error) {
+	cfg := new(config)
+
+	// Find the config file
+	if len(filename) == 0 {
+		filename = getenv(envConfig)
+	}
+	optional := false
+	if len(filename) == 0 {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return cfg.override(configFromEnv(getenv)), nil
+		}
+		filename = filepath.Join(home, defaultConfigFile)
+		optional = true
+	}
+
+	// Read the config file
+	data, err := os.ReadFile(filename) //nolint:gosec // the config file is chosen by the user
+	if err != nil {
+		if !optional || !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+	} else if err = json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", filename, err)
+	}
+
+	return cfg.override(configFromEnv(getenv)), nil
+}
+
+// configFromEnv will return the configuration set in the environment
+func configFromEnv(getenv func(string) string) *config {
+	return &config{
+		APIKey:      getenv(envAPIKey),
+		APIURL:      getenv(envAPIURL),
+		Environment: getenv(envEnvironment),
+		Output:      getenv(envOutput),
+	}
+}
+
+// override will overwrite the configuration with any values set in the other configuration
+func (c *config) override(other *config) *config {
+	if len(other.APIKey) > 0 {
+		c.APIKey = other.APIKey
+	}
+	if len(other.APIURL) > 0 {
+		c.APIURL = other.APIURL
+	}
+	if len(other.Environment) > 0 {
+		c.Environment = other.Environment
+	}
+	if len(other.Output) > 0 {
+		c.Output = other.Output
+	}
+	return c
+}
+
+// newClient will create a new client using the configuration
+func (c *config) newClient() (tonicpow.ClientInterface, error) {
+	opts := []tonicpow.ClientOps{
+		tonicpow.WithAPIKey(c.APIKey),
+		tonicpow.WithEnvironmentString(c.Environment),
+	}
+	if len(c.APIURL) > 0 {
+		opts = append(opts, tonicpow.WithCustomEnvironment("custom", "custom", c.APIURL))
+	}
+	return tonicpow.NewClient(opts...)
+}
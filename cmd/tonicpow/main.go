@@ -0,0 +1,178 @@
+// Package main is the tonicpow command-line tool, a thin wrapper around the go-tonicpow client
+//
+// Usage:
+//
+//	tonicpow [global flags] <command> <subcommand> [flags] [arguments]
+//
+// The API key and environment are loaded from the flags, the environment
+// (TONICPOW_API_KEY, TONICPOW_ENVIRONMENT, TONICPOW_API_URL) or a JSON config file
+// (~/.tonicpow.json or TONICPOW_CONFIG), in that order of precedence.
+//
+// By TonicPow Inc (https://tonicpow.com)
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tonicpow/go-tonicpow"
+)
+
+// Exit codes
+const (
+	exitError = 1 // Request or output failed
+	exitUsage = 2 // Invalid command, flags or arguments
+)
+
+// errUsage is returned when the command, flags or arguments are invalid
+var errUsage = errors.New("invalid usage")
+
+// cli is the state shared by all commands
+type cli struct {
+	client tonicpow.ClientInterface
+	ctx    context.Context
+	out    *printer
+}
+
+// command is a subcommand handler
+type command struct {
+	run   func(c *cli, args []string) error
+	usage string
+}
+
+// commands are all the available commands by name and subcommand
+var commands = map[string]map[string]*command{
+	"advertisers": {
+		"apps":      {run: listAdvertiserApps, usage: "<profile-id> [list flags]"},
+		"campaigns": {run: listAdvertiserCampaigns, usage: "<profile-id> [list flags]"},
+		"get":       {run: getAdvertiser, usage: "<profile-id>"},
+	},
+	"apps": {
+		"list": {run: listAdvertiserApps, usage: "<profile-id> [list flags]"},
+	},
+	"campaigns": {
+		"by-url": {run: listCampaignsByURL, usage: "<target-url> [list flags]"},
+		"get":    {run: getCampaign, usage: "<campaign-id | slug>"},
+		"list":   {run: listCampaigns, usage: "[-query text] [-min-balance satoshis] [-expired] [list flags]"},
+	},
+	"conversions": {
+		"cancel": {run: cancelConversion, usage: "<conversion-id> [-reason text]"},
+		"create": {run: createConversion, usage: "(-goal-id id | -goal-name name) (-session | -user-id | -short-code | -twitter-id) [flags]"},
+		"get":    {run: getConversion, usage: "<conversion-id>"},
+	},
+	"feeds": {
+		"atom":  {run: campaignsFeed(tonicpow.FeedTypeAtom), usage: ""},
+		"items": {run: campaignsFeedItems, usage: "[-type rss|atom|json]"},
+		"json":  {run: campaignsFeed(tonicpow.FeedTypeJSON), usage: ""},
+		"rss":   {run: campaignsFeed(tonicpow.FeedTypeRSS), usage: ""},
+	},
+	"goals": {
+		"delete": {run: deleteGoal, usage: "<goal-id>"},
+		"get":    {run: getGoal, usage: "<goal-id>"},
+	},
+	"rates": {
+		"get": {run: getRate, usage: "<currency> [-amount value]"},
+	},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Getenv, os.Stdout, os.Stderr))
+}
+
+// run will run the command line tool and return the exit code
+func run(args []string, getenv func(string) string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("tonicpow", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	apiKey := fs.String("api-key", "", "API key (default $TONICPOW_API_KEY)")
+	apiURL := fs.String("api-url", "", "custom API url, overrides the environment (default $TONICPOW_API_URL)")
+	configFile := fs.String("config", "", "JSON config file (default $TONICPOW_CONFIG or ~/.tonicpow.json)")
+	environment := fs.String("env", "", "environment: live, staging or development (default $TONICPOW_ENVIRONMENT)")
+	output := fs.String("output", "", "output format: table, json or csv (default table)")
+	timeout := fs.Duration("timeout", 30*time.Second, "timeout for the command")
+	fs.Usage = func() { printUsage(fs) }
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	// Find the command
+	if fs.NArg() < 2 {
+		fs.Usage()
+		return exitUsage
+	}
+	cmd, ok := commands[fs.Arg(0)][fs.Arg(1)]
+	if !ok {
+		_, _ = fmt.Fprintf(stderr, "unknown command: %s %s\n\n", fs.Arg(0), fs.Arg(1))
+		fs.Usage()
+		return exitUsage
+	}
+
+	// Load the configuration
+	cfg, err := loadConfig(*configFile, getenv)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "error: %s\n", err.Error())
+		return exitUsage
+	}
+	cfg.override(&config{
+		APIKey:      *apiKey,
+		APIURL:      *apiURL,
+		Environment: *environment,
+		Output:      *output,
+	})
+
+	// Create the printer and client
+	c := &cli{out: &printer{format: strings.ToLower(cfg.Output), w: stdout}}
+	if err = c.out.validate(); err != nil {
+		_, _ = fmt.Fprintf(stderr, "error: %s\n", err.Error())
+		return exitUsage
+	}
+	if c.client, err = cfg.newClient(); err != nil {
+		_, _ = fmt.Fprintf(stderr, "error: %s\n", err.Error())
+		return exitUsage
+	}
+	var cancel context.CancelFunc
+	c.ctx, cancel = context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	// Run the command
+	if err = cmd.run(c, fs.Args()[2:]); errors.Is(err, errUsage) {
+		_, _ = fmt.Fprintf(stderr, "usage: tonicpow %s %s %s\n", fs.Arg(0), fs.Arg(1), cmd.usage)
+		return exitUsage
+	} else if err != nil {
+		_, _ = fmt.Fprintf(stderr, "error: %s\n", err.Error())
+		return exitError
+	}
+	return 0
+}
+
+// printUsage will print the usage of the tool and all the commands
+func printUsage(fs *flag.FlagSet) {
+	w := fs.Output()
+	_, _ = fmt.Fprintln(w, "usage: tonicpow [global flags] <command> <subcommand> [flags] [arguments]")
+	_, _ = fmt.Fprintln(w, "\ncommands:")
+	for _, name := range sortedNames(commands) {
+		for _, subcommand := range sortedNames(commands[name]) {
+			_, _ = fmt.Fprintf(w, "  %s %s %s\n", name, subcommand, commands[name][subcommand].usage)
+		}
+	}
+	_, _ = fmt.Fprintln(w, "\nlist flags:")
+	_, _ = fmt.Fprintln(w, "  -page, -per-page, -sort-by, -sort-order, -all (fetch every page)")
+	_, _ = fmt.Fprintln(w, "\nglobal flags:")
+	fs.PrintDefaults()
+}
+
+// sortedNames will return the keys of the map in order
+func sortedNames[T any](m map[string]T) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
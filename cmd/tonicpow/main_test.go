@@ -0,0 +1,291 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tonicpow/go-tonicpow"
+	"github.com/tonicpow/go-tonicpow/tonicpowtest"
+)
+
+// emptyConfigFile is used as the config file for tests (ignores the config file in the home directory)
+var emptyConfigFile string
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "tonicpow")
+	if err != nil {
+		panic(err)
+	}
+	emptyConfigFile = filepath.Join(dir, "config.json")
+	if err = os.WriteFile(emptyConfigFile, []byte("{}"), 0o600); err != nil {
+		panic(err)
+	}
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// newTestServer will return a fake API server seeded with an advertiser, app, campaigns and a goal
+func newTestServer(t *testing.T) *tonicpowtest.Server {
+	server := tonicpowtest.NewServer()
+	t.Cleanup(server.Close)
+
+	profile := server.AddAdvertiserProfile(&tonicpow.AdvertiserProfile{
+		HomepageURL: "https://tonicpow.com", Name: "TonicPow", UserID: 43,
+	})
+	server.AddApp(&tonicpow.App{AdvertiserProfileID: profile.ID, Name: "TonicPow App", UserID: 43})
+	for _, title := range []string{"First Campaign", "Second Campaign", "Third Campaign"} {
+		server.AddCampaign(&tonicpow.Campaign{
+			AdvertiserProfileID: profile.ID,
+			Balance:             tonicpow.NewDecimal(10, 0),
+			BalanceSatoshis:     20000000,
+			Description:         "Earn BSV for sharing things you like",
+			Goals:               []*tonicpow.Goal{{Name: "signup", PayoutRate: tonicpow.NewDecimal(5, 1), PayoutType: "flat"}},
+			PayPerClickRate:     tonicpow.NewDecimal(1, 2),
+			TargetType:          "url",
+			TargetURL:           "https://tonicpow.com",
+			Title:               title,
+		})
+	}
+	return server
+}
+
+// runTest will run the tool against the server and return the exit code and output
+func runTest(server *tonicpowtest.Server, env map[string]string, args ...string) (int, string, string) {
+	if server != nil {
+		args = append([]string{"-api-key", tonicpowtest.DefaultAPIKey, "-api-url", server.URL()}, args...)
+	}
+	if env == nil {
+		env = map[string]string{}
+	}
+	if _, ok := env[envConfig]; !ok {
+		env[envConfig] = emptyConfigFile
+	}
+	var stdout, stderr bytes.Buffer
+	code := run(args, func(key string) string { return env[key] }, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// TestRun_Usage will test the usage errors
+func TestRun_Usage(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t)
+
+	t.Run("missing command", func(t *testing.T) {
+		code, _, stderr := runTest(server, nil)
+		assert.Equal(t, exitUsage, code)
+		assert.Contains(t, stderr, "campaigns list")
+	})
+
+	t.Run("unknown command", func(t *testing.T) {
+		code, _, stderr := runTest(server, nil, "campaigns", "unknown")
+		assert.Equal(t, exitUsage, code)
+		assert.Contains(t, stderr, "unknown command: campaigns unknown")
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		code, _, stderr := runTest(server, nil, "goals", "get", "abc")
+		assert.Equal(t, exitUsage, code)
+		assert.Contains(t, stderr, "usage: tonicpow goals get <goal-id>")
+	})
+
+	t.Run("missing argument", func(t *testing.T) {
+		code, _, _ := runTest(server, nil, "campaigns", "by-url")
+		assert.Equal(t, exitUsage, code)
+	})
+
+	t.Run("unknown output format", func(t *testing.T) {
+		code, _, stderr := runTest(server, nil, "-output", "xml", "goals", "get", "4")
+		assert.Equal(t, exitUsage, code)
+		assert.Contains(t, stderr, "unknown output format")
+	})
+
+	t.Run("missing api key", func(t *testing.T) {
+		code, _, stderr := runTest(nil, nil, "goals", "get", "4")
+		assert.Equal(t, exitUsage, code)
+		assert.Contains(t, stderr, "missing an API Key")
+	})
+}
+
+// TestRun_Config will test loading the configuration from the config file, environment and flags
+func TestRun_Config(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t)
+
+	t.Run("environment", func(t *testing.T) {
+		code, stdout, _ := runTest(nil, map[string]string{
+			envAPIKey: tonicpowtest.DefaultAPIKey,
+			envAPIURL: server.URL(),
+			envOutput: formatJSON,
+		}, "goals", "get", "4")
+		assert.Equal(t, 0, code)
+		assert.Contains(t, stdout, `"name": "signup"`)
+	})
+
+	t.Run("config file", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "config.json")
+		data, err := json.Marshal(&config{APIKey: "wrong-key", APIURL: server.URL(), Output: formatCSV})
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filename, data, 0o600))
+
+		// Wrong key in the config file
+		code, _, stderr := runTest(nil, map[string]string{envConfig: filename}, "goals", "get", "4")
+		assert.Equal(t, exitError, code)
+		assert.Contains(t, stderr, "api key is invalid")
+
+		// Environment overrides the config file
+		var stdout string
+		code, stdout, _ = runTest(nil, map[string]string{
+			envAPIKey: tonicpowtest.DefaultAPIKey, envConfig: filename,
+		}, "goals", "get", "4")
+		assert.Equal(t, 0, code)
+		assert.True(t, strings.HasPrefix(stdout, "id,campaign_id,name"))
+
+		// Flags override the environment
+		code, stdout, _ = runTest(nil, map[string]string{
+			envAPIKey: "wrong-key", envConfig: filename,
+		}, "-api-key", tonicpowtest.DefaultAPIKey, "-output", "table", "goals", "get", "4")
+		assert.Equal(t, 0, code)
+		assert.True(t, strings.HasPrefix(stdout, "ID"))
+	})
+
+	t.Run("missing custom config file", func(t *testing.T) {
+		code, _, stderr := runTest(nil, map[string]string{
+			envConfig: filepath.Join(t.TempDir(), "missing.json"),
+		}, "-config", filepath.Join(t.TempDir(), "missing.json"), "goals", "get", "4")
+		assert.Equal(t, exitUsage, code)
+		assert.Contains(t, stderr, "failed to read config file")
+	})
+}
+
+// TestRun_Advertisers will test the advertisers & apps commands
+func TestRun_Advertisers(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t)
+
+	code, stdout, _ := runTest(server, nil, "advertisers", "get", "1")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, "https://tonicpow.com")
+
+	code, stdout, _ = runTest(server, nil, "-output", "json", "advertisers", "campaigns", "1", "-per-page", "2")
+	assert.Equal(t, 0, code)
+	var campaigns []*tonicpow.Campaign
+	require.NoError(t, json.Unmarshal([]byte(stdout), &campaigns))
+	assert.Len(t, campaigns, 2)
+
+	code, stdout, _ = runTest(server, nil, "apps", "list", "1", "-all")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, "TonicPow App")
+
+	code, _, stderr := runTest(server, nil, "advertisers", "get", "999")
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "error:")
+}
+
+// TestRun_Campaigns will test the campaigns & feeds commands
+func TestRun_Campaigns(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t)
+
+	t.Run("get by id and slug", func(t *testing.T) {
+		code, stdout, _ := runTest(server, nil, "campaigns", "get", "3")
+		assert.Equal(t, 0, code)
+		assert.Contains(t, stdout, "First Campaign")
+
+		code, stdout, _ = runTest(server, nil, "campaigns", "get", "second-campaign")
+		assert.Equal(t, 0, code)
+		assert.Contains(t, stdout, "Second Campaign")
+	})
+
+	t.Run("list as csv", func(t *testing.T) {
+		code, stdout, _ := runTest(
+			server, nil, "-output", "csv", "campaigns", "list", "-sort-by", "created_at", "-sort-order", "asc",
+		)
+		assert.Equal(t, 0, code)
+		records, err := csv.NewReader(strings.NewReader(stdout)).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 4)
+		assert.Equal(t, "First Campaign", records[1][2])
+	})
+
+	t.Run("list all pages", func(t *testing.T) {
+		code, stdout, _ := runTest(server, nil, "-output", "json", "campaigns", "list", "-all", "-per-page", "2")
+		assert.Equal(t, 0, code)
+		var campaigns []*tonicpow.Campaign
+		require.NoError(t, json.Unmarshal([]byte(stdout), &campaigns))
+		assert.Len(t, campaigns, 3)
+		assert.GreaterOrEqual(t, len(server.Requests()), 2)
+	})
+
+	t.Run("list by url", func(t *testing.T) {
+		code, stdout, _ := runTest(server, nil, "campaigns", "by-url", "https://tonicpow.com", "-page", "1")
+		assert.Equal(t, 0, code)
+		assert.Contains(t, stdout, "Third Campaign")
+	})
+
+	t.Run("feed", func(t *testing.T) {
+		code, stdout, _ := runTest(server, nil, "feeds", "rss")
+		assert.Equal(t, 0, code)
+		assert.Contains(t, stdout, "<rss")
+	})
+
+	t.Run("feed items", func(t *testing.T) {
+		code, stdout, _ := runTest(server, nil, "feeds", "items", "-type", "json")
+		assert.Equal(t, 0, code)
+		assert.Contains(t, stdout, "https://tonicpow.com/campaign/")
+	})
+}
+
+// TestRun_GoalsAndConversions will test the goals & conversions commands
+func TestRun_GoalsAndConversions(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t)
+
+	code, stdout, _ := runTest(
+		server, nil, "-output", "json", "conversions", "create",
+		"-goal-name", "signup", "-session", "session-123", "-delay", "10", "-idempotency-key", "order-1",
+	)
+	require.Equal(t, 0, code)
+	var conversion *tonicpow.Conversion
+	require.NoError(t, json.Unmarshal([]byte(stdout), &conversion))
+	assert.Equal(t, tonicpow.ConversionStatusDelayed, conversion.Status)
+
+	code, stdout, _ = runTest(server, nil, "conversions", "cancel", "-reason", "refund", formatUint(conversion.ID))
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, tonicpow.ConversionStatusCanceled)
+
+	code, stdout, _ = runTest(server, nil, "conversions", "get", formatUint(conversion.ID))
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, tonicpow.ConversionStatusCanceled)
+
+	code, stdout, _ = runTest(server, nil, "goals", "delete", "4")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "deleted goal: 4\n", stdout)
+	assert.Nil(t, server.Goal(4))
+
+	code, _, _ = runTest(server, nil, "conversions", "create", "-session", "session-123")
+	assert.Equal(t, exitError, code)
+}
+
+// TestRun_Rates will test the rates command
+func TestRun_Rates(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t)
+
+	code, stdout, _ := runTest(server, nil, "-output", "csv", "rates", "get", "usd", "-amount", "0.01")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "currency,currency_amount,price_in_satoshis\nusd,0.01,20000\n", stdout)
+}
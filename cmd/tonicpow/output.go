@@ -0,0 +1,182 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/tonicpow/go-tonicpow"
+)
+
+// Output formats
+const (
+	formatCSV   = "csv"
+	formatJSON  = "json"
+	formatTable = "table"
+)
+
+// printer will write the results in the chosen output format
+type printer struct {
+	format string
+	w      io.Writer
+}
+
+// validate will check the output format
+func (p *printer) validate() error {
+	switch p.format {
+	case "":
+		p.format = formatTable
+	case formatCSV, formatJSON, formatTable:
+	default:
+		return fmt.Errorf("unknown output format: %s (table, json or csv)", p.format)
+	}
+	return nil
+}
+
+// table describes how to print a model as rows
+type table[T any] struct {
+	columns []string
+	row     func(item T) []string
+}
+
+// printItem will print a single model (as an object when using json)
+func printItem[T any](p *printer, t *table[T], item T) error {
+	if p.format == formatJSON {
+		return p.writeJSON(item)
+	}
+	return p.writeRows(t.columns, [][]string{t.row(item)})
+}
+
+// printItems will print a list of models (as an array when using json)
+func printItems[T any](p *printer, t *table[T], items []T) error {
+	if p.format == formatJSON {
+		if items == nil {
+			items = []T{}
+		}
+		return p.writeJSON(items)
+	}
+	rows := make([][]string, 0, len(items))
+	for _, item := range items {
+		rows = append(rows, t.row(item))
+	}
+	return p.writeRows(t.columns, rows)
+}
+
+// writeJSON will write the value as indented JSON
+func (p *printer) writeJSON(value interface{}) error {
+	encoder := json.NewEncoder(p.w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// writeRows will write the rows as a table or csv
+func (p *printer) writeRows(columns []string, rows [][]string) error {
+	if p.format == formatCSV {
+		w := csv.NewWriter(p.w)
+		if err := w.Write(columns); err != nil {
+			return err
+		}
+		if err := w.WriteAll(rows); err != nil {
+			return err
+		}
+		return w.Error()
+	}
+
+	w := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	header := make([]string, 0, len(columns))
+	for _, column := range columns {
+		header = append(header, strings.ToUpper(column))
+	}
+	if _, err := fmt.Fprintln(w, strings.Join(header, "\t")); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if _, err := fmt.Fprintln(w, strings.Join(row, "\t")); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// formatting helpers
+func formatUint(value uint64) string { return strconv.FormatUint(value, 10) }
+
+// advertiserTable is used for printing advertiser profiles
+var advertiserTable = &table[*tonicpow.AdvertiserProfile]{
+	columns: []string{"id", "name", "homepage_url", "user_id", "domain_verified", "unlisted"},
+	row: func(p *tonicpow.AdvertiserProfile) []string {
+		return []string{
+			formatUint(p.ID), p.Name, p.HomepageURL, formatUint(p.UserID),
+			strconv.FormatBool(p.DomainVerified), strconv.FormatBool(p.Unlisted),
+		}
+	},
+}
+
+// appTable is used for printing apps
+var appTable = &table[*tonicpow.App]{
+	columns: []string{"id", "advertiser_profile_id", "name", "user_id", "webhook_url"},
+	row: func(a *tonicpow.App) []string {
+		return []string{formatUint(a.ID), formatUint(a.AdvertiserProfileID), a.Name, formatUint(a.UserID), a.WebhookURL}
+	},
+}
+
+// campaignTable is used for printing campaigns
+var campaignTable = &table[*tonicpow.Campaign]{
+	columns: []string{
+		"id", "slug", "title", "balance", "currency", "balance_satoshis",
+		"pay_per_click_rate", "links_created", "paid_clicks", "expires_at",
+	},
+	row: func(c *tonicpow.Campaign) []string {
+		return []string{
+			formatUint(c.ID), c.Slug, c.Title, c.Balance.String(), string(c.Currency), formatUint(c.BalanceSatoshis),
+			c.PayPerClickRate.String(), formatUint(c.LinksCreated), formatUint(c.PaidClicks), c.ExpiresAt,
+		}
+	},
+}
+
+// conversionTable is used for printing conversions
+var conversionTable = &table[*tonicpow.Conversion]{
+	columns: []string{"id", "campaign_id", "goal_id", "goal_name", "user_id", "status", "amount", "payout_after", "tx_id"},
+	row: func(c *tonicpow.Conversion) []string {
+		return []string{
+			formatUint(c.ID), formatUint(c.CampaignID), formatUint(c.GoalID), c.GoalName, formatUint(c.UserID),
+			c.Status, c.Amount.String(), c.PayoutAfter, c.TxID,
+		}
+	},
+}
+
+// feedItemTable is used for printing feed items
+var feedItemTable = &table[*tonicpow.FeedItem]{
+	columns: []string{"id", "slug", "title", "link", "image_url", "published_at"},
+	row: func(i *tonicpow.FeedItem) []string {
+		var published string
+		if !i.PublishedAt.IsZero() {
+			published = i.PublishedAt.Format(time.RFC3339)
+		}
+		return []string{i.ID, i.Slug, i.Title, i.Link, i.ImageURL, published}
+	},
+}
+
+// goalTable is used for printing goals
+var goalTable = &table[*tonicpow.Goal]{
+	columns: []string{"id", "campaign_id", "name", "title", "payout_type", "payout_rate", "payouts", "last_converted_at"},
+	row: func(g *tonicpow.Goal) []string {
+		return []string{
+			formatUint(g.ID), formatUint(g.CampaignID), g.Name, g.Title, string(g.PayoutType),
+			g.PayoutRate.String(), strconv.Itoa(g.Payouts), g.LastConvertedAt,
+		}
+	},
+}
+
+// rateTable is used for printing rates
+var rateTable = &table[*tonicpow.Rate]{
+	columns: []string{"currency", "currency_amount", "price_in_satoshis"},
+	row: func(r *tonicpow.Rate) []string {
+		return []string{string(r.Currency), r.CurrencyAmount.String(), strconv.FormatInt(r.PriceInSatoshis, 10)}
+	},
+}
@@ -0,0 +1,389 @@
+package tonicpow
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// MinimumCancelWindow is the time that must remain before the payout for a delayed conversion to be canceled
+	MinimumCancelWindow = time.Minute
+
+	// defaultCancelWarning is the default time before the cancellation window closes to warn (see WithCancelWarning)
+	defaultCancelWarning = 5 * time.Minute
+
+	// defaultDelayCheckInterval is the default interval between checks when started (see DelayManager.Start)
+	defaultDelayCheckInterval = 15 * time.Second
+)
+
+var (
+	// ErrCancelWindowClosed is returned when a delayed conversion can no longer be canceled (see MinimumCancelWindow)
+	ErrCancelWindowClosed = errors.New("cancellation window closed")
+
+	// ErrReferenceNotFound is returned when no delayed conversion is tracked for the reference
+	ErrReferenceNotFound = errors.New("conversion reference not found")
+)
+
+// PayoutAfterTime will parse the PayoutAfter timestamp of a delayed conversion (zero if not delayed)
+func (c *Conversion) PayoutAfterTime() (time.Time, error) {
+	if len(c.PayoutAfter) == 0 {
+		return time.Time{}, nil
+	}
+	return parseTimestamp(c.PayoutAfter)
+}
+
+// DelayedConversion is a delayed conversion tracked by your own reference (order id, etc.)
+type DelayedConversion struct {
+	Conversion  *Conversion // The conversion (as created)
+	PayoutAfter time.Time   // When the conversion is paid out
+	Reference   string      // Your reference (order id, etc.)
+}
+
+// CancelDeadline will return the time the cancellation window closes (MinimumCancelWindow before the payout)
+func (d *DelayedConversion) CancelDeadline() time.Time {
+	return d.PayoutAfter.Add(-MinimumCancelWindow)
+}
+
+// CancelWarningFunc is called when the cancellation window of a delayed conversion is about to close
+type CancelWarningFunc func(conversion *DelayedConversion, remaining time.Duration)
+
+// DelayManagerOps allow functional options to be supplied to NewDelayManager
+type DelayManagerOps func(m *DelayManager)
+
+// WithCancelWarning will set the function called (once per conversion) when the cancellation window
+// closes within the lead time (default lead time is 5 minutes)
+func WithCancelWarning(lead time.Duration, warn CancelWarningFunc) DelayManagerOps {
+	return func(m *DelayManager) {
+		if lead > 0 {
+			m.warningLead = lead
+		}
+		m.warn = warn
+	}
+}
+
+// WithDelayCheckInterval will set the interval between checks when started (default is 15 seconds)
+func WithDelayCheckInterval(interval time.Duration) DelayManagerOps {
+	return func(m *DelayManager) {
+		if interval > 0 {
+			m.checkInterval = interval
+		}
+	}
+}
+
+// withDelayManagerClock will set the clock of the manager (for tests)
+func withDelayManagerClock(now func() time.Time) DelayManagerOps {
+	return func(m *DelayManager) {
+		m.now = now
+	}
+}
+
+// DelayManager tracks delayed conversions (see WithDelay) by your own reference,
+// so they can be canceled by reference (on a refund, etc.) until the cancellation window closes
+//
+// A reference can have several conversions (different goals, users or amounts), which are all canceled together.
+//
+// Conversions are forgotten once canceled or paid out (after PayoutAfter).
+// The tracked conversions are only kept in memory and are lost when the process restarts,
+// use Track to track them again (from your own records) after a restart.
+// A DelayManager is safe for concurrent use
+type DelayManager struct {
+	checkInterval time.Duration
+	conversions   map[string][]*DelayedConversion
+	done          chan struct{}
+	mu            sync.Mutex
+	now           func() time.Time
+	service       ConversionService
+	stop          context.CancelFunc
+	warn          CancelWarningFunc
+	warned        map[*DelayedConversion]bool
+	warningLead   time.Duration
+}
+
+// NewDelayManager will return a manager creating and canceling conversions using the service (the Client)
+func NewDelayManager(service ConversionService, opts ...DelayManagerOps) *DelayManager {
+	m := &DelayManager{
+		checkInterval: defaultDelayCheckInterval,
+		conversions:   make(map[string][]*DelayedConversion),
+		now:           time.Now,
+		service:       service,
+		warned:        make(map[*DelayedConversion]bool),
+		warningLead:   defaultCancelWarning,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Create will fire a delayed conversion (WithDelay is required) and track it by the reference
+//
+// The idempotency key is a hash of the reference, goal, user and amount,
+// unless WithConversionIdempotencyKey is given
+func (m *DelayManager) Create(ctx context.Context, reference string,
+	opts ...ConversionOps) (*DelayedConversion, error) {
+
+	// Must have a reference and a delay
+	if len(reference) == 0 {
+		return nil, errors.New("missing required attribute: reference")
+	}
+	options := new(conversionOptions)
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.delayInMinutes == 0 {
+		return nil, fmt.Errorf("missing required attribute: %s", fieldDelayInMinutes)
+	}
+
+	if len(options.idempotencyKey) == 0 {
+		opts = append(opts, WithConversionIdempotencyKey(delayIdempotencyKey(reference, options)))
+	}
+
+	conversion, _, err := m.service.CreateConversionWithContext(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return m.Track(reference, conversion)
+}
+
+// delayIdempotencyKey will return the default idempotency key of a delayed conversion, a hash of the
+// reference, goal, user and amount (separate conversions sharing a reference are not merged by the API)
+func delayIdempotencyKey(reference string, options *conversionOptions) string {
+	hash := sha256.New()
+	for _, part := range []string{
+		reference,
+		strconv.FormatUint(options.goalID, 10), options.goalName,
+		strconv.FormatUint(options.tonicPowUserID, 10), options.shortCode, options.twitterID, options.tncpwSession,
+		options.purchaseAmount.String(),
+	} {
+		_, _ = hash.Write([]byte(strconv.Quote(part))) // Quoted, so the parts cannot run into each other
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Track will track an existing delayed conversion by the reference
+// (added to the conversions of the reference, replacing a tracked conversion with the same ID)
+func (m *DelayManager) Track(reference string, conversion *Conversion) (*DelayedConversion, error) {
+	if len(reference) == 0 {
+		return nil, errors.New("missing required attribute: reference")
+	} else if conversion == nil || conversion.Status != ConversionStatusDelayed {
+		return nil, fmt.Errorf("conversion for %s is not delayed", reference)
+	}
+	payoutAfter, err := conversion.PayoutAfterTime()
+	if err != nil {
+		return nil, err
+	}
+
+	delayed := &DelayedConversion{Conversion: conversion, PayoutAfter: payoutAfter, Reference: reference}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tracked := m.conversions[reference]
+	for i, existing := range tracked {
+		if existing.Conversion.ID == conversion.ID {
+			delete(m.warned, existing)
+			tracked[i] = delayed
+			return delayed, nil
+		}
+	}
+	m.conversions[reference] = append(tracked, delayed)
+	return delayed, nil
+}
+
+// Cancel will cancel every delayed conversion of the reference and stop tracking the canceled conversions
+//
+// Returns the canceled conversions, and an error for each conversion that could not be canceled
+// (those stay tracked): ErrCancelWindowClosed (without calling the API) if less than MinimumCancelWindow remains
+func (m *DelayManager) Cancel(ctx context.Context, reference, reason string) ([]*Conversion, error) {
+	tracked, err := m.Get(reference)
+	if err != nil {
+		return nil, err
+	}
+
+	var canceled []*Conversion
+	var errs []error
+	for _, delayed := range tracked {
+		if !m.now().Before(delayed.CancelDeadline()) {
+			errs = append(errs, fmt.Errorf("%w: %s (conversion %d) is paid out at %s", ErrCancelWindowClosed,
+				reference, delayed.Conversion.ID, delayed.PayoutAfter.Format(time.RFC3339)))
+			continue
+		}
+		conversion, _, cancelErr := m.service.CancelConversionWithContext(ctx, delayed.Conversion.ID, reason)
+		if cancelErr != nil {
+			errs = append(errs, cancelErr)
+			continue
+		}
+		m.forget(delayed)
+		canceled = append(canceled, conversion)
+	}
+	return canceled, errors.Join(errs...)
+}
+
+// Get will return the delayed conversions of the reference (the earliest payout first)
+func (m *DelayManager) Get(reference string) ([]*DelayedConversion, error) {
+	m.mu.Lock()
+	tracked := append([]*DelayedConversion(nil), m.conversions[reference]...)
+	m.mu.Unlock()
+	if len(tracked) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrReferenceNotFound, reference)
+	}
+	sortDelayedConversions(tracked)
+	return tracked, nil
+}
+
+// Forget will stop tracking the conversions of the reference
+func (m *DelayManager) Forget(reference string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, delayed := range m.conversions[reference] {
+		delete(m.warned, delayed)
+	}
+	delete(m.conversions, reference)
+}
+
+// forget will stop tracking the conversion (other conversions of the reference are still tracked)
+func (m *DelayManager) forget(delayed *DelayedConversion) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.removeLocked(delayed)
+}
+
+// removeLocked will remove the conversion (the lock must be held)
+func (m *DelayManager) removeLocked(delayed *DelayedConversion) {
+	delete(m.warned, delayed)
+	tracked := m.conversions[delayed.Reference]
+	for i, existing := range tracked {
+		if existing == delayed {
+			tracked = append(tracked[:i:i], tracked[i+1:]...)
+			break
+		}
+	}
+	if len(tracked) == 0 {
+		delete(m.conversions, delayed.Reference)
+	} else {
+		m.conversions[delayed.Reference] = tracked
+	}
+}
+
+// isTrackedLocked will return true if the conversion is still tracked (the lock must be held)
+func (m *DelayManager) isTrackedLocked(delayed *DelayedConversion) bool {
+	for _, existing := range m.conversions[delayed.Reference] {
+		if existing == delayed {
+			return true
+		}
+	}
+	return false
+}
+
+// Conversions will return the tracked conversions (the earliest payout first)
+func (m *DelayManager) Conversions() []*DelayedConversion {
+	m.mu.Lock()
+	conversions := make([]*DelayedConversion, 0, len(m.conversions))
+	for _, tracked := range m.conversions {
+		conversions = append(conversions, tracked...)
+	}
+	m.mu.Unlock()
+
+	sortDelayedConversions(conversions)
+	return conversions
+}
+
+// sortDelayedConversions will sort the conversions by payout (then by reference and conversion ID)
+func sortDelayedConversions(conversions []*DelayedConversion) {
+	sort.Slice(conversions, func(i, j int) bool {
+		a, b := conversions[i], conversions[j]
+		if !a.PayoutAfter.Equal(b.PayoutAfter) {
+			return a.PayoutAfter.Before(b.PayoutAfter)
+		} else if a.Reference != b.Reference {
+			return a.Reference < b.Reference
+		}
+		return a.Conversion.ID < b.Conversion.ID
+	})
+}
+
+// Closing will return the conversions that can still be canceled, but not after the duration (the earliest first)
+func (m *DelayManager) Closing(within time.Duration) []*DelayedConversion {
+	now := m.now()
+	var closing []*DelayedConversion
+	for _, delayed := range m.Conversions() {
+		if deadline := delayed.CancelDeadline(); now.Before(deadline) && !deadline.After(now.Add(within)) {
+			closing = append(closing, delayed)
+		}
+	}
+	return closing
+}
+
+// Check will warn about the conversions closing within the warning lead time (see WithCancelWarning)
+// and forget the conversions that have been paid out
+func (m *DelayManager) Check() {
+	now := m.now()
+
+	// Forget the paid out conversions
+	m.mu.Lock()
+	for _, tracked := range m.conversions {
+		for _, delayed := range tracked {
+			if !now.Before(delayed.PayoutAfter) {
+				m.removeLocked(delayed)
+			}
+		}
+	}
+	m.mu.Unlock()
+
+	if m.warn == nil {
+		return
+	}
+	for _, delayed := range m.Closing(m.warningLead) {
+		m.mu.Lock()
+		warned := m.warned[delayed] || !m.isTrackedLocked(delayed)
+		if !warned {
+			m.warned[delayed] = true
+		}
+		m.mu.Unlock()
+		if !warned {
+			m.warn(delayed, delayed.CancelDeadline().Sub(now))
+		}
+	}
+}
+
+// Start will check the conversions now and on every check interval, until Stop is called or the context is done
+func (m *DelayManager) Start(ctx context.Context) {
+	m.Stop()
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	m.mu.Lock()
+	m.stop, m.done = cancel, done
+	m.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(m.checkInterval)
+		defer ticker.Stop()
+		for {
+			m.Check()
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop will stop checking the conversions
+func (m *DelayManager) Stop() {
+	m.mu.Lock()
+	stop, done := m.stop, m.done
+	m.stop, m.done = nil, nil
+	m.mu.Unlock()
+
+	if stop != nil {
+		stop()
+		<-done
+	}
+}
@@ -0,0 +1,341 @@
+package tonicpow
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testDelayService is a ConversionService creating delayed conversions (canceled if more than a minute remains)
+type testDelayService struct {
+	ConversionService
+	clock       *testClock
+	conversions map[uint64]*Conversion
+	keys        []string
+	mu          sync.Mutex
+}
+
+// CreateConversionWithContext will create a (delayed) conversion
+func (s *testDelayService) CreateConversionWithContext(_ context.Context,
+	opts ...ConversionOps) (*Conversion, *StandardResponse, error) {
+	options := new(conversionOptions)
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = append(s.keys, options.idempotencyKey)
+	conversion := &Conversion{GoalID: options.goalID, ID: uint64(len(s.keys)), Status: ConversionStatusPaid}
+	if options.delayInMinutes > 0 {
+		conversion.Status = ConversionStatusDelayed
+		conversion.PayoutAfter = s.clock.Now().Add(time.Duration(options.delayInMinutes) * time.Minute).
+			Format(timestampLayout)
+	}
+	s.conversions[conversion.ID] = conversion
+	return conversion, nil, nil
+}
+
+// CancelConversionWithContext will cancel the conversion
+func (s *testDelayService) CancelConversionWithContext(_ context.Context, conversionID uint64,
+	reason string) (*Conversion, *StandardResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	conversion, ok := s.conversions[conversionID]
+	if !ok {
+		return nil, nil, &Error{StatusCode: http.StatusNotFound}
+	}
+	payoutAfter, _ := conversion.PayoutAfterTime()
+	if payoutAfter.Sub(s.clock.Now()) <= MinimumCancelWindow {
+		return nil, nil, &Error{Message: "conversion can no longer be canceled", StatusCode: http.StatusBadRequest}
+	}
+	conversion.Status, conversion.StatusData = ConversionStatusCanceled, reason
+	return conversion, nil, nil
+}
+
+// newTestDelayManager will return a manager using a test service and a test clock
+func newTestDelayManager(opts ...DelayManagerOps) (*DelayManager, *testDelayService, *testClock) {
+	clock := &testClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	service := &testDelayService{clock: clock, conversions: make(map[uint64]*Conversion)}
+	return NewDelayManager(service, append([]DelayManagerOps{withDelayManagerClock(clock.Now)}, opts...)...),
+		service, clock
+}
+
+// TestConversion_PayoutAfterTime will test parsing the payout timestamp
+func TestConversion_PayoutAfterTime(t *testing.T) {
+	t.Parallel()
+
+	payoutAfter, err := (&Conversion{PayoutAfter: "2024-01-01 12:30:00"}).PayoutAfterTime()
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 1, 1, 12, 30, 0, 0, time.UTC), payoutAfter)
+
+	payoutAfter, err = (&Conversion{PayoutAfter: "2024-01-01T12:30:00Z"}).PayoutAfterTime()
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 1, 1, 12, 30, 0, 0, time.UTC), payoutAfter)
+
+	payoutAfter, err = (&Conversion{}).PayoutAfterTime()
+	require.NoError(t, err)
+	assert.True(t, payoutAfter.IsZero())
+
+	_, err = (&Conversion{PayoutAfter: "tomorrow"}).PayoutAfterTime()
+	require.Error(t, err)
+}
+
+// TestDelayManager_Create will test creating and tracking delayed conversions
+func TestDelayManager_Create(t *testing.T) {
+	t.Parallel()
+
+	t.Run("create and track", func(t *testing.T) {
+		manager, service, clock := newTestDelayManager()
+
+		delayed, err := manager.Create(context.Background(), "order-1",
+			WithGoalID(testGoalID), WithTncpwSession(testTncpwSession), WithDelay(30))
+		require.NoError(t, err)
+		assert.Equal(t, "order-1", delayed.Reference)
+		assert.Equal(t, clock.Now().Add(30*time.Minute), delayed.PayoutAfter)
+		assert.Equal(t, clock.Now().Add(29*time.Minute), delayed.CancelDeadline())
+		require.Len(t, service.keys, 1)
+		assert.Len(t, service.keys[0], 64)
+
+		tracked, err := manager.Get("order-1")
+		require.NoError(t, err)
+		assert.Equal(t, []*DelayedConversion{delayed}, tracked)
+	})
+
+	t.Run("delay is required", func(t *testing.T) {
+		manager, service, _ := newTestDelayManager()
+
+		_, err := manager.Create(context.Background(), "order-1", WithGoalID(testGoalID))
+		require.Error(t, err)
+		_, err = manager.Create(context.Background(), "", WithGoalID(testGoalID), WithDelay(30))
+		require.Error(t, err)
+		assert.Empty(t, service.keys)
+	})
+
+	t.Run("conversions sharing a reference have separate keys", func(t *testing.T) {
+		manager, service, _ := newTestDelayManager()
+
+		for _, opts := range [][]ConversionOps{
+			{WithGoalID(testGoalID), WithUserID(testUserID), WithPurchaseAmountDecimal(NewDecimal(10, 0))},
+			{WithGoalID(testGoalID), WithUserID(testUserID), WithPurchaseAmountDecimal(NewDecimal(25, 0))},
+			{WithGoalID(testGoalID + 1), WithUserID(testUserID), WithPurchaseAmountDecimal(NewDecimal(10, 0))},
+			{WithGoalID(testGoalID), WithUserID(testUserID + 1), WithPurchaseAmountDecimal(NewDecimal(10, 0))},
+			{WithGoalID(testGoalID), WithUserID(testUserID), WithPurchaseAmountDecimal(NewDecimal(10, 0))},
+		} {
+			_, err := manager.Create(context.Background(), "user-1", append(opts, WithDelay(30))...)
+			require.NoError(t, err)
+		}
+		require.Len(t, service.keys, 5)
+		assert.Len(t, map[string]bool{
+			service.keys[0]: true, service.keys[1]: true, service.keys[2]: true, service.keys[3]: true,
+		}, 4)
+		assert.Equal(t, service.keys[0], service.keys[4]) // The same conversion (retried) has the same key
+	})
+
+	t.Run("custom idempotency key", func(t *testing.T) {
+		manager, service, _ := newTestDelayManager()
+
+		_, err := manager.Create(context.Background(), "order-1",
+			WithGoalID(testGoalID), WithDelay(30), WithConversionIdempotencyKey("key-1"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"key-1"}, service.keys)
+	})
+
+	t.Run("track only delayed conversions", func(t *testing.T) {
+		manager, _, _ := newTestDelayManager()
+
+		_, err := manager.Track("order-1", &Conversion{ID: 1, Status: ConversionStatusPaid})
+		require.Error(t, err)
+		_, err = manager.Track("order-1", &Conversion{ID: 1, PayoutAfter: "soon", Status: ConversionStatusDelayed})
+		require.Error(t, err)
+		_, err = manager.Track("order-1", nil)
+		require.Error(t, err)
+		assert.Empty(t, manager.Conversions())
+	})
+}
+
+// TestDelayManager_Cancel will test canceling conversions by reference
+func TestDelayManager_Cancel(t *testing.T) {
+	t.Parallel()
+
+	t.Run("cancel by reference", func(t *testing.T) {
+		manager, _, clock := newTestDelayManager()
+		_, err := manager.Create(context.Background(), "order-1", WithGoalID(testGoalID), WithDelay(30))
+		require.NoError(t, err)
+
+		clock.Advance(28 * time.Minute)
+		conversions, err := manager.Cancel(context.Background(), "order-1", "refund")
+		require.NoError(t, err)
+		require.Len(t, conversions, 1)
+		assert.Equal(t, ConversionStatusCanceled, conversions[0].Status)
+		assert.Equal(t, "refund", conversions[0].StatusData)
+
+		_, err = manager.Get("order-1")
+		assert.ErrorIs(t, err, ErrReferenceNotFound)
+	})
+
+	t.Run("cancel every conversion of the reference", func(t *testing.T) {
+		manager, service, clock := newTestDelayManager()
+		first, err := manager.Create(context.Background(), "user-1",
+			WithGoalID(testGoalID), WithUserID(testUserID), WithPurchaseAmountDecimal(NewDecimal(10, 0)), WithDelay(30))
+		require.NoError(t, err)
+		second, err := manager.Create(context.Background(), "user-1",
+			WithGoalID(testGoalID), WithUserID(testUserID), WithPurchaseAmountDecimal(NewDecimal(25, 0)), WithDelay(60))
+		require.NoError(t, err)
+		require.NotEqual(t, first.Conversion.ID, second.Conversion.ID)
+
+		tracked, err := manager.Get("user-1")
+		require.NoError(t, err)
+		assert.Equal(t, []*DelayedConversion{first, second}, tracked)
+
+		clock.Advance(10 * time.Minute)
+		conversions, err := manager.Cancel(context.Background(), "user-1", "refund")
+		require.NoError(t, err)
+		require.Len(t, conversions, 2)
+		for _, conversion := range conversions {
+			assert.Equal(t, ConversionStatusCanceled, conversion.Status)
+		}
+		assert.Equal(t, ConversionStatusCanceled, service.conversions[first.Conversion.ID].Status)
+		assert.Equal(t, ConversionStatusCanceled, service.conversions[second.Conversion.ID].Status)
+		assert.Empty(t, manager.Conversions())
+	})
+
+	t.Run("conversions with a closed window stay tracked", func(t *testing.T) {
+		manager, _, clock := newTestDelayManager()
+		_, err := manager.Create(context.Background(), "user-1", WithGoalID(testGoalID), WithDelay(30))
+		require.NoError(t, err)
+		second, err := manager.Create(context.Background(), "user-1", WithGoalID(testGoalID+1), WithDelay(60))
+		require.NoError(t, err)
+
+		clock.Advance(29 * time.Minute)
+		conversions, err := manager.Cancel(context.Background(), "user-1", "refund")
+		assert.ErrorIs(t, err, ErrCancelWindowClosed)
+		require.Len(t, conversions, 1)
+		assert.Equal(t, second.Conversion.ID, conversions[0].ID)
+
+		tracked, err := manager.Get("user-1")
+		require.NoError(t, err)
+		require.Len(t, tracked, 1)
+		assert.NotEqual(t, second.Conversion.ID, tracked[0].Conversion.ID)
+	})
+
+	t.Run("track the same conversion again", func(t *testing.T) {
+		manager, _, _ := newTestDelayManager()
+		conversion := &Conversion{ID: 1, PayoutAfter: "2024-01-01 13:00:00", Status: ConversionStatusDelayed}
+		_, err := manager.Track("order-1", conversion)
+		require.NoError(t, err)
+		_, err = manager.Track("order-1", conversion)
+		require.NoError(t, err)
+		assert.Len(t, manager.Conversions(), 1)
+	})
+
+	t.Run("cancellation window closed", func(t *testing.T) {
+		manager, _, clock := newTestDelayManager()
+		_, err := manager.Create(context.Background(), "order-1", WithGoalID(testGoalID), WithDelay(30))
+		require.NoError(t, err)
+
+		clock.Advance(29 * time.Minute)
+		_, err = manager.Cancel(context.Background(), "order-1", "refund")
+		assert.ErrorIs(t, err, ErrCancelWindowClosed)
+	})
+
+	t.Run("api error", func(t *testing.T) {
+		manager, _, _ := newTestDelayManager()
+		_, err := manager.Track("order-1", &Conversion{
+			ID: 999, PayoutAfter: "2024-01-01 13:00:00", Status: ConversionStatusDelayed,
+		})
+		require.NoError(t, err)
+
+		_, err = manager.Cancel(context.Background(), "order-1", "refund")
+		assert.True(t, errors.Is(err, ErrNotFound))
+		_, err = manager.Get("order-1")
+		assert.NoError(t, err)
+	})
+
+	t.Run("unknown reference", func(t *testing.T) {
+		manager, _, _ := newTestDelayManager()
+		_, err := manager.Cancel(context.Background(), "order-1", "refund")
+		assert.ErrorIs(t, err, ErrReferenceNotFound)
+	})
+}
+
+// TestDelayManager_Check will test the cancellation warnings
+func TestDelayManager_Check(t *testing.T) {
+	t.Parallel()
+
+	var warnings []string
+	var remaining []time.Duration
+	manager, _, clock := newTestDelayManager(WithCancelWarning(10*time.Minute,
+		func(conversion *DelayedConversion, left time.Duration) {
+			warnings = append(warnings, conversion.Reference)
+			remaining = append(remaining, left)
+		},
+	))
+	for reference, delay := range map[string]uint64{"order-1": 10, "order-2": 30, "order-3": 60} {
+		_, err := manager.Create(context.Background(), reference, WithGoalID(testGoalID), WithDelay(delay))
+		require.NoError(t, err)
+	}
+
+	manager.Check()
+	assert.Equal(t, []string{"order-1"}, warnings)
+	assert.Equal(t, []time.Duration{9 * time.Minute}, remaining)
+
+	// Warned once per conversion
+	manager.Check()
+	assert.Len(t, warnings, 1)
+
+	clock.Advance(20 * time.Minute)
+	assert.Len(t, manager.Closing(10*time.Minute), 1)
+	manager.Check()
+	assert.Equal(t, []string{"order-1", "order-2"}, warnings)
+
+	// Paid out conversions are forgotten
+	conversions := manager.Conversions()
+	require.Len(t, conversions, 2)
+	assert.Equal(t, "order-2", conversions[0].Reference)
+	assert.Equal(t, "order-3", conversions[1].Reference)
+
+	// Every conversion of a reference is warned about (and forgotten once paid out)
+	_, err := manager.Create(context.Background(), "order-3", WithGoalID(testGoalID+1), WithDelay(5))
+	require.NoError(t, err)
+	manager.Check()
+	assert.Equal(t, []string{"order-1", "order-2", "order-3"}, warnings)
+
+	clock.Advance(20 * time.Minute)
+	manager.Check()
+	conversions = manager.Conversions()
+	require.Len(t, conversions, 1)
+	assert.Equal(t, "order-3", conversions[0].Reference)
+	assert.Equal(t, clock.Now().Add(20*time.Minute), conversions[0].PayoutAfter)
+}
+
+// TestDelayManager_Start will test checking in the background
+func TestDelayManager_Start(t *testing.T) {
+	t.Parallel()
+
+	warned := make(chan string, 1)
+	manager, _, _ := newTestDelayManager(
+		WithDelayCheckInterval(time.Millisecond),
+		WithCancelWarning(10*time.Minute, func(conversion *DelayedConversion, _ time.Duration) {
+			warned <- conversion.Reference
+		}),
+	)
+	manager.Start(context.Background())
+	defer manager.Stop()
+
+	_, err := manager.Create(context.Background(), "order-1", WithGoalID(testGoalID), WithDelay(5))
+	require.NoError(t, err)
+
+	select {
+	case reference := <-warned:
+		assert.Equal(t, "order-1", reference)
+	case <-time.After(time.Second):
+		t.Fatal("no warning")
+	}
+}
@@ -1,6 +1,7 @@
 package tonicpow
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -14,9 +15,11 @@ type ConversionOps func(c *conversionOptions)
 type conversionOptions struct {
 	customDimensions string  // (optional) custom dimensions to add to the conversion
 	delayInMinutes   uint64  // (optional) delay the conversion x minutes (before processing, allowing cancellation)
+	dimensionsErr    error   // Error encoding the structured custom dimensions (see WithDimensions)
 	goalID           uint64  // Goal by ID
 	goalName         string  // Goal by name
-	purchaseAmount   float64 // (optional) purchase amount (total for e-commerce)
+	idempotencyKey   string  // (optional) idempotency key to prevent duplicate conversions (and payouts)
+	purchaseAmount   Decimal // (optional) purchase amount (total for e-commerce)
 	shortCode        string  // (optional) trigger a conversion for a link short_code
 	tncpwSession     string  // tncpw session
 	tonicPowUserID   uint64  // (optional) trigger a conversion for a specific user
@@ -25,7 +28,9 @@ type conversionOptions struct {
 
 // validate will check the options before processing
 func (o *conversionOptions) validate() error {
-	if o.goalID == 0 && len(o.goalName) == 0 {
+	if o.dimensionsErr != nil {
+		return o.dimensionsErr
+	} else if o.goalID == 0 && len(o.goalName) == 0 {
 		return fmt.Errorf("missing required attribute(s): %s or %s", fieldID, fieldName)
 	} else if o.goalID == 0 && o.tonicPowUserID > 0 {
 		return fmt.Errorf("missing required attribute: %s", fieldID)
@@ -69,8 +74,8 @@ func (o *conversionOptions) payload() map[string]string {
 	}
 
 	// Set purchase amount
-	if o.purchaseAmount > 0 {
-		m[fieldAmount] = fmt.Sprintf("%f", o.purchaseAmount)
+	if o.purchaseAmount.Sign() > 0 {
+		m[fieldAmount] = o.purchaseAmount.String()
 	}
 
 	// Set custom dimensions
@@ -88,7 +93,7 @@ func WithGoalID(goalID uint64) ConversionOps {
 	}
 }
 
-// WithGoalName will set a goal name
+// WithGoalName will set a goal name (use FindGoalByName to check the name of the goal first)
 func WithGoalName(name string) ConversionOps {
 	return func(c *conversionOptions) {
 		c.goalName = name
@@ -102,15 +107,20 @@ func WithTncpwSession(session string) ConversionOps {
 	}
 }
 
-// WithCustomDimensions will set custom dimensions (string / json)
+// WithCustomDimensions will set custom dimensions (string / json), see WithDimensions for structured dimensions
 func WithCustomDimensions(dimensions string) ConversionOps {
 	return func(c *conversionOptions) {
-		c.customDimensions = dimensions
+		c.customDimensions, c.dimensionsErr = dimensions, nil
 	}
 }
 
 // WithPurchaseAmount will set purchase amount from e-commerce
 func WithPurchaseAmount(amount float64) ConversionOps {
+	return WithPurchaseAmountDecimal(NewDecimalFromFloat(amount))
+}
+
+// WithPurchaseAmountDecimal will set purchase amount from e-commerce (exact, see NewDecimal and ParseDecimal)
+func WithPurchaseAmountDecimal(amount Decimal) ConversionOps {
 	return func(c *conversionOptions) {
 		c.purchaseAmount = amount
 	}
@@ -137,6 +147,14 @@ func WithShortCode(shortCode string) ConversionOps {
 	}
 }
 
+// WithConversionIdempotencyKey will set an idempotency key for the conversion
+// The conversion will only be created once per key, so the request is safe to retry
+func WithConversionIdempotencyKey(key string) ConversionOps {
+	return func(c *conversionOptions) {
+		c.idempotencyKey = key
+	}
+}
+
 // WithTwitterID will set a Twitter user ID
 func WithTwitterID(twitterID string) ConversionOps {
 	return func(c *conversionOptions) {
@@ -145,10 +163,18 @@ func WithTwitterID(twitterID string) ConversionOps {
 }
 
 // CreateConversion will fire a conversion for a given goal, if successful it will make a new Conversion
+// Use WithConversionIdempotencyKey() to safely retry the request without paying out twice,
+// the key used is returned on the response (StandardResponse.IdempotencyKey)
 //
 // For more information: https://docs.tonicpow.com/#caeffdd5-eaad-4fc8-ac01-8288b50e8e27
 func (c *Client) CreateConversion(opts ...ConversionOps) (conversion *Conversion,
 	response *StandardResponse, err error) {
+	return c.CreateConversionWithContext(context.Background(), opts...)
+}
+
+// CreateConversionWithContext is the same as CreateConversion but uses the given context
+func (c *Client) CreateConversionWithContext(ctx context.Context, opts ...ConversionOps) (conversion *Conversion,
+	response *StandardResponse, err error) {
 
 	// Start the options
 	options := new(conversionOptions)
@@ -164,10 +190,12 @@ func (c *Client) CreateConversion(opts ...ConversionOps) (conversion *Conversion
 	}
 
 	// Fire the Request
-	if response, err = c.Request(
-		http.MethodPost,
+	if response, err = c.RequestWithOptions(
+		ctx, http.MethodPost,
 		"/"+modelConversion,
 		options.payload(), http.StatusCreated,
+		WithIdempotencyKey(options.idempotencyKey),
+		withOperation("CreateConversion"), withCallIDs(CallIDs{GoalID: options.goalID}),
 	); err != nil {
 		return
 	}
@@ -182,6 +210,12 @@ func (c *Client) CreateConversion(opts ...ConversionOps) (conversion *Conversion
 // For more information: https://docs.tonicpow.com/#fce465a1-d8d5-442d-be22-95169170167e
 func (c *Client) GetConversion(conversionID uint64) (conversion *Conversion,
 	response *StandardResponse, err error) {
+	return c.GetConversionWithContext(context.Background(), conversionID)
+}
+
+// GetConversionWithContext is the same as GetConversion but uses the given context
+func (c *Client) GetConversionWithContext(ctx context.Context, conversionID uint64) (conversion *Conversion,
+	response *StandardResponse, err error) {
 
 	// Must have an ID
 	if conversionID == 0 {
@@ -190,10 +224,11 @@ func (c *Client) GetConversion(conversionID uint64) (conversion *Conversion,
 	}
 
 	// Fire the Request
-	if response, err = c.Request(
-		http.MethodGet,
+	if response, err = c.RequestWithOptions(
+		ctx, http.MethodGet,
 		fmt.Sprintf("/%s/details/%d", modelConversion, conversionID),
 		nil, http.StatusOK,
+		withOperation("GetConversion"), withCallIDs(CallIDs{ConversionID: conversionID}),
 	); err != nil {
 		return
 	}
@@ -207,6 +242,12 @@ func (c *Client) GetConversion(conversionID uint64) (conversion *Conversion,
 // For more information: https://docs.tonicpow.com/#e650b083-bbb4-4ff7-9879-c14b1ab3f753
 func (c *Client) CancelConversion(conversionID uint64, cancelReason string) (conversion *Conversion,
 	response *StandardResponse, err error) {
+	return c.CancelConversionWithContext(context.Background(), conversionID, cancelReason)
+}
+
+// CancelConversionWithContext is the same as CancelConversion but uses the given context
+func (c *Client) CancelConversionWithContext(ctx context.Context, conversionID uint64,
+	cancelReason string) (conversion *Conversion, response *StandardResponse, err error) {
 
 	// Must have an ID
 	if conversionID == 0 {
@@ -215,14 +256,15 @@ func (c *Client) CancelConversion(conversionID uint64, cancelReason string) (con
 	}
 
 	// Fire the Request
-	if response, err = c.Request(
-		http.MethodPut,
+	if response, err = c.RequestWithOptions(
+		ctx, http.MethodPut,
 		fmt.Sprintf("/%s/cancel", modelConversion),
 		map[string]string{
 			fieldID:     fmt.Sprintf("%d", conversionID),
 			fieldReason: cancelReason,
 		},
 		http.StatusOK,
+		withOperation("CancelConversion"), withCallIDs(CallIDs{ConversionID: conversionID}),
 	); err != nil {
 		return
 	}
@@ -230,3 +272,83 @@ func (c *Client) CancelConversion(conversionID uint64, cancelReason string) (con
 	err = json.Unmarshal(response.Body, &conversion)
 	return
 }
+
+// ListConversionsByCampaign will return a list of conversions for the campaign
+// This will return an Error if no conversions are found (404)
+func (c *Client) ListConversionsByCampaign(campaignID uint64, options *ConversionListOptions) (
+	results *ConversionResults, response *StandardResponse, err error) {
+	return c.ListConversionsByCampaignWithContext(context.Background(), campaignID, options)
+}
+
+// ListConversionsByCampaignWithContext is the same as ListConversionsByCampaign but uses the given context
+func (c *Client) ListConversionsByCampaignWithContext(ctx context.Context, campaignID uint64,
+	options *ConversionListOptions) (results *ConversionResults, response *StandardResponse, err error) {
+	return c.listConversions(
+		ctx, modelCampaign, fieldCampaignID, campaignID, options,
+		withOperation("ListConversionsByCampaign"), withCallIDs(CallIDs{CampaignID: campaignID}),
+	)
+}
+
+// ListConversionsByGoal will return a list of conversions for the goal
+// This will return an Error if no conversions are found (404)
+func (c *Client) ListConversionsByGoal(goalID uint64, options *ConversionListOptions) (
+	results *ConversionResults, response *StandardResponse, err error) {
+	return c.ListConversionsByGoalWithContext(context.Background(), goalID, options)
+}
+
+// ListConversionsByGoalWithContext is the same as ListConversionsByGoal but uses the given context
+func (c *Client) ListConversionsByGoalWithContext(ctx context.Context, goalID uint64,
+	options *ConversionListOptions) (results *ConversionResults, response *StandardResponse, err error) {
+	return c.listConversions(
+		ctx, modelGoal, fieldGoalID, goalID, options,
+		withOperation("ListConversionsByGoal"), withCallIDs(CallIDs{GoalID: goalID}),
+	)
+}
+
+// ListConversionsByUser will return a list of conversions for the tonicpow user
+// This will return an Error if no conversions are found (404)
+func (c *Client) ListConversionsByUser(userID uint64, options *ConversionListOptions) (
+	results *ConversionResults, response *StandardResponse, err error) {
+	return c.ListConversionsByUserWithContext(context.Background(), userID, options)
+}
+
+// ListConversionsByUserWithContext is the same as ListConversionsByUser but uses the given context
+func (c *Client) ListConversionsByUserWithContext(ctx context.Context, userID uint64,
+	options *ConversionListOptions) (results *ConversionResults, response *StandardResponse, err error) {
+	return c.listConversions(
+		ctx, modelUser, fieldUserID, userID, options, withOperation("ListConversionsByUser"),
+	)
+}
+
+// listConversions will return a page of conversions for the parent model (campaigns, goals or users)
+func (c *Client) listConversions(ctx context.Context, parent, field string, parentID uint64,
+	options *ConversionListOptions, opts ...RequestOps) (results *ConversionResults,
+	response *StandardResponse, err error) {
+
+	// Must have an ID
+	if parentID == 0 {
+		err = fmt.Errorf("missing required attribute: %s", field)
+		return
+	}
+
+	// Validate the options
+	if options == nil {
+		options = new(ConversionListOptions)
+	}
+	var query queryValues
+	if err = options.encode(&query); err != nil {
+		return
+	}
+
+	// Fire the Request
+	if response, err = c.RequestWithOptions(
+		ctx, http.MethodGet,
+		fmt.Sprintf("/%s/%s/%d?%s", modelConversion, parent, parentID, query),
+		nil, http.StatusOK, opts...,
+	); err != nil {
+		return
+	}
+
+	err = json.Unmarshal(response.Body, &results)
+	return
+}
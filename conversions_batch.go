@@ -0,0 +1,130 @@
+package tonicpow
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// defaultBatchConcurrency is the default number of conversions submitted at the same time
+const defaultBatchConcurrency = 4
+
+// ErrBatchStopped is returned for the conversions that were not submitted
+// because an earlier conversion failed (see WithStopOnError)
+var ErrBatchStopped = errors.New("batch stopped after an error")
+
+// BatchOps allow functional options to be supplied to CreateConversions
+type BatchOps func(o *batchOptions)
+
+// batchOptions holds all the configuration for submitting a batch of conversions
+type batchOptions struct {
+	concurrency int  // Number of conversions submitted at the same time
+	stopOnError bool // Stop submitting conversions after the first error
+}
+
+// WithBatchConcurrency will set the number of conversions submitted at the same time.
+// Default is 4.
+func WithBatchConcurrency(workers int) BatchOps {
+	return func(o *batchOptions) {
+		if workers > 0 {
+			o.concurrency = workers
+		}
+	}
+}
+
+// WithStopOnError will stop submitting conversions after the first error,
+// conversions already in flight will complete and the rest will fail with ErrBatchStopped
+func WithStopOnError() BatchOps {
+	return func(o *batchOptions) {
+		o.stopOnError = true
+	}
+}
+
+// ConversionResult is the result of a single conversion in a batch (see CreateConversions)
+type ConversionResult struct {
+	Conversion *Conversion       // The new conversion (nil if failed)
+	Err        error             // Error creating the conversion (nil if successful)
+	Index      int               // Position of the conversion in the batch
+	Options    []ConversionOps   // The conversion options (from the batch)
+	Response   *StandardResponse // Response from the API (nil if the request was not sent)
+}
+
+// CreateConversions will fire a batch of conversions (one set of ConversionOps per conversion)
+// using a bounded number of concurrent requests. The client rate limit (WithRateLimit)
+// and retry policy apply to every conversion.
+//
+// The results are returned in the same order as the batch, every result has its own error.
+// The error returned is the first error (in batch order) if any conversion failed.
+//
+// Use WithConversionIdempotencyKey() on every conversion to safely retry a batch
+func (c *Client) CreateConversions(batch [][]ConversionOps, opts ...BatchOps) ([]*ConversionResult, error) {
+	return c.CreateConversionsWithContext(context.Background(), batch, opts...)
+}
+
+// CreateConversionsWithContext is the same as CreateConversions but uses the given context
+func (c *Client) CreateConversionsWithContext(ctx context.Context, batch [][]ConversionOps,
+	opts ...BatchOps) ([]*ConversionResult, error) {
+
+	// Set the batch options
+	options := &batchOptions{concurrency: defaultBatchConcurrency}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	// Start the workers
+	results := make([]*ConversionResult, len(batch))
+	jobs := make(chan *ConversionResult)
+	var stopped sync.Once
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < options.concurrency && i < len(batch); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for result := range jobs {
+				result.Conversion, result.Response, result.Err = c.CreateConversionWithContext(
+					ctx, result.Options...,
+				)
+				if result.Err != nil && options.stopOnError {
+					stopped.Do(func() { close(stop) })
+				}
+			}
+		}()
+	}
+
+	// Submit the conversions (in order) until done, stopped or canceled
+	var skipErr error
+	for index, conversionOpts := range batch {
+		results[index] = &ConversionResult{Index: index, Options: conversionOpts}
+		if skipErr != nil {
+			results[index].Err = skipErr
+			continue
+		}
+		select {
+		case <-stop:
+			skipErr = ErrBatchStopped
+			results[index].Err = skipErr
+			continue
+		default:
+		}
+		select {
+		case jobs <- results[index]:
+		case <-stop:
+			skipErr = ErrBatchStopped
+			results[index].Err = skipErr
+		case <-ctx.Done():
+			skipErr = ctx.Err()
+			results[index].Err = skipErr
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	// Return the first error (in batch order)
+	for _, result := range results {
+		if result.Err != nil && !errors.Is(result.Err, ErrBatchStopped) {
+			return results, result.Err
+		}
+	}
+	return results, nil
+}
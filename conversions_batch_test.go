@@ -0,0 +1,193 @@
+package tonicpow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testFailingGoalID is the goal ID rejected by mockConversionBatch
+const testFailingGoalID uint64 = 500
+
+// mockConversionBatch is used for mocking conversions (the conversion ID is the goal ID),
+// the returned func will return the maximum number of concurrent requests
+func mockConversionBatch(delay time.Duration) func() int32 {
+	httpmock.Reset()
+	var inFlight, maxInFlight int32
+	httpmock.RegisterResponder(http.MethodPost, EnvironmentDevelopment.apiURL+"/"+modelConversion,
+		func(req *http.Request) (*http.Response, error) {
+			current := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+			for {
+				if previous := atomic.LoadInt32(&maxInFlight); current <= previous ||
+					atomic.CompareAndSwapInt32(&maxInFlight, previous, current) {
+					break
+				}
+			}
+			time.Sleep(delay)
+
+			payload := make(map[string]string)
+			if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+				return nil, err
+			}
+			goalID, _ := strconv.ParseUint(payload[fieldGoalID], 10, 64)
+			if goalID == testFailingGoalID {
+				return httpmock.NewStringResponse(
+					http.StatusUnprocessableEntity, `{"code":422,"message":"goal is not active"}`,
+				), nil
+			}
+			return httpmock.NewJsonResponse(http.StatusCreated, &Conversion{GoalID: goalID, ID: goalID})
+		},
+	)
+	return func() int32 { return atomic.LoadInt32(&maxInFlight) }
+}
+
+// newTestConversionBatch will return a batch of conversions for the goal IDs
+func newTestConversionBatch(goalIDs ...uint64) [][]ConversionOps {
+	batch := make([][]ConversionOps, 0, len(goalIDs))
+	for _, goalID := range goalIDs {
+		batch = append(batch, []ConversionOps{WithGoalID(goalID), WithTncpwSession(testTncpwSession)})
+	}
+	return batch
+}
+
+// TestClient_CreateConversions will test the method CreateConversions()
+func TestClient_CreateConversions(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	t.Run("results in batch order", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		maxInFlight := mockConversionBatch(5 * time.Millisecond)
+		goalIDs := []uint64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+		var results []*ConversionResult
+		results, err = client.CreateConversions(newTestConversionBatch(goalIDs...), WithBatchConcurrency(3))
+		require.NoError(t, err)
+
+		require.Len(t, results, len(goalIDs))
+		for index, result := range results {
+			assert.NoError(t, result.Err)
+			assert.Equal(t, index, result.Index)
+			assert.Equal(t, goalIDs[index], result.Conversion.ID)
+			assert.Equal(t, http.StatusCreated, result.Response.StatusCode)
+		}
+		assert.LessOrEqual(t, maxInFlight(), int32(3))
+		assert.Greater(t, maxInFlight(), int32(1))
+	})
+
+	t.Run("errors per conversion", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		mockConversionBatch(0)
+		batch := newTestConversionBatch(1, testFailingGoalID, 3)
+		batch = append(batch, []ConversionOps{WithTncpwSession(testTncpwSession)})
+		var results []*ConversionResult
+		results, err = client.CreateConversions(batch)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrValidation))
+
+		require.Len(t, results, 4)
+		assert.NoError(t, results[0].Err)
+		assert.True(t, errors.Is(results[1].Err, ErrValidation))
+		assert.Nil(t, results[1].Conversion)
+		assert.NoError(t, results[2].Err)
+		assert.Equal(t, uint64(3), results[2].Conversion.ID)
+		assert.Error(t, results[3].Err)
+		assert.Nil(t, results[3].Response)
+	})
+
+	t.Run("stop on error", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		mockConversionBatch(0)
+		var results []*ConversionResult
+		results, err = client.CreateConversions(
+			newTestConversionBatch(1, testFailingGoalID, 3, 4),
+			WithBatchConcurrency(1), WithStopOnError(),
+		)
+		require.True(t, errors.Is(err, ErrValidation))
+
+		require.Len(t, results, 4)
+		assert.NoError(t, results[0].Err)
+		assert.True(t, errors.Is(results[1].Err, ErrValidation))
+		assert.True(t, errors.Is(results[2].Err, ErrBatchStopped))
+		assert.Nil(t, results[2].Response)
+		assert.True(t, errors.Is(results[3].Err, ErrBatchStopped))
+		assert.Equal(t, 2, httpmock.GetTotalCallCount())
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		mockConversionBatch(0)
+		var results []*ConversionResult
+		results, err = client.CreateConversionsWithContext(newCanceledContext(), newTestConversionBatch(1, 2))
+		require.Error(t, err)
+		require.Len(t, results, 2)
+		for _, result := range results {
+			assert.Error(t, result.Err)
+			assert.Nil(t, result.Conversion)
+		}
+	})
+
+	t.Run("empty batch", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		results, err := client.CreateConversions(nil)
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+}
+
+// ExampleClient_CreateConversions example using CreateConversions()
+//
+// See more examples in /examples/
+func ExampleClient_CreateConversions() {
+	// Load the client (using test client for example only)
+	client, err := newTestClient()
+	if err != nil {
+		fmt.Printf("error loading client: %s", err.Error())
+		return
+	}
+
+	// Mock response (for example only)
+	mockConversionBatch(0)
+
+	// Create conversions (using mocking response)
+	var results []*ConversionResult
+	if results, err = client.CreateConversions(
+		newTestConversionBatch(testGoalID, testGoalID+1), WithBatchConcurrency(4),
+	); err != nil {
+		fmt.Printf("error creating conversions: %s", err.Error())
+		return
+	}
+	for _, result := range results {
+		fmt.Printf("created conversion: %d\n", result.Conversion.ID)
+	}
+	// Output:created conversion: 13
+	// created conversion: 14
+}
+
+// BenchmarkClient_CreateConversions benchmarks the method CreateConversions()
+func BenchmarkClient_CreateConversions(b *testing.B) {
+	client, _ := newTestClient()
+	mockConversionBatch(0)
+	batch := newTestConversionBatch(1, 2, 3, 4, 5, 6, 7, 8)
+	for i := 0; i < b.N; i++ {
+		_, _ = client.CreateConversions(batch)
+	}
+}
@@ -1,11 +1,15 @@
 package tonicpow
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
+	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func newTestConversion() *Conversion {
@@ -20,6 +24,16 @@ func newTestConversion() *Conversion {
 	}
 }
 
+// newTestConversionResults will return a page with a single conversion
+func newTestConversionResults(currentPage, resultsPerPage int) *ConversionResults {
+	return &ConversionResults{
+		Conversions:    []*Conversion{newTestConversion()},
+		CurrentPage:    currentPage,
+		Results:        1,
+		ResultsPerPage: resultsPerPage,
+	}
+}
+
 // TestClient_CreateConversion will test the method CreateConversion()
 func TestClient_CreateConversion(t *testing.T) {
 	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
@@ -50,6 +64,28 @@ func TestClient_CreateConversion(t *testing.T) {
 		assert.Equal(t, testGoalID, newConversion.GoalID)
 	})
 
+	t.Run("create a conversion with an idempotency key (success)", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		endpoint := fmt.Sprintf("%s/%s", EnvironmentDevelopment.apiURL, modelConversion)
+
+		headers := mockResponseHeaders(http.MethodPost, endpoint, http.StatusCreated)
+
+		var response *StandardResponse
+		_, response, err = client.CreateConversion(
+			WithGoalID(testGoalID),
+			WithTncpwSession(testTncpwSession),
+			WithConversionIdempotencyKey("order-12345"),
+		)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, response)
+		assert.Equal(t, "order-12345", response.IdempotencyKey)
+		assert.Equal(t, "order-12345", (*headers)[0].Get(IdempotencyKeyHeader))
+	})
+
 	t.Run("create a conversion by goal name (success)", func(t *testing.T) {
 		client, err := newTestClient()
 		assert.NoError(t, err)
@@ -654,3 +690,262 @@ func BenchmarkClient_CancelConversion(b *testing.B) {
 		_, _, _ = client.CancelConversion(conversion.ID, "my reason")
 	}
 }
+
+// TestClient_CreateConversionWithContext will test the method CreateConversionWithContext()
+func TestClient_CreateConversionWithContext(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	endpoint := fmt.Sprintf("%s/%s", EnvironmentDevelopment.apiURL, modelConversion)
+
+	t.Run("valid context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		err = mockResponseData(http.MethodPost, endpoint, http.StatusCreated, newTestConversion())
+		assert.NoError(t, err)
+
+		var conversion *Conversion
+		conversion, _, err = client.CreateConversionWithContext(
+			context.Background(), WithGoalID(testGoalID), WithTncpwSession(testTncpwSession),
+		)
+		assert.NoError(t, err)
+		assert.NotNil(t, conversion)
+		assert.Equal(t, testConversionID, conversion.ID)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		mockResponseBlocking(http.MethodPost, endpoint)
+
+		var conversion *Conversion
+		conversion, _, err = client.CreateConversionWithContext(
+			newCanceledContext(), WithGoalID(testGoalID), WithTncpwSession(testTncpwSession),
+		)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, conversion)
+	})
+}
+
+// TestClient_GetConversionWithContext will test the method GetConversionWithContext()
+func TestClient_GetConversionWithContext(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	endpoint := fmt.Sprintf("%s/%s/details/%d", EnvironmentDevelopment.apiURL, modelConversion, testConversionID)
+
+	t.Run("valid context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestConversion())
+		assert.NoError(t, err)
+
+		var conversion *Conversion
+		conversion, _, err = client.GetConversionWithContext(context.Background(), testConversionID)
+		assert.NoError(t, err)
+		assert.NotNil(t, conversion)
+		assert.Equal(t, testConversionID, conversion.ID)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		mockResponseBlocking(http.MethodGet, endpoint)
+
+		var conversion *Conversion
+		conversion, _, err = client.GetConversionWithContext(newCanceledContext(), testConversionID)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, conversion)
+	})
+}
+
+// TestClient_CancelConversionWithContext will test the method CancelConversionWithContext()
+func TestClient_CancelConversionWithContext(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	endpoint := fmt.Sprintf("%s/%s/cancel", EnvironmentDevelopment.apiURL, modelConversion)
+
+	t.Run("valid context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		err = mockResponseData(http.MethodPut, endpoint, http.StatusOK, newTestConversion())
+		assert.NoError(t, err)
+
+		var conversion *Conversion
+		conversion, _, err = client.CancelConversionWithContext(context.Background(), testConversionID, "my reason")
+		assert.NoError(t, err)
+		assert.NotNil(t, conversion)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		mockResponseBlocking(http.MethodPut, endpoint)
+
+		var conversion *Conversion
+		conversion, _, err = client.CancelConversionWithContext(newCanceledContext(), testConversionID, "my reason")
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, conversion)
+	})
+}
+
+// TestClient_ListConversionsByCampaign will test the method ListConversionsByCampaign()
+func TestClient_ListConversionsByCampaign(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	t.Run("list with filters (success)", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		endpoint := fmt.Sprintf(
+			"%s/%s/%s/%d?current_page=1&results_per_page=50&sort_by=amount&sort_order=asc"+
+				"&status=paid&payout_state=paid&from=2021-01-01+00%%3A00%%3A00&to=2021-02-01+00%%3A00%%3A00",
+			EnvironmentDevelopment.apiURL, modelConversion, modelCampaign, testCampaignID,
+		)
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestConversionResults(1, 50))
+		require.NoError(t, err)
+
+		var results *ConversionResults
+		results, _, err = client.ListConversionsByCampaign(testCampaignID, &ConversionListOptions{
+			ListOptions: ListOptions{Page: 1, ResultsPerPage: 50, SortBy: SortByFieldAmount, SortOrder: SortOrderAsc},
+			From:        time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			PayoutState: PayoutStatePaid,
+			Status:      ConversionStatusPaid,
+			To:          time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+		})
+		require.NoError(t, err)
+		require.NotNil(t, results)
+		require.Len(t, results.Conversions, 1)
+		assert.Equal(t, uint64(testConversionID), results.Conversions[0].ID)
+	})
+
+	t.Run("nil options (first page & default page size)", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		endpoint := fmt.Sprintf(
+			"%s/%s/%s/%d?current_page=1&results_per_page=25&sort_by=created_at&sort_order=desc",
+			EnvironmentDevelopment.apiURL, modelConversion, modelCampaign, testCampaignID,
+		)
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestConversionResults(1, 25))
+		require.NoError(t, err)
+
+		var results *ConversionResults
+		results, _, err = client.ListConversionsByCampaign(testCampaignID, nil)
+		require.NoError(t, err)
+		require.NotNil(t, results)
+		assert.Equal(t, 1, httpmock.GetTotalCallCount())
+	})
+
+	t.Run("missing campaign id", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		var results *ConversionResults
+		var response *StandardResponse
+		results, response, err = client.ListConversionsByCampaign(0, nil)
+		assert.EqualError(t, err, "missing required attribute: campaign_id")
+		assert.Nil(t, results)
+		assert.Nil(t, response)
+	})
+
+	t.Run("invalid options are not sent", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+		httpmock.Reset()
+
+		_, _, err = client.ListConversionsByCampaign(testCampaignID, &ConversionListOptions{Status: "refunded"})
+		assert.EqualError(t, err, "status refunded is not valid")
+		assert.Equal(t, 0, httpmock.GetTotalCallCount())
+	})
+
+	t.Run("error from api (status code)", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		endpoint := fmt.Sprintf(
+			"%s/%s/%s/%d?current_page=1&results_per_page=25&sort_by=created_at&sort_order=desc",
+			EnvironmentDevelopment.apiURL, modelConversion, modelCampaign, testCampaignID,
+		)
+		apiError := &Error{Code: 404, Message: "conversions not found", RequestGUID: "7f3d97a8fd67ff57861904df6118dcc8"}
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusNotFound, apiError)
+		require.NoError(t, err)
+
+		_, _, err = client.ListConversionsByCampaignWithContext(context.Background(), testCampaignID, nil)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+// TestClient_ListConversionsByGoal will test the methods ListConversionsByGoal() and ListConversionsByUser()
+func TestClient_ListConversionsByGoal(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	tests := []struct {
+		name  string
+		model string
+		id    uint64
+		list  func(client ClientInterface, id uint64) (*ConversionResults, *StandardResponse, error)
+	}{
+		{"by goal", modelGoal, testGoalID, func(client ClientInterface, id uint64) (*ConversionResults, *StandardResponse, error) {
+			return client.ListConversionsByGoal(id, &ConversionListOptions{PayoutState: "UNPAID"})
+		}},
+		{"by user", modelUser, testUserID, func(client ClientInterface, id uint64) (*ConversionResults, *StandardResponse, error) {
+			return client.ListConversionsByUser(id, &ConversionListOptions{PayoutState: PayoutStateUnpaid})
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, err := newTestClient()
+			require.NoError(t, err)
+
+			endpoint := fmt.Sprintf(
+				"%s/%s/%s/%d?current_page=1&results_per_page=25&sort_by=created_at&sort_order=desc&payout_state=unpaid",
+				EnvironmentDevelopment.apiURL, modelConversion, test.model, test.id,
+			)
+			err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestConversionResults(1, 25))
+			require.NoError(t, err)
+
+			var results *ConversionResults
+			results, _, err = test.list(client, test.id)
+			require.NoError(t, err)
+			require.NotNil(t, results)
+			assert.Equal(t, 1, results.Results)
+
+			_, _, err = test.list(client, 0)
+			assert.Error(t, err)
+		})
+	}
+}
+
+// TestClient_IterateConversionsByCampaign will test the method IterateConversionsByCampaign()
+func TestClient_IterateConversionsByCampaign(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	client, err := newTestClient()
+	require.NoError(t, err)
+
+	err = mockResponseData(http.MethodGet, fmt.Sprintf(
+		"%s/%s/%s/%d?current_page=1&results_per_page=25&sort_by=created_at&sort_order=desc&status=delayed",
+		EnvironmentDevelopment.apiURL, modelConversion, modelCampaign, testCampaignID,
+	), http.StatusOK, newTestConversionResults(1, 25))
+	require.NoError(t, err)
+
+	iter := client.IterateConversionsByCampaign(context.Background(), testCampaignID, &ConversionListOptions{
+		ListOptions: ListOptions{Page: 5, ResultsPerPage: 1},
+		Status:      ConversionStatusDelayed,
+	})
+	assert.True(t, iter.Next())
+	assert.Equal(t, uint64(testConversionID), iter.Conversion().ID)
+	assert.False(t, iter.Next())
+	assert.NoError(t, iter.Err())
+}
@@ -5,29 +5,48 @@ import (
 )
 
 const (
+	// IdempotencyKeyHeader is the header used to send the idempotency key of a request
+	IdempotencyKeyHeader = "Idempotency-Key"
+
 	// Package configuration defaults
 	apiVersion         string = "v1"
 	defaultHTTPTimeout        = 10 * time.Second          // Default timeout for all GET requests in seconds
 	defaultRetryCount  int    = 2                         // Default retry count for HTTP requests
 	defaultUserAgent          = "go-tonicpow: " + version // Default user agent
+	timestampLayout           = "2006-01-02 15:04:05"     // Layout of the API timestamps (UTC)
 	version            string = "v0.8.0"                  // go-tonicpow version
 
 	// Field key names for various model requests
 	fieldAdvertiserProfileID = "advertiser_profile_id"
 	fieldAmount              = "amount"
 	fieldAPIKey              = "api_key"
+	fieldBalanceAlert        = "balance_alert_threshold"
 	fieldCampaignID          = "campaign_id"
 	fieldCurrency            = "currency"
 	fieldCurrentPage         = "current_page"
 	fieldCustomDimensions    = "custom_dimensions"
 	fieldDelayInMinutes      = "delay_in_minutes"
 	fieldDescription         = "description"
+	fieldEventType           = "event_type"
 	fieldExpired             = "expired"
+	fieldExpiresAt           = "expires_at"
 	fieldFeedType            = "feed_type"
+	fieldFrom                = "from"
 	fieldGoalID              = "goal_id"
+	fieldGoals               = "goals"
+	fieldHomepageURL         = "homepage_url"
+	fieldIconURL             = "icon_url"
 	fieldID                  = "id"
+	fieldImageURL            = "image_url"
+	fieldMaxPerPromoter      = "max_per_promoter"
+	fieldMaxPerVisitor       = "max_per_visitor"
 	fieldMinimumBalance      = "minimum_balance"
 	fieldName                = "name"
+	fieldPayoutMode          = "payout_mode"
+	fieldPayoutState         = "payout_state"
+	fieldPayoutRate          = "payout_rate"
+	fieldPayoutType          = "payout_type"
+	fieldPayPerClickRate     = "pay_per_click_rate"
 	fieldReason              = "reason"
 	fieldResultsPerPage      = "results_per_page"
 	fieldSearchQuery         = "query"
@@ -35,14 +54,18 @@ const (
 	fieldSlug                = "slug"
 	fieldSortBy              = "sort_by"
 	fieldSortOrder           = "sort_order"
+	fieldStatus              = "status"
 	fieldTargetURL           = "target_url"
 	fieldTargetData          = "target_data"
 	fieldTargetType          = "target_type"
 
 	fieldTitle              = "title"
+	fieldTo                 = "to"
 	fieldTwitterID          = "twitter_id"
 	fieldUserID             = "user_id"
+	fieldVisitorCountries   = "visitor_countries"
 	fieldVisitorSessionGUID = "tncpw_session"
+	fieldWebhookURL         = "webhook_url"
 
 	// Model names (used for Request endpoints)
 	modelAdvertiser string = "advertisers"
@@ -51,6 +74,7 @@ const (
 	modelConversion string = "conversions"
 	modelGoal       string = "goals"
 	modelRates      string = "rates"
+	modelUser       string = "users"
 
 	// Environment names
 	environmentDevelopmentAlias string = "local"
@@ -65,29 +89,53 @@ const (
 	liveAPIURL     = "https://api.tonicpow.com/" + apiVersion
 	stagingAPIURL  = "https://api.staging.tonicpow.com/" + apiVersion
 
+	// SortByFieldAmount is for sorting results by field: amount
+	SortByFieldAmount = "amount"
+
 	// SortByFieldBalance is for sorting results by field: balance
-	SortByFieldBalance string = "balance"
+	SortByFieldBalance = "balance"
 
 	// SortByFieldCreatedAt is for sorting results by field: created_at
-	SortByFieldCreatedAt string = "created_at"
+	SortByFieldCreatedAt = "created_at"
 
 	// SortByFieldName is for sorting results by field: name
-	SortByFieldName string = "name"
+	SortByFieldName = "name"
 
 	// SortByFieldLinksCreated is for sorting results by field: links_created
-	SortByFieldLinksCreated string = "links_created"
+	SortByFieldLinksCreated = "links_created"
 
 	// SortByFieldPaidClicks is for sorting results by field: paid_clicks
-	SortByFieldPaidClicks string = "paid_clicks"
+	SortByFieldPaidClicks = "paid_clicks"
 
 	// SortByFieldPayPerClick is for sorting results by field: pay_per_click_rate
-	SortByFieldPayPerClick string = "pay_per_click_rate"
+	SortByFieldPayPerClick = "pay_per_click_rate"
+
+	// SortByFieldPayouts is for sorting results by field: payouts
+	SortByFieldPayouts = "payouts"
 
 	// SortOrderAsc is for returning the results in ascending order
-	SortOrderAsc string = "asc"
+	SortOrderAsc = "asc"
 
 	// SortOrderDesc is for returning the results in descending order
-	SortOrderDesc string = "desc"
+	SortOrderDesc = "desc"
+
+	// ConversionStatusCanceled is the status of a conversion that was canceled before payout
+	ConversionStatusCanceled string = "canceled"
+
+	// ConversionStatusDelayed is the status of a conversion waiting for the delay to pass
+	ConversionStatusDelayed string = "delayed"
+
+	// ConversionStatusFailed is the status of a conversion that failed to process
+	ConversionStatusFailed string = "failed"
+
+	// ConversionStatusPaid is the status of a conversion that has been paid out
+	ConversionStatusPaid string = "paid"
+
+	// ConversionStatusPending is the status of a conversion waiting to be processed
+	ConversionStatusPending string = "pending"
+
+	// ConversionStatusProcessing is the status of a conversion that is being processed
+	ConversionStatusProcessing string = "processing"
 
 	// FeedTypeAtom is for using the feed type: Atom
 	FeedTypeAtom FeedType = "atom"
@@ -97,18 +145,71 @@ const (
 
 	// FeedTypeRSS is for using the feed type: RSS
 	FeedTypeRSS FeedType = "rss"
+
+	// PayoutModeClicksAndGoals is for campaigns paying for clicks (PayPerClickRate) and goals (default)
+	PayoutModeClicksAndGoals PayoutMode = 0
+
+	// PayoutModeGoalsOnly is for campaigns only paying for goals (conversions)
+	PayoutModeGoalsOnly PayoutMode = 1
+
+	// PayoutStatePaid is for conversions that have been paid out (Conversion.TxID is set)
+	PayoutStatePaid PayoutState = "paid"
+
+	// PayoutStateUnpaid is for conversions that have not been paid out (pending, delayed, failed, etc.)
+	PayoutStateUnpaid PayoutState = "unpaid"
+
+	// PayoutTypeFlat is for goals paying a flat rate (Goal.PayoutRate)
+	PayoutTypeFlat PayoutType = "flat"
+
+	// PayoutTypePercent is for goals paying a percentage (Goal.PayoutRate) of the purchase amount
+	PayoutTypePercent PayoutType = "percent"
+
+	// TargetTypeHosted is for campaigns targeting content hosted by TonicPow (Campaign.TargetData)
+	TargetTypeHosted TargetType = "hosted"
+
+	// TargetTypeURL is for campaigns targeting a URL (Campaign.TargetURL)
+	TargetTypeURL TargetType = "url"
 )
 
 var (
 
+	// advertiserSortFields is used for allowing specific fields for sorting
+	advertiserSortFields = []SortField{
+		SortByFieldCreatedAt,
+		SortByFieldName,
+	}
+
 	// appSortFields is used for allowing specific fields for sorting
-	appSortFields = []string{
+	appSortFields = []SortField{
+		SortByFieldCreatedAt,
+		SortByFieldName,
+	}
+
+	// goalSortFields is used for allowing specific fields for sorting
+	goalSortFields = []SortField{
 		SortByFieldCreatedAt,
 		SortByFieldName,
+		SortByFieldPayouts,
+	}
+
+	// conversionSortFields is used for allowing specific fields for sorting
+	conversionSortFields = []SortField{
+		SortByFieldAmount,
+		SortByFieldCreatedAt,
+	}
+
+	// conversionStatuses are the known statuses of a conversion
+	conversionStatuses = []string{
+		ConversionStatusCanceled,
+		ConversionStatusDelayed,
+		ConversionStatusFailed,
+		ConversionStatusPaid,
+		ConversionStatusPending,
+		ConversionStatusProcessing,
 	}
 
 	// campaignSortFields is used for allowing specific fields for sorting
-	campaignSortFields = []string{
+	campaignSortFields = []SortField{
 		SortByFieldBalance,
 		SortByFieldCreatedAt,
 		SortByFieldLinksCreated,
@@ -120,6 +221,29 @@ var (
 // FeedType is used for the campaign feeds (rss, atom, json)
 type FeedType string
 
+// SortField is used for sorting the list results (balance, created_at, etc.)
+//
+// The SortByField* constants are untyped, so they can be used as a SortField (ListOptions)
+// or as a string (the positional List*() methods)
+type SortField string
+
+// SortOrder is used for the order of the list results (asc, desc)
+//
+// The SortOrder* constants are untyped, so they can be used as a SortOrder or as a string
+type SortOrder string
+
+// PayoutMode is used for the campaign payout mode (clicks and goals, goals only)
+type PayoutMode int
+
+// PayoutType is used for the goal payout (flat, percent)
+type PayoutType string
+
+// PayoutState is used for filtering conversions by payout (paid, unpaid)
+type PayoutState string
+
+// TargetType is used for the campaign target (url, hosted)
+type TargetType string
+
 // Environment is used for changing the Environment for running client requests
 type Environment struct {
 	alias  string
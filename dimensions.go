@@ -0,0 +1,87 @@
+package tonicpow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+const (
+	// MaxDimensionsSize is the maximum size (bytes) of the JSON encoded custom dimensions
+	MaxDimensionsSize = 2048
+
+	// MaxDimensionKeyLength is the maximum length of a custom dimension key
+	MaxDimensionKeyLength = 64
+)
+
+// ErrInvalidDimensions is returned when the custom dimensions are not a valid JSON object,
+// have invalid key names or are too large
+var ErrInvalidDimensions = errors.New("invalid custom dimensions")
+
+// dimensionKeyPattern is the pattern of valid dimension keys (order_id, utm.source, etc.)
+var dimensionKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// MarshalDimensions will encode the dimensions (a map or struct) as the custom dimensions of a conversion
+//
+// The dimensions must encode to a JSON object of at most MaxDimensionsSize bytes, the keys
+// (of the object, not nested objects) must start with a letter or underscore and contain only
+// letters, digits, underscores, dots and dashes (at most MaxDimensionKeyLength characters)
+func MarshalDimensions(dimensions interface{}) (string, error) {
+	data, err := json.Marshal(dimensions)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidDimensions, err.Error())
+	}
+	if err = validateDimensions(data); err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// validateDimensions will check the size and the keys of the JSON encoded dimensions
+func validateDimensions(data []byte) error {
+	if len(data) > MaxDimensionsSize {
+		return fmt.Errorf("%w: %d bytes exceeds the maximum of %d bytes", ErrInvalidDimensions,
+			len(data), MaxDimensionsSize)
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil || object == nil {
+		return fmt.Errorf("%w: must be a JSON object", ErrInvalidDimensions)
+	}
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if len(key) > MaxDimensionKeyLength || !dimensionKeyPattern.MatchString(key) {
+			return fmt.Errorf("%w: invalid key %q", ErrInvalidDimensions, key)
+		}
+	}
+	return nil
+}
+
+// WithDimensions will set structured custom dimensions (a map or struct, encoded as JSON)
+//
+// Invalid dimensions (see MarshalDimensions) are returned as an error when creating the conversion
+func WithDimensions(dimensions interface{}) ConversionOps {
+	return func(c *conversionOptions) {
+		c.customDimensions, c.dimensionsErr = MarshalDimensions(dimensions)
+	}
+}
+
+// DecodeDimensions will decode the custom dimensions of the conversion into v (a pointer to a map or struct)
+//
+// Nothing is decoded if the conversion has no custom dimensions
+func (c *Conversion) DecodeDimensions(v interface{}) error {
+	if len(c.CustomDimensions) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(c.CustomDimensions), v); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidDimensions, err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package tonicpow
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testDimensions are structured custom dimensions
+type testDimensions struct {
+	Coupon  string  `json:"coupon,omitempty"`
+	OrderID string  `json:"order_id"`
+	Total   Decimal `json:"total"`
+}
+
+// TestMarshalDimensions will test the method MarshalDimensions()
+func TestMarshalDimensions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		dimensions interface{}
+		expected   string
+		valid      bool
+	}{
+		{"struct", &testDimensions{OrderID: "order-1", Total: NewDecimal(1999, 2)},
+			`{"order_id":"order-1","total":19.99}`, true},
+		{"map", map[string]interface{}{"utm.source": "twitter", "_ref": 1, "page-id": true},
+			`{"_ref":1,"page-id":true,"utm.source":"twitter"}`, true},
+		{"nested keys are not validated", map[string]interface{}{"cart": map[string]int{"sku 1": 2}},
+			`{"cart":{"sku 1":2}}`, true},
+		{"empty map", map[string]string{}, `{}`, true},
+		{"invalid key", map[string]string{"order id": "1"}, "", false},
+		{"key starts with a digit", map[string]string{"1st": "1"}, "", false},
+		{"empty key", map[string]string{"": "1"}, "", false},
+		{"key too long", map[string]string{strings.Repeat("a", MaxDimensionKeyLength+1): "1"}, "", false},
+		{"too large", map[string]string{"data": strings.Repeat("a", MaxDimensionsSize)}, "", false},
+		{"not an object", []string{"order-1"}, "", false},
+		{"string", "order-1", "", false},
+		{"nil", nil, "", false},
+		{"cannot be encoded", map[string]interface{}{"callback": func() {}}, "", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dimensions, err := MarshalDimensions(test.dimensions)
+			if test.valid {
+				require.NoError(t, err)
+				assert.Equal(t, test.expected, dimensions)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidDimensions)
+			}
+		})
+	}
+}
+
+// TestConversion_DecodeDimensions will test the method DecodeDimensions()
+func TestConversion_DecodeDimensions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("struct", func(t *testing.T) {
+		conversion := &Conversion{CustomDimensions: `{"order_id":"order-1","total":19.99}`}
+		var dimensions testDimensions
+		require.NoError(t, conversion.DecodeDimensions(&dimensions))
+		assert.Equal(t, testDimensions{OrderID: "order-1", Total: NewDecimal(1999, 2)}, dimensions)
+	})
+
+	t.Run("map", func(t *testing.T) {
+		conversion := newTestConversion()
+		dimensions := make(map[string]string)
+		require.NoError(t, conversion.DecodeDimensions(&dimensions))
+		assert.Equal(t, map[string]string{"some_field": "some_value"}, dimensions)
+	})
+
+	t.Run("no dimensions", func(t *testing.T) {
+		var dimensions map[string]string
+		require.NoError(t, (&Conversion{}).DecodeDimensions(&dimensions))
+		assert.Nil(t, dimensions)
+	})
+
+	t.Run("not json", func(t *testing.T) {
+		var dimensions map[string]string
+		err := (&Conversion{CustomDimensions: "plain text"}).DecodeDimensions(&dimensions)
+		assert.ErrorIs(t, err, ErrInvalidDimensions)
+	})
+}
+
+// TestWithDimensions will test creating conversions with structured dimensions
+func TestWithDimensions(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	endpoint := fmt.Sprintf("%s/%s", EnvironmentDevelopment.apiURL, modelConversion)
+
+	t.Run("dimensions are sent as json", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		var payload map[string]string
+		httpmock.Reset()
+		httpmock.RegisterResponder(http.MethodPost, endpoint, func(req *http.Request) (*http.Response, error) {
+			if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+				return nil, err
+			}
+			return httpmock.NewJsonResponse(http.StatusCreated, newTestConversion())
+		})
+
+		_, _, err = client.CreateConversion(
+			WithGoalID(testGoalID), WithTncpwSession(testTncpwSession),
+			WithDimensions(&testDimensions{Coupon: "SAVE10", OrderID: "order-1", Total: NewDecimal(5, 0)}),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, `{"coupon":"SAVE10","order_id":"order-1","total":5}`, payload[fieldCustomDimensions])
+	})
+
+	t.Run("invalid dimensions are not sent", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		err = mockResponseData(http.MethodPost, endpoint, http.StatusCreated, newTestConversion())
+		require.NoError(t, err)
+
+		_, _, err = client.CreateConversion(
+			WithGoalID(testGoalID), WithTncpwSession(testTncpwSession),
+			WithDimensions(map[string]string{"order id": "1"}),
+		)
+		assert.ErrorIs(t, err, ErrInvalidDimensions)
+		assert.Equal(t, 0, httpmock.GetTotalCallCount())
+	})
+
+	t.Run("last dimensions option wins", func(t *testing.T) {
+		options := new(conversionOptions)
+		WithDimensions([]string{"invalid"})(options)
+		WithCustomDimensions("plain text")(options)
+		assert.NoError(t, options.dimensionsErr)
+		assert.Equal(t, "plain text", options.customDimensions)
+	})
+}
+
+// ExampleMarshalDimensions example using MarshalDimensions()
+//
+// See more examples in /examples/
+func ExampleMarshalDimensions() {
+	dimensions, err := MarshalDimensions(map[string]string{"order_id": "order-1", "coupon": "SAVE10"})
+	if err != nil {
+		fmt.Printf("error encoding dimensions: %s", err.Error())
+		return
+	}
+	fmt.Printf("custom dimensions: %s", dimensions)
+	// Output:custom dimensions: {"coupon":"SAVE10","order_id":"order-1"}
+}
@@ -0,0 +1,68 @@
+package tonicpow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Sentinel errors that can be used with errors.Is() against any error returned from the API
+var (
+	// ErrNotFound is returned when the requested resource does not exist (404)
+	ErrNotFound = errors.New("resource not found")
+
+	// ErrUnauthorized is returned when the API key is missing, invalid or not permitted (401, 403)
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrRateLimited is returned when too many requests have been made (429)
+	ErrRateLimited = errors.New("rate limited")
+
+	// ErrValidation is returned when the API rejected the request data (400, 422)
+	ErrValidation = errors.New("validation failed")
+)
+
+// Error will return the error message from the API (implements the error interface)
+func (e *Error) Error() string {
+	if len(e.Message) > 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("api error: %d %s", e.statusCode(), http.StatusText(e.statusCode()))
+}
+
+// Is will return true if the target is the matching sentinel error for the status code (used by errors.Is)
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.statusCode() == http.StatusNotFound
+	case ErrUnauthorized:
+		return e.statusCode() == http.StatusUnauthorized || e.statusCode() == http.StatusForbidden
+	case ErrRateLimited:
+		return e.statusCode() == http.StatusTooManyRequests
+	case ErrValidation:
+		return e.statusCode() == http.StatusBadRequest || e.statusCode() == http.StatusUnprocessableEntity
+	}
+	return false
+}
+
+// statusCode will return the HTTP status code, falling back to the API error code if not set
+func (e *Error) statusCode() int {
+	if e.StatusCode > 0 {
+		return e.StatusCode
+	}
+	return e.Code
+}
+
+// newError will create an Error from the response body of a failed request
+//
+// If the body is not a valid API error, the Error will only contain the status code
+func newError(statusCode int, body []byte) *Error {
+	apiError := new(Error)
+	if len(body) > 0 {
+		_ = json.Unmarshal(body, apiError)
+	}
+	if apiError.StatusCode == 0 {
+		apiError.StatusCode = statusCode
+	}
+	return apiError
+}
@@ -0,0 +1,144 @@
+package tonicpow
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestError_Error will test the method Error()
+func TestError_Error(t *testing.T) {
+	t.Parallel()
+
+	t.Run("api message", func(t *testing.T) {
+		e := &Error{Message: "some error message", StatusCode: http.StatusBadRequest}
+		assert.Equal(t, "some error message", e.Error())
+	})
+
+	t.Run("missing message", func(t *testing.T) {
+		e := &Error{StatusCode: http.StatusNotFound}
+		assert.Equal(t, "api error: 404 Not Found", e.Error())
+	})
+
+	t.Run("missing status code, use api code", func(t *testing.T) {
+		e := &Error{Code: http.StatusTooManyRequests}
+		assert.Equal(t, "api error: 429 Too Many Requests", e.Error())
+	})
+}
+
+// TestError_Is will test the method Is()
+func TestError_Is(t *testing.T) {
+	t.Parallel()
+
+	var tests = []struct {
+		statusCode int
+		expected   error
+	}{
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrUnauthorized},
+		{http.StatusTooManyRequests, ErrRateLimited},
+		{http.StatusBadRequest, ErrValidation},
+		{http.StatusUnprocessableEntity, ErrValidation},
+	}
+	sentinels := []error{ErrNotFound, ErrUnauthorized, ErrRateLimited, ErrValidation}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("status code %d", test.statusCode), func(t *testing.T) {
+			var err error = &Error{StatusCode: test.statusCode}
+			for _, sentinel := range sentinels {
+				assert.Equal(t, sentinel == test.expected, errors.Is(err, sentinel))
+			}
+		})
+	}
+
+	t.Run("server error matches nothing", func(t *testing.T) {
+		var err error = &Error{StatusCode: http.StatusInternalServerError}
+		for _, sentinel := range sentinels {
+			assert.False(t, errors.Is(err, sentinel))
+		}
+	})
+
+	t.Run("wrapped error", func(t *testing.T) {
+		err := fmt.Errorf("failed: %w", &Error{StatusCode: http.StatusNotFound})
+		assert.True(t, errors.Is(err, ErrNotFound))
+	})
+}
+
+// TestNewError will test the method newError()
+func TestNewError(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid api error", func(t *testing.T) {
+		e := newError(http.StatusBadRequest, []byte(`{"code":400,"message":"bad","request_guid":"abc","status_code":400}`))
+		assert.Equal(t, "bad", e.Message)
+		assert.Equal(t, "abc", e.RequestGUID)
+		assert.Equal(t, http.StatusBadRequest, e.StatusCode)
+	})
+
+	t.Run("empty body", func(t *testing.T) {
+		e := newError(http.StatusNotFound, nil)
+		assert.Equal(t, http.StatusNotFound, e.StatusCode)
+		assert.Equal(t, "", e.Message)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		e := newError(http.StatusBadGateway, []byte(`<html>bad gateway</html>`))
+		assert.Equal(t, http.StatusBadGateway, e.StatusCode)
+	})
+}
+
+// TestClient_Request_Error will test the typed errors returned from Request()
+func TestClient_Request_Error(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	endpoint := fmt.Sprintf("%s/%s/details/%d", EnvironmentDevelopment.apiURL, modelGoal, testGoalID)
+
+	t.Run("api error (errors.As)", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		apiError := &Error{
+			Code:        http.StatusNotFound,
+			Data:        "goal",
+			Message:     "goal not found",
+			Method:      http.MethodGet,
+			RequestGUID: "7f3d97a8fd67ff57861904df6118dcc8",
+			StatusCode:  http.StatusNotFound,
+			URL:         endpoint,
+		}
+
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusNotFound, apiError)
+		assert.NoError(t, err)
+
+		var response *StandardResponse
+		_, response, err = client.GetGoal(testGoalID)
+		assert.Error(t, err)
+		assert.NotNil(t, response)
+		assert.True(t, errors.Is(err, ErrNotFound))
+
+		var typedErr *Error
+		assert.True(t, errors.As(err, &typedErr))
+		assert.Equal(t, apiError.RequestGUID, typedErr.RequestGUID)
+		assert.Equal(t, apiError.Code, typedErr.Code)
+		assert.Equal(t, "goal", typedErr.Data)
+		assert.Equal(t, response.Error, typedErr)
+	})
+
+	t.Run("empty body", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusUnauthorized, nil)
+		assert.NoError(t, err)
+
+		_, _, err = client.GetGoal(testGoalID)
+		assert.Error(t, err)
+		assert.True(t, errors.Is(err, ErrUnauthorized))
+		assert.False(t, errors.Is(err, ErrNotFound))
+	})
+}
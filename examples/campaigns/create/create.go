@@ -22,7 +22,7 @@ func main() {
 	campaign := &tonicpow.Campaign{
 		AdvertiserProfileID: 23,
 		Description:         "example campaign",
-		PayPerClickRate:     1,
+		PayPerClickRate:     tonicpow.NewDecimal(1, 0),
 		TargetURL:           "https://tonicpow.com",
 		Title:               "Example Campaign",
 	}
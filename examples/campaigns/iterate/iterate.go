@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/tonicpow/go-tonicpow"
+)
+
+func main() {
+
+	// Load the api client
+	client, err := tonicpow.NewClient(
+		tonicpow.WithAPIKey(os.Getenv("TONICPOW_API_KEY")),
+		tonicpow.WithEnvironmentString(os.Getenv("TONICPOW_ENVIRONMENT")),
+	)
+	if err != nil {
+		log.Fatalf("error in NewClient: %s", err.Error())
+	}
+
+	// Iterate all campaigns (fetching the next page in the background)
+	iter := client.IterateCampaigns(
+		context.Background(), "", "", "", 0, false,
+		tonicpow.WithPageSize(50), tonicpow.WithPrefetch(),
+	)
+	var total int
+	for iter.Next() {
+		total++
+		log.Printf("campaign: %s", iter.Campaign().Slug)
+	}
+	if err = iter.Err(); err != nil {
+		log.Fatalf("error in IterateCampaigns: %s", err.Error())
+	}
+
+	log.Printf("campaigns found: %d", total)
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/tonicpow/go-tonicpow"
+)
+
+func main() {
+
+	// Load the api client (the rate limit applies to every conversion in the batch)
+	client, err := tonicpow.NewClient(
+		tonicpow.WithAPIKey(os.Getenv("TONICPOW_API_KEY")),
+		tonicpow.WithEnvironmentString(os.Getenv("TONICPOW_ENVIRONMENT")),
+		tonicpow.WithRateLimit(10, 20),
+	)
+	if err != nil {
+		log.Fatalf("error in NewClient: %s", err.Error())
+	}
+
+	// Build the batch (one conversion per order, the order id is the idempotency key)
+	sessions := map[string]string{
+		"order-12345": "insert-your-visitor-tncpw-session-id",
+		"order-12346": "insert-another-visitor-tncpw-session-id",
+	}
+	var batch [][]tonicpow.ConversionOps
+	for orderID, session := range sessions {
+		batch = append(batch, []tonicpow.ConversionOps{
+			tonicpow.WithGoalID(13),
+			tonicpow.WithTncpwSession(session),
+			tonicpow.WithConversionIdempotencyKey(orderID),
+		})
+	}
+
+	// Create conversions (5 at a time)
+	var results []*tonicpow.ConversionResult
+	results, err = client.CreateConversions(batch, tonicpow.WithBatchConcurrency(5))
+	for _, result := range results {
+		if result.Err != nil {
+			log.Printf("conversion %d failed: %s", result.Index, result.Err.Error())
+			continue
+		}
+		log.Printf("created conversion: %d", result.Conversion.ID)
+	}
+	if err != nil {
+		log.Fatalf("error in CreateConversions: %s", err.Error())
+	}
+}
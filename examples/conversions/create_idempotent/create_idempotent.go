@@ -0,0 +1,35 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/tonicpow/go-tonicpow"
+)
+
+func main() {
+
+	// Load the api client (retry non-idempotent requests, idempotency keys make them safe)
+	client, err := tonicpow.NewClient(
+		tonicpow.WithAPIKey(os.Getenv("TONICPOW_API_KEY")),
+		tonicpow.WithEnvironmentString(os.Getenv("TONICPOW_ENVIRONMENT")),
+		tonicpow.WithRetryNonIdempotent(),
+	)
+	if err != nil {
+		log.Fatalf("error in NewClient: %s", err.Error())
+	}
+
+	// Create conversion (use your own unique key, such as the order id, to reconcile later)
+	var conversion *tonicpow.Conversion
+	var response *tonicpow.StandardResponse
+	conversion, response, err = client.CreateConversion(
+		tonicpow.WithGoalID(13),
+		tonicpow.WithTncpwSession("insert-your-visitor-tncpw-session-id"),
+		tonicpow.WithConversionIdempotencyKey("order-12345"),
+	)
+	if err != nil {
+		log.Fatalf("error in CreateConversion: %s", err.Error())
+	}
+
+	log.Printf("created conversion: %d (idempotency key: %s)", conversion.ID, response.IdempotencyKey)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"github.com/tonicpow/go-tonicpow"
+)
+
+func main() {
+
+	// Load the api client
+	client, err := tonicpow.NewClient(
+		tonicpow.WithAPIKey(os.Getenv("TONICPOW_API_KEY")),
+		tonicpow.WithEnvironmentString(os.Getenv("TONICPOW_ENVIRONMENT")),
+	)
+	if err != nil {
+		log.Fatalf("error in NewClient: %s", err.Error())
+	}
+
+	// Track delayed conversions by order id (warn 5 minutes before the cancellation window closes)
+	manager := tonicpow.NewDelayManager(client, tonicpow.WithCancelWarning(5*time.Minute,
+		func(conversion *tonicpow.DelayedConversion, remaining time.Duration) {
+			log.Printf("order %s can be canceled for %s", conversion.Reference, remaining)
+		},
+	))
+	manager.Start(context.Background())
+	defer manager.Stop()
+
+	// Create a conversion paid out in 30 minutes
+	var delayed *tonicpow.DelayedConversion
+	if delayed, err = manager.Create(
+		context.Background(), "order-12345",
+		tonicpow.WithGoalID(13),
+		tonicpow.WithTncpwSession("insert-your-visitor-tncpw-session-id"),
+		tonicpow.WithDelay(30),
+	); err != nil {
+		log.Fatalf("error in Create: %s", err.Error())
+	}
+	log.Printf("conversion %d is paid out at %s", delayed.Conversion.ID, delayed.PayoutAfter)
+
+	// The order was refunded (cancels every conversion of the order)
+	var conversions []*tonicpow.Conversion
+	if conversions, err = manager.Cancel(context.Background(), "order-12345", "refund"); err != nil {
+		log.Fatalf("error in Cancel: %s", err.Error())
+	}
+	for _, conversion := range conversions {
+		log.Printf("conversion %d: %s", conversion.ID, conversion.Status)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/tonicpow/go-tonicpow"
+)
+
+func main() {
+
+	// Load the api client
+	client, err := tonicpow.NewClient(
+		tonicpow.WithAPIKey(os.Getenv("TONICPOW_API_KEY")),
+		tonicpow.WithEnvironmentString(os.Getenv("TONICPOW_ENVIRONMENT")),
+	)
+	if err != nil {
+		log.Fatalf("error in NewClient: %s", err.Error())
+	}
+
+	// Load the outbox (queued conversions are kept in the directory between restarts)
+	var store *tonicpow.FileOutboxStore
+	if store, err = tonicpow.NewFileOutboxStore("tonicpow-outbox"); err != nil {
+		log.Fatalf("error in NewFileOutboxStore: %s", err.Error())
+	}
+	var outbox *tonicpow.Outbox
+	if outbox, err = tonicpow.NewOutbox(client, store); err != nil {
+		log.Fatalf("error in NewOutbox: %s", err.Error())
+	}
+
+	// Deliver in the background until interrupted
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	outbox.Start(ctx)
+	defer outbox.Stop()
+
+	// Queue a conversion (the order id is the idempotency key)
+	var entry *tonicpow.OutboxEntry
+	if entry, err = outbox.Enqueue(&tonicpow.ConversionRequest{
+		GoalID:         13,
+		IdempotencyKey: "order-12345",
+		TncpwSession:   "insert-your-visitor-tncpw-session-id",
+	}); err != nil {
+		log.Fatalf("error in Enqueue: %s", err.Error())
+	}
+	log.Printf("queued conversion: %s", entry.ID)
+
+	<-ctx.Done()
+	log.Printf("outbox: %+v (dead letters: %d)", outbox.Stats(), len(outbox.DeadLetters()))
+}
@@ -24,7 +24,7 @@ func main() {
 		Description:    "Example goal description",
 		MaxPerPromoter: 1,
 		Name:           "example_goal",
-		PayoutRate:     0.01,
+		PayoutRate:     tonicpow.NewDecimal(1, 2),
 		PayoutType:     "flat",
 		Title:          "Example Goal",
 	}
@@ -23,6 +23,13 @@ func main() {
 		// tonicpow.WithHTTPTimeout(10*time.Second),
 		// tonicpow.WithRequestTracing(),
 		// tonicpow.WithRetryCount(3),
+		// tonicpow.WithRetryPolicy(tonicpow.NewBackoffPolicy(3)),
+		// tonicpow.WithRateLimit(10, 20),
+		// tonicpow.WithCache(tonicpow.NewLRUCache(1000)),
+		// tonicpow.WithCacheTTL(tonicpow.CacheEndpointCampaign, 30*time.Second),
+		// tonicpow.WithLogger(slog.Default()),
+		// tonicpowotel.WithInstrumentation(), // OpenTelemetry spans & metrics (or tonicpow.WithMiddleware())
+		// tonicpow.WithHooks(&tonicpow.Hooks{OnError: func(ctx context.Context, req *tonicpow.RequestInfo, err error) {}}),
 		// tonicpow.WithUserAgent("my custom user agent v9.0.9"),
 
 		/*
@@ -25,5 +25,5 @@ func main() {
 		log.Fatalf("error in GetCurrentRate: %s", err.Error())
 	}
 
-	log.Printf("rate: %s %f is %d sats", rate.Currency, rate.CurrencyAmount, rate.PriceInSatoshis)
+	log.Printf("rate: %s %s is %d sats", rate.Currency, rate.CurrencyAmount, rate.PriceInSatoshis)
 }
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/tonicpow/go-tonicpow"
+)
+
+func main() {
+
+	// Load the webhook handler (using the shared secret for the app)
+	handler, err := tonicpow.NewWebhookHandler(os.Getenv("TONICPOW_WEBHOOK_SECRET"))
+	if err != nil {
+		log.Fatalf("error in NewWebhookHandler: %s", err.Error())
+	}
+
+	// Register callbacks
+	handler.OnConversionPaid(func(ctx context.Context, event *tonicpow.WebhookEvent, conversion *tonicpow.Conversion) error {
+		log.Printf("conversion paid: %d (tx: %s)", conversion.ID, conversion.TxID)
+		return nil
+	})
+	handler.OnCampaignLowBalance(func(ctx context.Context, event *tonicpow.WebhookEvent, campaign *tonicpow.Campaign) error {
+		log.Printf("campaign %s is low on funds: %s", campaign.Slug, campaign.BalanceMoney())
+		return nil
+	})
+
+	// Start the server
+	http.Handle("/tonicpow/webhook", handler)
+	log.Fatal(http.ListenAndServe(":8080", nil))
+}
@@ -0,0 +1,363 @@
+package tonicpow
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// campaignLinkPath is the path of a campaign link in the feeds (/campaign/<slug>)
+	campaignLinkPath = "/campaign/"
+
+	// jsonFeedVersionPrefix is the prefix of the JSON Feed version
+	jsonFeedVersionPrefix = "https://jsonfeed.org/version/"
+)
+
+// ErrInvalidFeed is returned when a campaigns feed is malformed (can be used with errors.Is())
+var ErrInvalidFeed = errors.New("invalid feed")
+
+// Feed is a parsed campaigns feed (the same model for RSS, Atom and JSON feeds)
+type Feed struct {
+	Description string      `json:"description"`
+	Items       []*FeedItem `json:"items"`
+	Link        string      `json:"link"`
+	Title       string      `json:"title"`
+	Type        FeedType    `json:"type"`
+	UpdatedAt   time.Time   `json:"updated_at,omitempty"`
+}
+
+// FeedItem is a single campaign in the feed
+type FeedItem struct {
+	Author          string    `json:"author,omitempty"`
+	Campaign        *Campaign `json:"campaign,omitempty"` // Set by Feed.Correlate()
+	Description     string    `json:"description"`
+	ID              string    `json:"id"`
+	ImageURL        string    `json:"image_url,omitempty"`
+	Link            string    `json:"link"`
+	PayPerClickRate Decimal   `json:"pay_per_click_rate"` // Set by Feed.Correlate()
+	PublishedAt     time.Time `json:"published_at,omitempty"`
+	Slug            string    `json:"slug"` // Campaign slug (from the link)
+	Title           string    `json:"title"`
+}
+
+// Correlate will match the feed items to the campaigns (by slug, or by public guid)
+// and set the Campaign, PayPerClickRate and ImageURL (if missing) of each matched item
+//
+// Returns the number of items that were matched
+func (f *Feed) Correlate(campaigns []*Campaign) (matched int) {
+	bySlug := make(map[string]*Campaign, len(campaigns))
+	byGUID := make(map[string]*Campaign, len(campaigns))
+	for _, campaign := range campaigns {
+		if campaign == nil {
+			continue
+		}
+		if len(campaign.Slug) > 0 {
+			bySlug[campaign.Slug] = campaign
+		}
+		if len(campaign.PublicGUID) > 0 {
+			byGUID[campaign.PublicGUID] = campaign
+		}
+	}
+
+	for _, item := range f.Items {
+		campaign, ok := bySlug[item.Slug]
+		if !ok {
+			if campaign, ok = byGUID[item.ID]; !ok {
+				continue
+			}
+		}
+		item.Campaign = campaign
+		item.PayPerClickRate = campaign.PayPerClickRate
+		if len(item.ImageURL) == 0 {
+			item.ImageURL = campaign.ImageURL
+		}
+		matched++
+	}
+	return
+}
+
+// ParseFeed will parse a campaigns feed (RSS, Atom or JSON) into a Feed
+//
+// Malformed feeds return an error that wraps ErrInvalidFeed
+func ParseFeed(feedType FeedType, data []byte) (*Feed, error) {
+	var feed *Feed
+	var err error
+	switch feedType {
+	case FeedTypeRSS:
+		feed, err = parseRSSFeed(data)
+	case FeedTypeAtom:
+		feed, err = parseAtomFeed(data)
+	case FeedTypeJSON:
+		feed, err = parseJSONFeed(data)
+	default:
+		return nil, fmt.Errorf("%w: unknown feed type: %s", ErrInvalidFeed, feedType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	feed.Type = feedType
+
+	// Validate the items
+	for index, item := range feed.Items {
+		if len(item.Link) == 0 && len(item.Title) == 0 {
+			return nil, fmt.Errorf("%w: item %d is missing a link and title", ErrInvalidFeed, index)
+		}
+		item.Slug = campaignSlug(item.Link)
+		if len(item.Slug) == 0 && len(item.ID) > 0 {
+			item.Slug = campaignSlug(item.ID)
+		}
+	}
+	return feed, nil
+}
+
+// CampaignsFeedParsed will return the feed of active campaigns parsed into a Feed (see CampaignsFeed)
+// This will return an Error if no campaigns are found (404) or ErrInvalidFeed if the feed is malformed
+func (c *Client) CampaignsFeedParsed(feedType FeedType) (feed *Feed, response *StandardResponse, err error) {
+	return c.CampaignsFeedParsedWithContext(context.Background(), feedType)
+}
+
+// CampaignsFeedParsedWithContext is the same as CampaignsFeedParsed but uses the given context
+func (c *Client) CampaignsFeedParsedWithContext(ctx context.Context, feedType FeedType) (feed *Feed,
+	response *StandardResponse, err error) {
+
+	var raw string
+	if raw, response, err = c.CampaignsFeedWithContext(ctx, feedType); err != nil {
+		return
+	}
+	feed, err = ParseFeed(feedType, []byte(raw))
+	return
+}
+
+// campaignSlug will return the campaign slug from a campaign link (https://tonicpow.com/campaign/<slug>)
+func campaignSlug(link string) string {
+	index := strings.LastIndex(link, campaignLinkPath)
+	if index < 0 {
+		return ""
+	}
+	slug := link[index+len(campaignLinkPath):]
+	if end := strings.IndexAny(slug, "/?#"); end >= 0 {
+		slug = slug[:end]
+	}
+	if unescaped, err := url.PathUnescape(slug); err == nil {
+		slug = unescaped
+	}
+	return slug
+}
+
+// parseFeedDate will parse the date using the first layout that matches (empty is allowed)
+func parseFeedDate(value string, layouts ...string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if len(value) == 0 {
+		return time.Time{}, nil
+	}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: invalid date: %s", ErrInvalidFeed, value)
+}
+
+// rssDocument is the RSS 2.0 document
+type rssDocument struct {
+	XMLName xml.Name `xml:"rss"`
+	Channel *struct {
+		Description string `xml:"description"`
+		Items       []struct {
+			Author      string `xml:"author"`
+			Description string `xml:"description"`
+			Enclosure   *struct {
+				Type string `xml:"type,attr"`
+				URL  string `xml:"url,attr"`
+			} `xml:"enclosure"`
+			GUID    string `xml:"guid"`
+			Link    string `xml:"link"`
+			PubDate string `xml:"pubDate"`
+			Title   string `xml:"title"`
+		} `xml:"item"`
+		LastBuildDate string `xml:"lastBuildDate"`
+		Link          string `xml:"link"`
+		PubDate       string `xml:"pubDate"`
+		Title         string `xml:"title"`
+	} `xml:"channel"`
+}
+
+// rssDateLayouts are the date layouts used in RSS feeds
+var rssDateLayouts = []string{time.RFC1123Z, time.RFC1123, time.RFC822Z, time.RFC822}
+
+// parseRSSFeed will parse an RSS 2.0 feed
+func parseRSSFeed(data []byte) (*Feed, error) {
+	doc := new(rssDocument)
+	if err := xml.Unmarshal(data, doc); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFeed, err.Error())
+	} else if doc.Channel == nil {
+		return nil, fmt.Errorf("%w: missing rss channel", ErrInvalidFeed)
+	}
+
+	feed := &Feed{Description: doc.Channel.Description, Link: doc.Channel.Link, Title: doc.Channel.Title}
+	updated := doc.Channel.LastBuildDate
+	if len(updated) == 0 {
+		updated = doc.Channel.PubDate
+	}
+	var err error
+	if feed.UpdatedAt, err = parseFeedDate(updated, rssDateLayouts...); err != nil {
+		return nil, err
+	}
+
+	for _, rssItem := range doc.Channel.Items {
+		item := &FeedItem{
+			Author:      rssItem.Author,
+			Description: rssItem.Description,
+			ID:          rssItem.GUID,
+			Link:        rssItem.Link,
+			Title:       rssItem.Title,
+		}
+		if rssItem.Enclosure != nil && strings.HasPrefix(rssItem.Enclosure.Type, "image/") {
+			item.ImageURL = rssItem.Enclosure.URL
+		}
+		if item.PublishedAt, err = parseFeedDate(rssItem.PubDate, rssDateLayouts...); err != nil {
+			return nil, err
+		}
+		feed.Items = append(feed.Items, item)
+	}
+	return feed, nil
+}
+
+// atomLink is a link of an Atom feed or entry
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+// atomDocument is the Atom document
+type atomDocument struct {
+	XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+	Entries []struct {
+		Author *struct {
+			Name string `xml:"name"`
+		} `xml:"author"`
+		ID        string     `xml:"id"`
+		Links     []atomLink `xml:"link"`
+		Published string     `xml:"published"`
+		Summary   string     `xml:"summary"`
+		Title     string     `xml:"title"`
+		Updated   string     `xml:"updated"`
+	} `xml:"entry"`
+	Links    []atomLink `xml:"link"`
+	Subtitle string     `xml:"subtitle"`
+	Title    string     `xml:"title"`
+	Updated  string     `xml:"updated"`
+}
+
+// parseAtomFeed will parse an Atom feed
+func parseAtomFeed(data []byte) (*Feed, error) {
+	doc := new(atomDocument)
+	if err := xml.Unmarshal(data, doc); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFeed, err.Error())
+	}
+
+	feed := &Feed{Description: doc.Subtitle, Title: doc.Title}
+	feed.Link, _ = atomLinks(doc.Links)
+	var err error
+	if feed.UpdatedAt, err = parseFeedDate(doc.Updated, time.RFC3339); err != nil {
+		return nil, err
+	}
+
+	for _, entry := range doc.Entries {
+		item := &FeedItem{Description: entry.Summary, ID: entry.ID, Title: entry.Title}
+		item.Link, item.ImageURL = atomLinks(entry.Links)
+		if entry.Author != nil {
+			item.Author = entry.Author.Name
+		}
+		published := entry.Published
+		if len(published) == 0 {
+			published = entry.Updated
+		}
+		if item.PublishedAt, err = parseFeedDate(published, time.RFC3339); err != nil {
+			return nil, err
+		}
+		feed.Items = append(feed.Items, item)
+	}
+	return feed, nil
+}
+
+// atomLinks will return the alternate link and the image (enclosure) of the links
+func atomLinks(links []atomLink) (link, image string) {
+	for _, l := range links {
+		switch l.Rel {
+		case "", "alternate":
+			if len(link) == 0 {
+				link = l.Href
+			}
+		case "enclosure":
+			if len(image) == 0 && (len(l.Type) == 0 || strings.HasPrefix(l.Type, "image/")) {
+				image = l.Href
+			}
+		}
+	}
+	return
+}
+
+// jsonFeedDocument is the JSON Feed document
+type jsonFeedDocument struct {
+	Description string `json:"description"`
+	HomePageURL string `json:"home_page_url"`
+	Items       []struct {
+		Author *struct {
+			Name string `json:"name"`
+		} `json:"author"`
+		BannerImage   string `json:"banner_image"`
+		ContentText   string `json:"content_text"`
+		DatePublished string `json:"date_published"`
+		ID            string `json:"id"`
+		Image         string `json:"image"`
+		Summary       string `json:"summary"`
+		Title         string `json:"title"`
+		URL           string `json:"url"`
+	} `json:"items"`
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// parseJSONFeed will parse a JSON Feed
+func parseJSONFeed(data []byte) (*Feed, error) {
+	doc := new(jsonFeedDocument)
+	if err := json.Unmarshal(data, doc); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFeed, err.Error())
+	} else if !strings.HasPrefix(doc.Version, jsonFeedVersionPrefix) {
+		return nil, fmt.Errorf("%w: unknown json feed version: %s", ErrInvalidFeed, doc.Version)
+	}
+
+	feed := &Feed{Description: doc.Description, Link: doc.HomePageURL, Title: doc.Title}
+	for _, jsonItem := range doc.Items {
+		item := &FeedItem{
+			Description: jsonItem.Summary,
+			ID:          jsonItem.ID,
+			ImageURL:    jsonItem.Image,
+			Link:        jsonItem.URL,
+			Title:       jsonItem.Title,
+		}
+		if len(item.Description) == 0 {
+			item.Description = jsonItem.ContentText
+		}
+		if len(item.ImageURL) == 0 {
+			item.ImageURL = jsonItem.BannerImage
+		}
+		if jsonItem.Author != nil {
+			item.Author = jsonItem.Author.Name
+		}
+		var err error
+		if item.PublishedAt, err = parseFeedDate(jsonItem.DatePublished, time.RFC3339); err != nil {
+			return nil, err
+		}
+		feed.Items = append(feed.Items, item)
+	}
+	return feed, nil
+}
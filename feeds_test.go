@@ -0,0 +1,195 @@
+package tonicpow
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseFeed will test the method ParseFeed()
+func TestParseFeed(t *testing.T) {
+	t.Parallel()
+
+	published := time.Date(2019, 9, 5, 1, 50, 6, 0, time.UTC)
+
+	tests := []struct {
+		feedType FeedType
+		data     string
+		updated  time.Time
+	}{
+		{FeedTypeRSS, newTestCampaignFeedRSS(), time.Date(2021, 6, 4, 17, 20, 28, 0, time.UTC)},
+		{FeedTypeAtom, newTestCampaignFeedAtom(), time.Date(2021, 6, 4, 17, 23, 38, 0, time.UTC)},
+		{FeedTypeJSON, newTestCampaignFeedJSON(), time.Time{}},
+	}
+	for _, test := range tests {
+		t.Run("parse "+string(test.feedType), func(t *testing.T) {
+			feed, err := ParseFeed(test.feedType, []byte(test.data))
+			require.NoError(t, err)
+			assert.Equal(t, test.feedType, feed.Type)
+			assert.Equal(t, "TonicPow", feed.Title)
+			assert.Equal(t, "https://tonicpow.com", feed.Link)
+			assert.Equal(t, "List of active campaigns, ordered by newest first", feed.Description)
+			assert.True(t, test.updated.Equal(feed.UpdatedAt))
+
+			require.Len(t, feed.Items, 1)
+			item := feed.Items[0]
+			assert.Equal(t, "TonicPow", item.Title)
+			assert.Equal(t, "TonicPow", item.Author)
+			assert.Equal(t, "https://tonicpow.com/campaign/tonicpow", item.Link)
+			assert.Equal(t, "tonicpow", item.Slug)
+			assert.True(t, published.Equal(item.PublishedAt))
+			assert.Contains(t, item.Description, "Earn BSV for sharing things you like")
+		})
+	}
+
+	t.Run("images", func(t *testing.T) {
+		feed, err := ParseFeed(FeedTypeRSS, []byte(`<rss><channel><item><title>a</title>
+<link>https://tonicpow.com/campaign/a-b?x=1</link><guid>guid-a</guid>
+<enclosure url="https://img.com/a.jpg" type="image/jpeg"></enclosure></item></channel></rss>`))
+		require.NoError(t, err)
+		assert.Equal(t, "a-b", feed.Items[0].Slug)
+		assert.Equal(t, "guid-a", feed.Items[0].ID)
+		assert.Equal(t, "https://img.com/a.jpg", feed.Items[0].ImageURL)
+
+		feed, err = ParseFeed(FeedTypeAtom, []byte(`<feed xmlns="http://www.w3.org/2005/Atom"><entry><title>a</title>
+<link href="https://img.com/a.jpg" rel="enclosure" type="image/jpeg"></link>
+<link href="https://tonicpow.com/campaign/a" rel="alternate"></link></entry></feed>`))
+		require.NoError(t, err)
+		assert.Equal(t, "a", feed.Items[0].Slug)
+		assert.Equal(t, "https://img.com/a.jpg", feed.Items[0].ImageURL)
+
+		feed, err = ParseFeed(FeedTypeJSON, []byte(`{"version":"https://jsonfeed.org/version/1.1",
+"items":[{"id":"guid-a","url":"https://tonicpow.com/campaign/a","title":"a","image":"https://img.com/a.jpg"}]}`))
+		require.NoError(t, err)
+		assert.Equal(t, "a", feed.Items[0].Slug)
+		assert.Equal(t, "https://img.com/a.jpg", feed.Items[0].ImageURL)
+	})
+
+	t.Run("malformed feeds", func(t *testing.T) {
+		malformed := []struct {
+			feedType FeedType
+			data     string
+		}{
+			{FeedTypeRSS, ""},
+			{FeedTypeRSS, `<rss><channel><item><title>a</title>`},
+			{FeedTypeRSS, `<rss></rss>`},
+			{FeedTypeRSS, newTestCampaignFeedAtom()},
+			{FeedTypeRSS, `<rss><channel><item><title>a</title><pubDate>yesterday</pubDate></item></channel></rss>`},
+			{FeedTypeRSS, `<rss><channel><item><description>a</description></item></channel></rss>`},
+			{FeedTypeAtom, newTestCampaignFeedRSS()},
+			{FeedTypeAtom, `<feed xmlns="http://www.w3.org/2005/Atom"><updated>today</updated></feed>`},
+			{FeedTypeJSON, `{`},
+			{FeedTypeJSON, `{"version":"1","items":[]}`},
+			{FeedTypeJSON, `{"version":"https://jsonfeed.org/version/1","items":[{"url":"a","date_published":"x"}]}`},
+			{"unknown", newTestCampaignFeedJSON()},
+		}
+		for _, test := range malformed {
+			feed, err := ParseFeed(test.feedType, []byte(test.data))
+			assert.ErrorIs(t, err, ErrInvalidFeed, test.data)
+			assert.Nil(t, feed)
+		}
+	})
+}
+
+// TestFeed_Correlate will test the method Correlate()
+func TestFeed_Correlate(t *testing.T) {
+	t.Parallel()
+
+	campaign := newTestCampaign()
+	other := newTestCampaign()
+	other.Slug = "other"
+	other.PublicGUID = "other-guid"
+	other.PayPerClickRate = NewDecimal(5, 0)
+
+	feed := &Feed{Items: []*FeedItem{
+		{Slug: campaign.Slug},
+		{ID: other.PublicGUID, ImageURL: "https://img.com/custom.jpg"},
+		{Slug: "unknown"},
+	}}
+	assert.Equal(t, 2, feed.Correlate([]*Campaign{nil, campaign, other}))
+
+	assert.Equal(t, campaign, feed.Items[0].Campaign)
+	assert.Equal(t, campaign.PayPerClickRate, feed.Items[0].PayPerClickRate)
+	assert.Equal(t, campaign.ImageURL, feed.Items[0].ImageURL)
+
+	assert.Equal(t, other, feed.Items[1].Campaign)
+	assert.Equal(t, NewDecimal(5, 0), feed.Items[1].PayPerClickRate)
+	assert.Equal(t, "https://img.com/custom.jpg", feed.Items[1].ImageURL)
+
+	assert.Nil(t, feed.Items[2].Campaign)
+}
+
+// TestClient_CampaignsFeedParsed will test the method CampaignsFeedParsed()
+func TestClient_CampaignsFeedParsed(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	endpoint := fmt.Sprintf(
+		"%s/%s/feed/?%s=%s", EnvironmentDevelopment.apiURL,
+		modelCampaign, fieldFeedType, FeedTypeAtom,
+	)
+
+	t.Run("parsed feed (success)", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		mockResponseFeed(endpoint, http.StatusOK, newTestCampaignFeedAtom())
+
+		var feed *Feed
+		var response *StandardResponse
+		feed, response, err = client.CampaignsFeedParsed(FeedTypeAtom)
+		require.NoError(t, err)
+		assert.NotNil(t, response)
+		require.Len(t, feed.Items, 1)
+		assert.Equal(t, "tonicpow", feed.Items[0].Slug)
+	})
+
+	t.Run("malformed feed", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		mockResponseFeed(endpoint, http.StatusOK, "not a feed")
+
+		var feed *Feed
+		feed, _, err = client.CampaignsFeedParsedWithContext(context.Background(), FeedTypeAtom)
+		assert.ErrorIs(t, err, ErrInvalidFeed)
+		assert.Nil(t, feed)
+	})
+
+	t.Run("error from api (status code)", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		mockResponseFeed(endpoint, http.StatusNotFound, "")
+
+		var feed *Feed
+		feed, _, err = client.CampaignsFeedParsed(FeedTypeAtom)
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Nil(t, feed)
+	})
+}
+
+// ExampleParseFeed example using ParseFeed()
+//
+// See more examples in /examples/
+func ExampleParseFeed() {
+	feed, err := ParseFeed(FeedTypeJSON, []byte(newTestCampaignFeedJSON()))
+	if err != nil {
+		fmt.Printf("error parsing feed: " + err.Error())
+		return
+	}
+	fmt.Printf("campaign: %s (%s)", feed.Items[0].Title, feed.Items[0].Slug)
+	// Output:campaign: TonicPow (tonicpow)
+}
+
+// BenchmarkParseFeed benchmarks the method ParseFeed()
+func BenchmarkParseFeed(b *testing.B) {
+	data := []byte(newTestCampaignFeedRSS())
+	for i := 0; i < b.N; i++ {
+		_, _ = ParseFeed(FeedTypeRSS, data)
+	}
+}
@@ -1,9 +1,11 @@
 package tonicpow
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 // permitFields will remove fields that cannot be used
@@ -12,9 +14,15 @@ func (g *Goal) permitFields() {
 }
 
 // CreateGoal will make a new goal
+// Use WithIdempotencyKey() to safely retry the request without creating duplicate goals
 //
 // For more information: https://docs.tonicpow.com/#29a93e9b-9726-474c-b25e-92586200a803
-func (c *Client) CreateGoal(goal *Goal) (*StandardResponse, error) {
+func (c *Client) CreateGoal(goal *Goal, opts ...RequestOps) (*StandardResponse, error) {
+	return c.CreateGoalWithContext(context.Background(), goal, opts...)
+}
+
+// CreateGoalWithContext is the same as CreateGoal but uses the given context
+func (c *Client) CreateGoalWithContext(ctx context.Context, goal *Goal, opts ...RequestOps) (*StandardResponse, error) {
 
 	// Basic requirements
 	if goal.CampaignID == 0 {
@@ -24,15 +32,20 @@ func (c *Client) CreateGoal(goal *Goal) (*StandardResponse, error) {
 	}
 
 	// Fire the Request
-	response, err := c.Request(
-		http.MethodPost,
+	response, err := c.RequestWithOptions(
+		ctx, http.MethodPost,
 		"/"+modelGoal,
-		goal, http.StatusCreated,
+		goal, http.StatusCreated, append([]RequestOps{
+			withOperation("CreateGoal"), withCallIDs(CallIDs{CampaignID: goal.CampaignID}),
+		}, opts...)...,
 	)
 	if err != nil {
 		return response, err
 	}
 
+	// Remove the cached campaign (includes the goals)
+	c.invalidateCampaign(goal.CampaignID, "")
+
 	return response, json.Unmarshal(response.Body, &goal)
 }
 
@@ -41,6 +54,12 @@ func (c *Client) CreateGoal(goal *Goal) (*StandardResponse, error) {
 //
 // For more information: https://docs.tonicpow.com/#48d7bbc8-5d7b-4078-87b7-25f545c3deaf
 func (c *Client) GetGoal(goalID uint64) (goal *Goal, response *StandardResponse, err error) {
+	return c.GetGoalWithContext(context.Background(), goalID)
+}
+
+// GetGoalWithContext is the same as GetGoal but uses the given context
+func (c *Client) GetGoalWithContext(ctx context.Context, goalID uint64) (goal *Goal,
+	response *StandardResponse, err error) {
 
 	// Must have an ID
 	if goalID == 0 {
@@ -49,10 +68,11 @@ func (c *Client) GetGoal(goalID uint64) (goal *Goal, response *StandardResponse,
 	}
 
 	// Fire the Request
-	if response, err = c.Request(
-		http.MethodGet,
-		fmt.Sprintf("/%s/details/%d", modelGoal, goalID),
-		nil, http.StatusOK,
+	if response, err = c.RequestWithOptions(
+		ctx, http.MethodGet,
+		goalEndpoint(goalID),
+		nil, http.StatusOK, withCacheEndpoint(CacheEndpointGoal),
+		withOperation("GetGoal"), withCallIDs(CallIDs{GoalID: goalID}),
 	); err != nil {
 		return
 	}
@@ -65,6 +85,11 @@ func (c *Client) GetGoal(goalID uint64) (goal *Goal, response *StandardResponse,
 //
 // For more information: https://docs.tonicpow.com/#395f5b7d-6a5d-49c8-b1ae-abf7f90b42a2
 func (c *Client) UpdateGoal(goal *Goal) (*StandardResponse, error) {
+	return c.UpdateGoalWithContext(context.Background(), goal)
+}
+
+// UpdateGoalWithContext is the same as UpdateGoal but uses the given context
+func (c *Client) UpdateGoalWithContext(ctx context.Context, goal *Goal) (*StandardResponse, error) {
 
 	// Basic requirements
 	if goal.ID == 0 {
@@ -75,22 +100,31 @@ func (c *Client) UpdateGoal(goal *Goal) (*StandardResponse, error) {
 	goal.permitFields()
 
 	// Fire the Request
-	response, err := c.Request(
-		http.MethodPut,
+	response, err := c.RequestWithOptions(
+		ctx, http.MethodPut,
 		"/"+modelGoal,
 		goal, http.StatusOK,
+		withOperation("UpdateGoal"), withCallIDs(CallIDs{GoalID: goal.ID}),
 	)
 	if err != nil {
 		return response, err
 	}
 
-	return response, json.Unmarshal(response.Body, &goal)
+	// Remove the cached goal & campaign
+	err = json.Unmarshal(response.Body, &goal)
+	c.invalidateGoal(goal.ID, goal.CampaignID)
+	return response, err
 }
 
 // DeleteGoal will delete an existing goal
 //
 // For more information: https://docs.tonicpow.com/#38605b65-72c9-4fc8-87a7-bc644bc89a96
 func (c *Client) DeleteGoal(goalID uint64) (bool, *StandardResponse, error) {
+	return c.DeleteGoalWithContext(context.Background(), goalID)
+}
+
+// DeleteGoalWithContext is the same as DeleteGoal but uses the given context
+func (c *Client) DeleteGoalWithContext(ctx context.Context, goalID uint64) (bool, *StandardResponse, error) {
 
 	// Basic requirements
 	if goalID == 0 {
@@ -98,15 +132,89 @@ func (c *Client) DeleteGoal(goalID uint64) (bool, *StandardResponse, error) {
 	}
 
 	// Fire the Request
-	response, err := c.Request(
-		http.MethodDelete,
+	response, err := c.RequestWithOptions(
+		ctx, http.MethodDelete,
 		fmt.Sprintf("/%s?%s=%d", modelGoal, fieldID, goalID),
 		nil, http.StatusOK,
+		withOperation("DeleteGoal"), withCallIDs(CallIDs{GoalID: goalID}),
 	)
 	if err != nil {
 		return false, response, err
 	}
 
+	// Remove the cached goal & campaign
+	c.invalidateGoal(goalID, 0)
+
 	// Flag for deleted if no error and good response
 	return true, response, err
 }
+
+// ListGoalsByCampaign will return a list of goals for the campaign
+// This will return an Error if no goals are found (404)
+func (c *Client) ListGoalsByCampaign(campaignID uint64, options *GoalListOptions) (goals *GoalResults,
+	response *StandardResponse, err error) {
+	return c.ListGoalsByCampaignWithContext(context.Background(), campaignID, options)
+}
+
+// ListGoalsByCampaignWithContext is the same as ListGoalsByCampaign but uses the given context
+func (c *Client) ListGoalsByCampaignWithContext(ctx context.Context, campaignID uint64,
+	options *GoalListOptions) (goals *GoalResults, response *StandardResponse, err error) {
+
+	// Must have an ID
+	if campaignID == 0 {
+		err = fmt.Errorf("missing required attribute: %s", fieldCampaignID)
+		return
+	}
+
+	// Validate the options
+	if options == nil {
+		options = new(GoalListOptions)
+	}
+	var query queryValues
+	if err = options.encode(&query); err != nil {
+		return
+	}
+
+	// Fire the Request
+	if response, err = c.RequestWithOptions(
+		ctx, http.MethodGet,
+		fmt.Sprintf("/%s/%s/%d?%s", modelCampaign, modelGoal, campaignID, query),
+		nil, http.StatusOK,
+		withOperation("ListGoalsByCampaign"), withCallIDs(CallIDs{CampaignID: campaignID}),
+	); err != nil {
+		return
+	}
+
+	err = json.Unmarshal(response.Body, &goals)
+	return
+}
+
+// FindGoalByName will return the goal of the campaign with the exact name (case-sensitive, the same as
+// the API lookup of WithGoalName()), use it to check the name before creating a conversion
+// This will return an error matching ErrNotFound if the campaign has no goal with the name
+func (c *Client) FindGoalByName(campaignID uint64, name string) (*Goal, error) {
+	return c.FindGoalByNameWithContext(context.Background(), campaignID, name)
+}
+
+// FindGoalByNameWithContext is the same as FindGoalByName but uses the given context
+func (c *Client) FindGoalByNameWithContext(ctx context.Context, campaignID uint64, name string) (*Goal, error) {
+
+	// Basic requirements
+	if campaignID == 0 {
+		return nil, fmt.Errorf("missing required attribute: %s", fieldCampaignID)
+	} else if len(strings.TrimSpace(name)) == 0 {
+		return nil, fmt.Errorf("missing required attribute: %s", fieldName)
+	}
+
+	// Search every page of goals
+	goals := c.IterateGoalsByCampaign(ctx, campaignID, "", "")
+	for goals.Next() {
+		if goals.Goal().Name == name { // Exact match (the API does not ignore case or spaces)
+			return goals.Goal(), nil
+		}
+	}
+	if err := goals.Err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("goal %s for campaign %d: %w", name, campaignID, ErrNotFound)
+}
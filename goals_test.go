@@ -1,11 +1,14 @@
 package tonicpow
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
 
+	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // newTestGoal will return a dummy example for tests
@@ -16,7 +19,7 @@ func newTestGoal() *Goal {
 		ID:             testGoalID,
 		MaxPerPromoter: 1,
 		Name:           testGoalName,
-		PayoutRate:     0.01,
+		PayoutRate:     NewDecimal(1, 2),
 		PayoutType:     "flat",
 		Title:          "Example Goal",
 	}
@@ -621,3 +624,327 @@ func BenchmarkClient_DeleteGoal(b *testing.B) {
 		_, _, _ = client.DeleteGoal(goal.ID)
 	}
 }
+
+// TestClient_CreateGoalWithContext will test the method CreateGoalWithContext()
+func TestClient_CreateGoalWithContext(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	endpoint := fmt.Sprintf("%s/%s", EnvironmentDevelopment.apiURL, modelGoal)
+
+	t.Run("valid context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		goal := newTestGoal()
+		err = mockResponseData(http.MethodPost, endpoint, http.StatusCreated, goal)
+		assert.NoError(t, err)
+
+		_, err = client.CreateGoalWithContext(context.Background(), goal)
+		assert.NoError(t, err)
+		assert.Equal(t, testGoalID, goal.ID)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		mockResponseBlocking(http.MethodPost, endpoint)
+
+		_, err = client.CreateGoalWithContext(newCanceledContext(), newTestGoal())
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+// TestClient_GetGoalWithContext will test the method GetGoalWithContext()
+func TestClient_GetGoalWithContext(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	endpoint := fmt.Sprintf("%s/%s/details/%d", EnvironmentDevelopment.apiURL, modelGoal, testGoalID)
+
+	t.Run("valid context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestGoal())
+		assert.NoError(t, err)
+
+		var goal *Goal
+		goal, _, err = client.GetGoalWithContext(context.Background(), testGoalID)
+		assert.NoError(t, err)
+		assert.NotNil(t, goal)
+		assert.Equal(t, testGoalID, goal.ID)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		mockResponseBlocking(http.MethodGet, endpoint)
+
+		var goal *Goal
+		goal, _, err = client.GetGoalWithContext(newCanceledContext(), testGoalID)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, goal)
+	})
+}
+
+// TestClient_UpdateGoalWithContext will test the method UpdateGoalWithContext()
+func TestClient_UpdateGoalWithContext(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	endpoint := fmt.Sprintf("%s/%s", EnvironmentDevelopment.apiURL, modelGoal)
+
+	t.Run("valid context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		goal := newTestGoal()
+		err = mockResponseData(http.MethodPut, endpoint, http.StatusOK, goal)
+		assert.NoError(t, err)
+
+		_, err = client.UpdateGoalWithContext(context.Background(), goal)
+		assert.NoError(t, err)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		mockResponseBlocking(http.MethodPut, endpoint)
+
+		_, err = client.UpdateGoalWithContext(newCanceledContext(), newTestGoal())
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+// TestClient_DeleteGoalWithContext will test the method DeleteGoalWithContext()
+func TestClient_DeleteGoalWithContext(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	endpoint := fmt.Sprintf("%s/%s?%s=%d", EnvironmentDevelopment.apiURL, modelGoal, fieldID, testGoalID)
+
+	t.Run("valid context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		err = mockResponseData(http.MethodDelete, endpoint, http.StatusOK, nil)
+		assert.NoError(t, err)
+
+		var deleted bool
+		deleted, _, err = client.DeleteGoalWithContext(context.Background(), testGoalID)
+		assert.NoError(t, err)
+		assert.True(t, deleted)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		mockResponseBlocking(http.MethodDelete, endpoint)
+
+		var deleted bool
+		deleted, _, err = client.DeleteGoalWithContext(newCanceledContext(), testGoalID)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.False(t, deleted)
+	})
+}
+
+// newTestGoalResults will return a page with the goals
+func newTestGoalResults(currentPage, resultsPerPage int, goals ...*Goal) *GoalResults {
+	return &GoalResults{
+		CurrentPage:    currentPage,
+		Goals:          goals,
+		Results:        len(goals),
+		ResultsPerPage: resultsPerPage,
+	}
+}
+
+// TestClient_ListGoalsByCampaign will test the method ListGoalsByCampaign()
+func TestClient_ListGoalsByCampaign(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	t.Run("list goals (success)", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		endpoint := fmt.Sprintf("%s/%s/%s/%d?current_page=2&results_per_page=10&sort_by=payouts&sort_order=desc",
+			EnvironmentDevelopment.apiURL, modelCampaign, modelGoal, testCampaignID)
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestGoalResults(2, 10, newTestGoal()))
+		require.NoError(t, err)
+
+		var goals *GoalResults
+		goals, _, err = client.ListGoalsByCampaign(testCampaignID, &GoalListOptions{ListOptions{
+			Page: 2, ResultsPerPage: 10, SortBy: SortByFieldPayouts, SortOrder: SortOrderDesc,
+		}})
+		require.NoError(t, err)
+		require.NotNil(t, goals)
+		require.Len(t, goals.Goals, 1)
+		assert.Equal(t, uint64(testGoalID), goals.Goals[0].ID)
+	})
+
+	t.Run("nil options (first page & default page size)", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		endpoint := fmt.Sprintf("%s/%s/%s/%d?current_page=1&results_per_page=25&sort_by=created_at&sort_order=desc",
+			EnvironmentDevelopment.apiURL, modelCampaign, modelGoal, testCampaignID)
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestGoalResults(1, 25, newTestGoal()))
+		require.NoError(t, err)
+
+		var goals *GoalResults
+		goals, _, err = client.ListGoalsByCampaign(testCampaignID, nil)
+		require.NoError(t, err)
+		require.NotNil(t, goals)
+		assert.Equal(t, 1, httpmock.GetTotalCallCount())
+	})
+
+	t.Run("missing campaign id", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		var goals *GoalResults
+		var response *StandardResponse
+		goals, response, err = client.ListGoalsByCampaign(0, nil)
+		assert.EqualError(t, err, "missing required attribute: campaign_id")
+		assert.Nil(t, goals)
+		assert.Nil(t, response)
+	})
+
+	t.Run("invalid sort field is not sent", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+		httpmock.Reset()
+
+		_, _, err = client.ListGoalsByCampaignWithContext(context.Background(), testCampaignID, &GoalListOptions{
+			ListOptions{SortBy: SortByFieldBalance},
+		})
+		assert.EqualError(t, err, "sort by balance is not valid")
+		assert.Equal(t, 0, httpmock.GetTotalCallCount())
+	})
+}
+
+// TestClient_FindGoalByName will test the method FindGoalByName()
+func TestClient_FindGoalByName(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	endpoint := fmt.Sprintf("%s/%s/%s/%d?current_page=1&results_per_page=25&sort_by=created_at&sort_order=desc",
+		EnvironmentDevelopment.apiURL, modelCampaign, modelGoal, testCampaignID)
+
+	t.Run("found (exact name)", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		other := newTestGoal()
+		other.ID, other.Name = testGoalID+1, "signup"
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestGoalResults(1, 25, other, newTestGoal()))
+		require.NoError(t, err)
+
+		var goal *Goal
+		goal, err = client.FindGoalByName(testCampaignID, testGoalName)
+		require.NoError(t, err)
+		require.NotNil(t, goal)
+		assert.Equal(t, uint64(testGoalID), goal.ID)
+	})
+
+	t.Run("name differs only in case or spaces", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestGoalResults(1, 25, newTestGoal()))
+		require.NoError(t, err)
+
+		for _, name := range []string{"Example_Goal", "EXAMPLE_GOAL", " example_goal "} {
+			var goal *Goal
+			goal, err = client.FindGoalByName(testCampaignID, name)
+			assert.ErrorIs(t, err, ErrNotFound, name)
+			assert.Nil(t, goal, name)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestGoalResults(1, 25, newTestGoal()))
+		require.NoError(t, err)
+
+		var goal *Goal
+		goal, err = client.FindGoalByName(testCampaignID, "refund")
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Nil(t, goal)
+	})
+
+	t.Run("campaign without goals", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		apiError := &Error{Code: 404, Message: "goals not found", RequestGUID: "7f3d97a8fd67ff57861904df6118dcc8"}
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusNotFound, apiError)
+		require.NoError(t, err)
+
+		_, err = client.FindGoalByName(testCampaignID, testGoalName)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("error from api (status code)", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		apiError := &Error{Code: 400, Message: "some error message", RequestGUID: "7f3d97a8fd67ff57861904df6118dcc8"}
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusBadRequest, apiError)
+		require.NoError(t, err)
+
+		_, err = client.FindGoalByName(testCampaignID, testGoalName)
+		assert.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("missing attributes", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		_, err = client.FindGoalByName(0, testGoalName)
+		assert.EqualError(t, err, "missing required attribute: campaign_id")
+
+		_, err = client.FindGoalByNameWithContext(context.Background(), testCampaignID, " ")
+		assert.EqualError(t, err, "missing required attribute: name")
+	})
+}
+
+// ExampleClient_FindGoalByName example using FindGoalByName()
+//
+// See more examples in /examples/
+func ExampleClient_FindGoalByName() {
+
+	// Load the client (using test client for example only)
+	client, err := newTestClient()
+	if err != nil {
+		fmt.Printf("error loading client: %s", err.Error())
+		return
+	}
+
+	// Mock response (for example only)
+	_ = mockResponseData(
+		http.MethodGet,
+		fmt.Sprintf("%s/%s/%s/%d?current_page=1&results_per_page=25&sort_by=created_at&sort_order=desc",
+			EnvironmentDevelopment.apiURL, modelCampaign, modelGoal, testCampaignID),
+		http.StatusOK,
+		newTestGoalResults(1, 25, newTestGoal()),
+	)
+
+	// Check the goal exists before creating the conversion (using mocking response)
+	var goal *Goal
+	if goal, err = client.FindGoalByName(testCampaignID, testGoalName); err != nil {
+		fmt.Printf("error finding goal: %s", err.Error())
+		return
+	}
+	fmt.Printf("goal: %s (%d)", goal.Name, goal.ID)
+	// Output:goal: example_goal (13)
+}
@@ -0,0 +1,157 @@
+package tonicpow
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// redactedValue replaces the values of sensitive headers passed to the hooks
+const redactedValue = "[REDACTED]"
+
+// Hooks are called for every attempt of a request (see WithHooks)
+//
+// Responses served from the cache without a request are not passed to the hooks.
+// Any of the hooks can be nil, hooks are called synchronously (keep them fast)
+type Hooks struct {
+	// BeforeRequest is called before the request is sent
+	BeforeRequest func(ctx context.Context, req *RequestInfo)
+
+	// AfterResponse is called when a response is received (any status code)
+	AfterResponse func(ctx context.Context, req *RequestInfo, resp *ResponseInfo)
+
+	// OnError is called when the attempt failed: no response (network error, canceled context, etc.)
+	// or an unexpected status code (the error is an *Error)
+	OnError func(ctx context.Context, req *RequestInfo, err error)
+}
+
+// RequestInfo is the request passed to the hooks
+type RequestInfo struct {
+	Attempt        int         // Attempt number (starts at 1)
+	Endpoint       string      // Endpoint of the request (/campaigns/details/?id=1)
+	Header         http.Header // Request headers (the api key is redacted)
+	IdempotencyKey string      // Idempotency key (if any)
+	Method         string      // HTTP method
+	URL            string      // Full URL of the request
+}
+
+// ResponseInfo is the response passed to the hooks
+type ResponseInfo struct {
+	Duration    time.Duration // Duration of the attempt
+	Header      http.Header   // Response headers
+	RequestGUID string        // Request GUID of the API error (if any)
+	StatusCode  int           // HTTP status code
+}
+
+// WithHooks will add hooks that are called for every request attempt (can be used multiple times)
+func WithHooks(hooks *Hooks) ClientOps {
+	return func(c *ClientOptions) {
+		if hooks != nil {
+			c.hooks = append(c.hooks, hooks)
+		}
+	}
+}
+
+// WithLogger will log every request attempt using the structured logger (see NewLogHooks)
+func WithLogger(logger *slog.Logger) ClientOps {
+	return WithHooks(NewLogHooks(logger))
+}
+
+// NewLogHooks will return hooks that log every request attempt using the structured logger
+//
+// Responses are logged at info level (warn for status codes >= 400) and errors without
+// a response at error level, with the method, endpoint, status, duration and request_guid.
+// The api key is never logged
+func NewLogHooks(logger *slog.Logger) *Hooks {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Hooks{
+		AfterResponse: func(ctx context.Context, req *RequestInfo, resp *ResponseInfo) {
+			level := slog.LevelInfo
+			if resp.StatusCode >= http.StatusBadRequest {
+				level = slog.LevelWarn
+			}
+			attrs := []slog.Attr{
+				slog.String("method", req.Method),
+				slog.String("endpoint", req.Endpoint),
+				slog.Int("status", resp.StatusCode),
+				slog.Duration("duration", resp.Duration),
+				slog.Int("attempt", req.Attempt),
+			}
+			if len(resp.RequestGUID) > 0 {
+				attrs = append(attrs, slog.String("request_guid", resp.RequestGUID))
+			}
+			logger.LogAttrs(ctx, level, "tonicpow request", attrs...)
+		},
+		OnError: func(ctx context.Context, req *RequestInfo, err error) {
+			var apiError *Error
+			if errors.As(err, &apiError) { // Already logged with the response
+				return
+			}
+			logger.LogAttrs(ctx, slog.LevelError, "tonicpow request failed",
+				slog.String("method", req.Method),
+				slog.String("endpoint", req.Endpoint),
+				slog.Int("attempt", req.Attempt),
+				slog.String("error", err.Error()),
+			)
+		},
+	}
+}
+
+// newRequestInfo will return the request info for the hooks (redacting the api key & authorization)
+func newRequestInfo(attempt int, method, endpoint, url string, header http.Header,
+	options *requestOptions) *RequestInfo {
+	header = header.Clone()
+	for key := range header {
+		if canonical := http.CanonicalHeaderKey(key); canonical == http.CanonicalHeaderKey(fieldAPIKey) ||
+			canonical == "Authorization" {
+			header[key] = []string{redactedValue}
+		}
+	}
+	return &RequestInfo{
+		Attempt:        attempt,
+		Endpoint:       endpoint,
+		Header:         header,
+		IdempotencyKey: options.idempotencyKey,
+		Method:         method,
+		URL:            url,
+	}
+}
+
+// beforeRequest will call the BeforeRequest hooks
+func (c *Client) beforeRequest(ctx context.Context, req *RequestInfo) {
+	for _, hooks := range c.options.hooks {
+		if hooks.BeforeRequest != nil {
+			hooks.BeforeRequest(ctx, req)
+		}
+	}
+}
+
+// afterAttempt will call the AfterResponse and OnError hooks for the attempt
+func (c *Client) afterAttempt(ctx context.Context, req *RequestInfo, statusCode int, header http.Header,
+	body []byte, duration time.Duration, expectedCode int, err error) {
+
+	if err == nil {
+		resp := &ResponseInfo{Duration: duration, Header: header, StatusCode: statusCode}
+		if statusCode >= http.StatusBadRequest {
+			resp.RequestGUID = newError(statusCode, body).RequestGUID
+		}
+		for _, hooks := range c.options.hooks {
+			if hooks.AfterResponse != nil {
+				hooks.AfterResponse(ctx, req, resp)
+			}
+		}
+		if expectedCode <= 0 || statusCode == expectedCode || statusCode == http.StatusNotModified {
+			return
+		}
+		err = newError(statusCode, body)
+	}
+	for _, hooks := range c.options.hooks {
+		if hooks.OnError != nil {
+			hooks.OnError(ctx, req, err)
+		}
+	}
+}
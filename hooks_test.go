@@ -0,0 +1,151 @@
+package tonicpow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClient_Hooks will test the request hooks
+func TestClient_Hooks(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	campaignURL := EnvironmentDevelopment.apiURL + campaignEndpoint(testCampaignID)
+
+	t.Run("hooks are called for every attempt", func(t *testing.T) {
+		var requests []*RequestInfo
+		var responses []*ResponseInfo
+		var errs []error
+		client, err := newRetryTestClient(
+			WithRetryPolicy(newTestRetryPolicy(1)),
+			WithCustomHeaders(map[string][]string{"Authorization": {"Bearer secret"}}),
+			WithHooks(&Hooks{
+				BeforeRequest: func(_ context.Context, req *RequestInfo) { requests = append(requests, req) },
+				AfterResponse: func(_ context.Context, _ *RequestInfo, resp *ResponseInfo) {
+					responses = append(responses, resp)
+				},
+				OnError: func(_ context.Context, _ *RequestInfo, err error) { errs = append(errs, err) },
+			}),
+			WithHooks(nil),
+		)
+		require.NoError(t, err)
+
+		mockResponseSequence(http.MethodGet, campaignURL, nil, http.StatusBadGateway, http.StatusOK)
+		_, _, err = client.GetCampaign(testCampaignID)
+		require.NoError(t, err)
+
+		require.Len(t, requests, 2)
+		assert.Equal(t, 1, requests[0].Attempt)
+		assert.Equal(t, 2, requests[1].Attempt)
+		assert.Equal(t, http.MethodGet, requests[0].Method)
+		assert.Equal(t, campaignEndpoint(testCampaignID), requests[0].Endpoint)
+		assert.Equal(t, campaignURL, requests[0].URL)
+		assert.Equal(t, redactedValue, requests[0].Header.Get(fieldAPIKey))
+		assert.Equal(t, redactedValue, requests[0].Header.Get("Authorization"))
+
+		require.Len(t, responses, 2)
+		assert.Equal(t, http.StatusBadGateway, responses[0].StatusCode)
+		assert.Equal(t, http.StatusOK, responses[1].StatusCode)
+
+		require.Len(t, errs, 1)
+		assert.Equal(t, http.StatusBadGateway, errs[0].(*Error).StatusCode)
+	})
+
+	t.Run("errors without a response", func(t *testing.T) {
+		var errs []error
+		client, err := newRetryTestClient(WithRetryCount(0), WithHooks(&Hooks{
+			OnError: func(_ context.Context, _ *RequestInfo, err error) { errs = append(errs, err) },
+		}))
+		require.NoError(t, err)
+
+		mockResponseBlocking(http.MethodGet, campaignURL)
+		_, _, err = client.GetCampaignWithContext(newCanceledContext(), testCampaignID)
+		require.Error(t, err)
+		require.Len(t, errs, 1)
+		assert.True(t, errors.Is(errs[0], context.Canceled))
+	})
+}
+
+// TestNewLogHooks will test the structured logging of requests
+func TestNewLogHooks(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	campaignURL := EnvironmentDevelopment.apiURL + campaignEndpoint(testCampaignID)
+
+	// logLines will return the JSON log lines
+	logLines := func(t *testing.T, buf *bytes.Buffer) (lines []map[string]interface{}) {
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			entry := make(map[string]interface{})
+			require.NoError(t, json.Unmarshal([]byte(line), &entry))
+			lines = append(lines, entry)
+		}
+		return
+	}
+
+	t.Run("successful request", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		client, err := newRetryTestClient(WithLogger(slog.New(slog.NewJSONHandler(buf, nil))))
+		require.NoError(t, err)
+
+		err = mockResponseData(http.MethodGet, campaignURL, http.StatusOK, newTestCampaign())
+		require.NoError(t, err)
+		_, _, err = client.GetCampaign(testCampaignID)
+		require.NoError(t, err)
+
+		lines := logLines(t, buf)
+		require.Len(t, lines, 1)
+		assert.Equal(t, "INFO", lines[0]["level"])
+		assert.Equal(t, "tonicpow request", lines[0]["msg"])
+		assert.Equal(t, http.MethodGet, lines[0]["method"])
+		assert.Equal(t, campaignEndpoint(testCampaignID), lines[0]["endpoint"])
+		assert.Equal(t, float64(http.StatusOK), lines[0]["status"])
+		assert.Contains(t, lines[0], "duration")
+		assert.NotContains(t, buf.String(), testAPIKey)
+	})
+
+	t.Run("api error with request guid", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		client, err := newRetryTestClient(WithRetryCount(0), WithLogger(slog.New(slog.NewJSONHandler(buf, nil))))
+		require.NoError(t, err)
+
+		httpmock.Reset()
+		httpmock.RegisterResponder(http.MethodGet, campaignURL, httpmock.NewStringResponder(
+			http.StatusNotFound, `{"code":404,"message":"campaign not found","request_guid":"guid-123"}`,
+		))
+		_, _, err = client.GetCampaign(testCampaignID)
+		require.ErrorIs(t, err, ErrNotFound)
+
+		lines := logLines(t, buf)
+		require.Len(t, lines, 1)
+		assert.Equal(t, "WARN", lines[0]["level"])
+		assert.Equal(t, "guid-123", lines[0]["request_guid"])
+	})
+
+	t.Run("error without a response", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		client, err := newRetryTestClient(WithRetryCount(0), WithLogger(slog.New(slog.NewJSONHandler(buf, nil))))
+		require.NoError(t, err)
+
+		mockResponseBlocking(http.MethodGet, campaignURL)
+		_, _, err = client.GetCampaignWithContext(newCanceledContext(), testCampaignID)
+		require.Error(t, err)
+
+		lines := logLines(t, buf)
+		require.Len(t, lines, 1)
+		assert.Equal(t, "ERROR", lines[0]["level"])
+		assert.Equal(t, "tonicpow request failed", lines[0]["msg"])
+	})
+
+	t.Run("default logger", func(t *testing.T) {
+		assert.NotNil(t, NewLogHooks(nil))
+	})
+}
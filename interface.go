@@ -1,49 +1,134 @@
 package tonicpow
 
-import "github.com/go-resty/resty/v2"
+import (
+	"context"
+
+	"github.com/go-resty/resty/v2"
+)
 
 // AdvertiserService is the advertiser requests
 type AdvertiserService interface {
+	CreateAdvertiserProfile(profile *AdvertiserProfile, opts ...RequestOps) (*StandardResponse, error)
+	CreateAdvertiserProfileWithContext(ctx context.Context, profile *AdvertiserProfile, opts ...RequestOps) (*StandardResponse, error)
 	GetAdvertiserProfile(profileID uint64) (profile *AdvertiserProfile, response *StandardResponse, err error)
+	GetAdvertiserProfileWithContext(ctx context.Context, profileID uint64) (profile *AdvertiserProfile, response *StandardResponse, err error)
+	GetAdvertiserProfileDetails(profileID uint64) (*AdvertiserProfileDetails, error)
+	GetAdvertiserProfileDetailsWithContext(ctx context.Context, profileID uint64) (*AdvertiserProfileDetails, error)
+	IterateAdvertiserProfiles(ctx context.Context, sortBy SortField, sortOrder SortOrder, opts ...IteratorOps) *AdvertiserIterator
+	IterateAppsByAdvertiserProfile(ctx context.Context, profileID uint64, sortBy, sortOrder string, opts ...IteratorOps) *AppIterator
+	IterateCampaignsByAdvertiserProfile(ctx context.Context, profileID uint64, sortBy, sortOrder string, opts ...IteratorOps) *CampaignIterator
+	ListAdvertiserProfiles(options *AdvertiserListOptions) (profiles *AdvertiserResults, response *StandardResponse, err error)
+	ListAdvertiserProfilesWithContext(ctx context.Context, options *AdvertiserListOptions) (profiles *AdvertiserResults, response *StandardResponse, err error)
 	ListAppsByAdvertiserProfile(profileID uint64, page, resultsPerPage int, sortBy, sortOrder string) (apps *AppResults, response *StandardResponse, err error)
+	ListAppsByAdvertiserProfileWithContext(ctx context.Context, profileID uint64, page, resultsPerPage int, sortBy, sortOrder string) (apps *AppResults, response *StandardResponse, err error)
 	ListCampaignsByAdvertiserProfile(profileID uint64, page, resultsPerPage int, sortBy, sortOrder string) (campaigns *CampaignResults, response *StandardResponse, err error)
+	ListCampaignsByAdvertiserProfileWithContext(ctx context.Context, profileID uint64, page, resultsPerPage int, sortBy, sortOrder string) (campaigns *CampaignResults, response *StandardResponse, err error)
+	PatchAdvertiserProfile(profileID uint64, changes ChangeSet) (profile *AdvertiserProfile, response *StandardResponse, err error)
+	PatchAdvertiserProfileWithContext(ctx context.Context, profileID uint64, changes ChangeSet) (profile *AdvertiserProfile, response *StandardResponse, err error)
+	QueryAppsByAdvertiserProfile(profileID uint64, options *AppListOptions) (apps *AppResults, response *StandardResponse, err error)
+	QueryAppsByAdvertiserProfileWithContext(ctx context.Context, profileID uint64, options *AppListOptions) (apps *AppResults, response *StandardResponse, err error)
+	QueryCampaignsByAdvertiserProfile(profileID uint64, options *ListOptions) (campaigns *CampaignResults, response *StandardResponse, err error)
+	QueryCampaignsByAdvertiserProfileWithContext(ctx context.Context, profileID uint64, options *ListOptions) (campaigns *CampaignResults, response *StandardResponse, err error)
 	UpdateAdvertiserProfile(profile *AdvertiserProfile) (*StandardResponse, error)
+	UpdateAdvertiserProfileWithContext(ctx context.Context, profile *AdvertiserProfile) (*StandardResponse, error)
+}
+
+// AppService is the app requests
+type AppService interface {
+	CreateApp(app *App, opts ...RequestOps) (*StandardResponse, error)
+	CreateAppWithContext(ctx context.Context, app *App, opts ...RequestOps) (*StandardResponse, error)
+	DeleteApp(appID uint64) (bool, *StandardResponse, error)
+	DeleteAppWithContext(ctx context.Context, appID uint64) (bool, *StandardResponse, error)
+	GetApp(appID uint64) (app *App, response *StandardResponse, err error)
+	GetAppWithContext(ctx context.Context, appID uint64) (app *App, response *StandardResponse, err error)
+	PatchApp(appID uint64, changes ChangeSet) (app *App, response *StandardResponse, err error)
+	PatchAppWithContext(ctx context.Context, appID uint64, changes ChangeSet) (app *App, response *StandardResponse, err error)
+	SendTestWebhook(appID uint64, eventType WebhookEventType) (result *WebhookTestResult, response *StandardResponse, err error)
+	SendTestWebhookWithContext(ctx context.Context, appID uint64, eventType WebhookEventType) (result *WebhookTestResult, response *StandardResponse, err error)
+	UpdateApp(app *App) (*StandardResponse, error)
+	UpdateAppWithContext(ctx context.Context, app *App) (*StandardResponse, error)
+	UpdateAppWebhookURL(appID uint64, webhookURL string) (app *App, response *StandardResponse, err error)
+	UpdateAppWebhookURLWithContext(ctx context.Context, appID uint64, webhookURL string) (app *App, response *StandardResponse, err error)
 }
 
 // CampaignService is the campaign requests
 type CampaignService interface {
 	CampaignsFeed(feedType FeedType) (feed string, response *StandardResponse, err error)
-	CreateCampaign(campaign *Campaign) (*StandardResponse, error)
+	CampaignsFeedWithContext(ctx context.Context, feedType FeedType) (feed string, response *StandardResponse, err error)
+	CampaignsFeedParsed(feedType FeedType) (feed *Feed, response *StandardResponse, err error)
+	CampaignsFeedParsedWithContext(ctx context.Context, feedType FeedType) (feed *Feed, response *StandardResponse, err error)
+	CreateCampaign(campaign *Campaign, opts ...RequestOps) (*StandardResponse, error)
+	CreateCampaignWithContext(ctx context.Context, campaign *Campaign, opts ...RequestOps) (*StandardResponse, error)
 	GetCampaign(campaignID uint64) (campaign *Campaign, response *StandardResponse, err error)
+	GetCampaignWithContext(ctx context.Context, campaignID uint64) (campaign *Campaign, response *StandardResponse, err error)
 	GetCampaignBySlug(slug string) (campaign *Campaign, response *StandardResponse, err error)
+	GetCampaignBySlugWithContext(ctx context.Context, slug string) (campaign *Campaign, response *StandardResponse, err error)
+	IterateCampaigns(ctx context.Context, sortBy, sortOrder, searchQuery string, minimumBalance uint64, includeExpired bool, opts ...IteratorOps) *CampaignIterator
+	IterateCampaignsByURL(ctx context.Context, targetURL, sortBy, sortOrder string, opts ...IteratorOps) *CampaignIterator
 	ListCampaigns(page, resultsPerPage int, sortBy, sortOrder, searchQuery string, minimumBalance uint64, includeExpired bool) (results *CampaignResults, response *StandardResponse, err error)
+	ListCampaignsWithContext(ctx context.Context, page, resultsPerPage int, sortBy, sortOrder, searchQuery string, minimumBalance uint64, includeExpired bool) (results *CampaignResults, response *StandardResponse, err error)
 	ListCampaignsByURL(targetURL string, page, resultsPerPage int, sortBy, sortOrder string) (results *CampaignResults, response *StandardResponse, err error)
+	ListCampaignsByURLWithContext(ctx context.Context, targetURL string, page, resultsPerPage int, sortBy, sortOrder string) (results *CampaignResults, response *StandardResponse, err error)
+	PatchCampaign(campaignID uint64, changes ChangeSet) (campaign *Campaign, response *StandardResponse, err error)
+	PatchCampaignWithContext(ctx context.Context, campaignID uint64, changes ChangeSet) (campaign *Campaign, response *StandardResponse, err error)
+	QueryCampaigns(options *CampaignListOptions) (results *CampaignResults, response *StandardResponse, err error)
+	QueryCampaignsWithContext(ctx context.Context, options *CampaignListOptions) (results *CampaignResults, response *StandardResponse, err error)
 	UpdateCampaign(campaign *Campaign) (response *StandardResponse, err error)
+	UpdateCampaignWithContext(ctx context.Context, campaign *Campaign) (response *StandardResponse, err error)
 }
 
 // ConversionService is the conversion requests
 type ConversionService interface {
 	CancelConversion(conversionID uint64, cancelReason string) (conversion *Conversion, response *StandardResponse, err error)
+	CancelConversionWithContext(ctx context.Context, conversionID uint64, cancelReason string) (conversion *Conversion, response *StandardResponse, err error)
 	CreateConversion(opts ...ConversionOps) (conversion *Conversion, response *StandardResponse, err error)
+	CreateConversionWithContext(ctx context.Context, opts ...ConversionOps) (conversion *Conversion, response *StandardResponse, err error)
+	CreateConversions(batch [][]ConversionOps, opts ...BatchOps) ([]*ConversionResult, error)
+	CreateConversionsWithContext(ctx context.Context, batch [][]ConversionOps, opts ...BatchOps) ([]*ConversionResult, error)
 	GetConversion(conversionID uint64) (conversion *Conversion, response *StandardResponse, err error)
+	GetConversionWithContext(ctx context.Context, conversionID uint64) (conversion *Conversion, response *StandardResponse, err error)
+	IterateConversionsByCampaign(ctx context.Context, campaignID uint64, options *ConversionListOptions, opts ...IteratorOps) *ConversionIterator
+	IterateConversionsByGoal(ctx context.Context, goalID uint64, options *ConversionListOptions, opts ...IteratorOps) *ConversionIterator
+	IterateConversionsByUser(ctx context.Context, userID uint64, options *ConversionListOptions, opts ...IteratorOps) *ConversionIterator
+	ListConversionsByCampaign(campaignID uint64, options *ConversionListOptions) (results *ConversionResults, response *StandardResponse, err error)
+	ListConversionsByCampaignWithContext(ctx context.Context, campaignID uint64, options *ConversionListOptions) (results *ConversionResults, response *StandardResponse, err error)
+	ListConversionsByGoal(goalID uint64, options *ConversionListOptions) (results *ConversionResults, response *StandardResponse, err error)
+	ListConversionsByGoalWithContext(ctx context.Context, goalID uint64, options *ConversionListOptions) (results *ConversionResults, response *StandardResponse, err error)
+	ListConversionsByUser(userID uint64, options *ConversionListOptions) (results *ConversionResults, response *StandardResponse, err error)
+	ListConversionsByUserWithContext(ctx context.Context, userID uint64, options *ConversionListOptions) (results *ConversionResults, response *StandardResponse, err error)
 }
 
 // GoalService is the goal requests
 type GoalService interface {
-	CreateGoal(goal *Goal) (*StandardResponse, error)
+	CreateGoal(goal *Goal, opts ...RequestOps) (*StandardResponse, error)
+	CreateGoalWithContext(ctx context.Context, goal *Goal, opts ...RequestOps) (*StandardResponse, error)
 	DeleteGoal(goalID uint64) (bool, *StandardResponse, error)
+	DeleteGoalWithContext(ctx context.Context, goalID uint64) (bool, *StandardResponse, error)
+	FindGoalByName(campaignID uint64, name string) (*Goal, error)
+	FindGoalByNameWithContext(ctx context.Context, campaignID uint64, name string) (*Goal, error)
 	GetGoal(goalID uint64) (goal *Goal, response *StandardResponse, err error)
+	GetGoalWithContext(ctx context.Context, goalID uint64) (goal *Goal, response *StandardResponse, err error)
+	IterateGoalsByCampaign(ctx context.Context, campaignID uint64, sortBy SortField, sortOrder SortOrder, opts ...IteratorOps) *GoalIterator
+	ListGoalsByCampaign(campaignID uint64, options *GoalListOptions) (goals *GoalResults, response *StandardResponse, err error)
+	ListGoalsByCampaignWithContext(ctx context.Context, campaignID uint64, options *GoalListOptions) (goals *GoalResults, response *StandardResponse, err error)
+	PatchGoal(goalID uint64, changes ChangeSet) (goal *Goal, response *StandardResponse, err error)
+	PatchGoalWithContext(ctx context.Context, goalID uint64, changes ChangeSet) (goal *Goal, response *StandardResponse, err error)
 	UpdateGoal(goal *Goal) (*StandardResponse, error)
+	UpdateGoalWithContext(ctx context.Context, goal *Goal) (*StandardResponse, error)
 }
 
 // RateService is the rate requests
 type RateService interface {
 	GetCurrentRate(currency string, customAmount float64) (rate *Rate, response *StandardResponse, err error)
+	GetCurrentRateDecimal(currency Currency, customAmount Decimal) (rate *Rate, response *StandardResponse, err error)
+	GetCurrentRateDecimalWithContext(ctx context.Context, currency Currency, customAmount Decimal) (rate *Rate, response *StandardResponse, err error)
+	GetCurrentRateWithContext(ctx context.Context, currency string, customAmount float64) (rate *Rate, response *StandardResponse, err error)
 }
 
 // ClientInterface is the Tonicpow client interface
 type ClientInterface interface {
 	AdvertiserService
+	AppService
 	CampaignService
 	ConversionService
 	GoalService
@@ -52,5 +137,6 @@ type ClientInterface interface {
 	GetUserAgent() string
 	Options() *ClientOptions
 	Request(httpMethod string, requestEndpoint string, data interface{}, expectedCode int) (response *StandardResponse, err error)
+	RequestWithContext(ctx context.Context, httpMethod string, requestEndpoint string, data interface{}, expectedCode int) (response *StandardResponse, err error)
 	WithCustomHTTPClient(client *resty.Client) *Client
 }
@@ -0,0 +1,368 @@
+package tonicpow
+
+import (
+	"context"
+	"errors"
+)
+
+// defaultIteratorPageSize is the default results per page when iterating
+const defaultIteratorPageSize = defaultResultsPerPage
+
+// IteratorOps allow functional options to be supplied
+// that overwrite default iterator options.
+type IteratorOps func(o *iteratorOptions)
+
+// iteratorOptions holds all the configuration for iterating list results
+type iteratorOptions struct {
+	pageSize int  // Results per page requested from the API
+	prefetch bool // Fetch the next page in the background while the current page is consumed
+}
+
+// WithPageSize will overwrite the results per page used when fetching pages (the API may cap it).
+// Default is 25.
+func WithPageSize(resultsPerPage int) IteratorOps {
+	return func(o *iteratorOptions) {
+		if resultsPerPage > 0 {
+			o.pageSize = resultsPerPage
+		}
+	}
+}
+
+// WithPrefetch will fetch the next page concurrently while the current page is being consumed.
+// Prefetching is disabled by default.
+func WithPrefetch() IteratorOps {
+	return func(o *iteratorOptions) {
+		o.prefetch = true
+	}
+}
+
+// pageFetcher will fetch a single page, returning the results and the results per page
+// reported by the API (0 if not reported)
+type pageFetcher[T any] func(ctx context.Context, page, resultsPerPage int) (items []T, pageSize int, err error)
+
+// pageResult is the result of fetching a single page
+type pageResult[T any] struct {
+	err      error
+	items    []T
+	pageSize int
+}
+
+// pager will lazily fetch pages and iterate over the results
+//
+// Iteration stops when a page returns fewer results than the results per page reported by the API
+// (the API may cap the requested page size), on an empty page, when the API returns a 404
+// (no more results) or on the first error
+type pager[T any] struct {
+	ctx      context.Context
+	current  T
+	done     bool
+	err      error
+	fetch    pageFetcher[T]
+	index    int
+	items    []T
+	options  *iteratorOptions
+	page     int
+	prefetch chan pageResult[T]
+}
+
+// newPager will create a new pager starting on the first page
+func newPager[T any](ctx context.Context, fetch pageFetcher[T], opts ...IteratorOps) *pager[T] {
+	options := &iteratorOptions{pageSize: defaultIteratorPageSize}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return &pager[T]{
+		ctx:     ctx,
+		fetch:   fetch,
+		options: options,
+		page:    1,
+	}
+}
+
+// next will advance to the next result, fetching the next page if needed
+func (p *pager[T]) next() bool {
+	for p.err == nil && p.index >= len(p.items) {
+		if p.done {
+			return false
+		}
+		p.items, p.err = p.nextPage()
+		p.index = 0
+	}
+	if p.err != nil {
+		return false
+	}
+	p.current = p.items[p.index]
+	p.index++
+	return true
+}
+
+// nextPage will return the next page of results (waiting for the prefetched page if available)
+func (p *pager[T]) nextPage() ([]T, error) {
+	var result pageResult[T]
+	if p.prefetch != nil {
+		result = <-p.prefetch
+		p.prefetch = nil
+	} else {
+		result = p.load(p.page)
+	}
+	p.page++
+
+	// No more results
+	if errors.Is(result.err, ErrNotFound) {
+		p.done = true
+		return nil, nil
+	} else if result.err != nil {
+		return nil, result.err
+	} else if len(result.items) == 0 || (result.pageSize > 0 && len(result.items) < result.pageSize) {
+		p.done = true
+		return result.items, nil
+	}
+
+	// Start loading the next page in the background
+	if p.options.prefetch {
+		p.prefetch = make(chan pageResult[T], 1)
+		go func(ch chan<- pageResult[T], page int) {
+			ch <- p.load(page)
+		}(p.prefetch, p.page)
+	}
+	return result.items, nil
+}
+
+// load will fetch a single page
+func (p *pager[T]) load(page int) pageResult[T] {
+	items, pageSize, err := p.fetch(p.ctx, page, p.options.pageSize)
+	return pageResult[T]{err: err, items: items, pageSize: pageSize}
+}
+
+// CampaignIterator will iterate over all the campaigns of a list request, fetching pages as needed
+//
+//	for iter.Next() {
+//	    campaign := iter.Campaign()
+//	}
+//	if err := iter.Err(); err != nil {
+//	    // handle error
+//	}
+type CampaignIterator struct {
+	pager *pager[*Campaign]
+}
+
+// Next will advance the iterator, returns false when there are no more campaigns or an error occurred
+func (i *CampaignIterator) Next() bool {
+	return i.pager.next()
+}
+
+// Campaign will return the current campaign
+func (i *CampaignIterator) Campaign() *Campaign {
+	return i.pager.current
+}
+
+// Err will return the first error that occurred while iterating
+func (i *CampaignIterator) Err() error {
+	return i.pager.err
+}
+
+// AppIterator will iterate over all the apps of a list request, fetching pages as needed
+type AppIterator struct {
+	pager *pager[*App]
+}
+
+// Next will advance the iterator, returns false when there are no more apps or an error occurred
+func (i *AppIterator) Next() bool {
+	return i.pager.next()
+}
+
+// App will return the current app
+func (i *AppIterator) App() *App {
+	return i.pager.current
+}
+
+// Err will return the first error that occurred while iterating
+func (i *AppIterator) Err() error {
+	return i.pager.err
+}
+
+// AdvertiserIterator will iterate over all the advertiser profiles of a list request, fetching pages as needed
+type AdvertiserIterator struct {
+	pager *pager[*AdvertiserProfile]
+}
+
+// Next will advance the iterator, returns false when there are no more profiles or an error occurred
+func (i *AdvertiserIterator) Next() bool {
+	return i.pager.next()
+}
+
+// AdvertiserProfile will return the current advertiser profile
+func (i *AdvertiserIterator) AdvertiserProfile() *AdvertiserProfile {
+	return i.pager.current
+}
+
+// Err will return the first error that occurred while iterating
+func (i *AdvertiserIterator) Err() error {
+	return i.pager.err
+}
+
+// ConversionIterator will iterate over all the conversions of a list request, fetching pages as needed
+type ConversionIterator struct {
+	pager *pager[*Conversion]
+}
+
+// Next will advance the iterator, returns false when there are no more conversions or an error occurred
+func (i *ConversionIterator) Next() bool {
+	return i.pager.next()
+}
+
+// Conversion will return the current conversion
+func (i *ConversionIterator) Conversion() *Conversion {
+	return i.pager.current
+}
+
+// Err will return the first error that occurred while iterating
+func (i *ConversionIterator) Err() error {
+	return i.pager.err
+}
+
+// GoalIterator will iterate over all the goals of a list request, fetching pages as needed
+type GoalIterator struct {
+	pager *pager[*Goal]
+}
+
+// Next will advance the iterator, returns false when there are no more goals or an error occurred
+func (i *GoalIterator) Next() bool {
+	return i.pager.next()
+}
+
+// Goal will return the current goal
+func (i *GoalIterator) Goal() *Goal {
+	return i.pager.current
+}
+
+// Err will return the first error that occurred while iterating
+func (i *GoalIterator) Err() error {
+	return i.pager.err
+}
+
+// campaignPage will return the campaigns and the results per page from a page of results
+func campaignPage(results *CampaignResults, err error) ([]*Campaign, int, error) {
+	if err != nil || results == nil {
+		return nil, 0, err
+	}
+	return results.Campaigns, results.ResultsPerPage, nil
+}
+
+// IterateCampaigns will return an iterator over all campaigns (see ListCampaigns)
+func (c *Client) IterateCampaigns(ctx context.Context, sortBy, sortOrder, searchQuery string,
+	minimumBalance uint64, includeExpired bool, opts ...IteratorOps) *CampaignIterator {
+	return &CampaignIterator{pager: newPager(ctx, func(ctx context.Context, page, resultsPerPage int) ([]*Campaign, int, error) {
+		results, _, err := c.ListCampaignsWithContext(
+			ctx, page, resultsPerPage, sortBy, sortOrder, searchQuery, minimumBalance, includeExpired,
+		)
+		return campaignPage(results, err)
+	}, opts...)}
+}
+
+// IterateCampaignsByURL will return an iterator over all campaigns for the target url (see ListCampaignsByURL)
+func (c *Client) IterateCampaignsByURL(ctx context.Context, targetURL, sortBy, sortOrder string,
+	opts ...IteratorOps) *CampaignIterator {
+	return &CampaignIterator{pager: newPager(ctx, func(ctx context.Context, page, resultsPerPage int) ([]*Campaign, int, error) {
+		results, _, err := c.ListCampaignsByURLWithContext(ctx, targetURL, page, resultsPerPage, sortBy, sortOrder)
+		return campaignPage(results, err)
+	}, opts...)}
+}
+
+// IterateCampaignsByAdvertiserProfile will return an iterator over all campaigns
+// for the advertiser profile (see ListCampaignsByAdvertiserProfile)
+func (c *Client) IterateCampaignsByAdvertiserProfile(ctx context.Context, profileID uint64,
+	sortBy, sortOrder string, opts ...IteratorOps) *CampaignIterator {
+	return &CampaignIterator{pager: newPager(ctx, func(ctx context.Context, page, resultsPerPage int) ([]*Campaign, int, error) {
+		results, _, err := c.ListCampaignsByAdvertiserProfileWithContext(
+			ctx, profileID, page, resultsPerPage, sortBy, sortOrder,
+		)
+		return campaignPage(results, err)
+	}, opts...)}
+}
+
+// IterateAppsByAdvertiserProfile will return an iterator over all apps
+// for the advertiser profile (see ListAppsByAdvertiserProfile)
+func (c *Client) IterateAppsByAdvertiserProfile(ctx context.Context, profileID uint64,
+	sortBy, sortOrder string, opts ...IteratorOps) *AppIterator {
+	return &AppIterator{pager: newPager(ctx, func(ctx context.Context, page, resultsPerPage int) ([]*App, int, error) {
+		results, _, err := c.ListAppsByAdvertiserProfileWithContext(
+			ctx, profileID, page, resultsPerPage, sortBy, sortOrder,
+		)
+		if err != nil || results == nil {
+			return nil, 0, err
+		}
+		return results.Apps, results.ResultsPerPage, nil
+	}, opts...)}
+}
+
+// IterateAdvertiserProfiles will return an iterator over all advertiser profiles (see ListAdvertiserProfiles)
+func (c *Client) IterateAdvertiserProfiles(ctx context.Context, sortBy SortField, sortOrder SortOrder,
+	opts ...IteratorOps) *AdvertiserIterator {
+	return &AdvertiserIterator{pager: newPager(ctx, func(ctx context.Context, page, resultsPerPage int) ([]*AdvertiserProfile, int, error) {
+		results, _, err := c.ListAdvertiserProfilesWithContext(ctx, &AdvertiserListOptions{ListOptions: ListOptions{
+			Page: page, ResultsPerPage: resultsPerPage, SortBy: sortBy, SortOrder: sortOrder,
+		}})
+		if err != nil || results == nil {
+			return nil, 0, err
+		}
+		return results.Advertisers, results.ResultsPerPage, nil
+	}, opts...)}
+}
+
+// IterateConversionsByCampaign will return an iterator over all conversions
+// for the campaign (see ListConversionsByCampaign), the page of the options is ignored
+func (c *Client) IterateConversionsByCampaign(ctx context.Context, campaignID uint64,
+	options *ConversionListOptions, opts ...IteratorOps) *ConversionIterator {
+	return c.iterateConversions(ctx, c.ListConversionsByCampaignWithContext, campaignID, options, opts...)
+}
+
+// IterateConversionsByGoal will return an iterator over all conversions
+// for the goal (see ListConversionsByGoal), the page of the options is ignored
+func (c *Client) IterateConversionsByGoal(ctx context.Context, goalID uint64,
+	options *ConversionListOptions, opts ...IteratorOps) *ConversionIterator {
+	return c.iterateConversions(ctx, c.ListConversionsByGoalWithContext, goalID, options, opts...)
+}
+
+// IterateConversionsByUser will return an iterator over all conversions
+// for the tonicpow user (see ListConversionsByUser), the page of the options is ignored
+func (c *Client) IterateConversionsByUser(ctx context.Context, userID uint64,
+	options *ConversionListOptions, opts ...IteratorOps) *ConversionIterator {
+	return c.iterateConversions(ctx, c.ListConversionsByUserWithContext, userID, options, opts...)
+}
+
+// conversionLister is a method listing the conversions of a parent model (see ListConversionsByCampaign)
+type conversionLister func(ctx context.Context, parentID uint64,
+	options *ConversionListOptions) (*ConversionResults, *StandardResponse, error)
+
+// iterateConversions will return an iterator over all conversions for the parent model
+func (c *Client) iterateConversions(ctx context.Context, list conversionLister, parentID uint64,
+	options *ConversionListOptions, opts ...IteratorOps) *ConversionIterator {
+	filters := ConversionListOptions{}
+	if options != nil {
+		filters = *options
+	}
+	return &ConversionIterator{pager: newPager(ctx, func(ctx context.Context, page, resultsPerPage int) ([]*Conversion, int, error) {
+		pageOptions := filters
+		pageOptions.Page, pageOptions.ResultsPerPage = page, resultsPerPage
+		results, _, err := list(ctx, parentID, &pageOptions)
+		if err != nil || results == nil {
+			return nil, 0, err
+		}
+		return results.Conversions, results.ResultsPerPage, nil
+	}, opts...)}
+}
+
+// IterateGoalsByCampaign will return an iterator over all goals for the campaign (see ListGoalsByCampaign)
+func (c *Client) IterateGoalsByCampaign(ctx context.Context, campaignID uint64,
+	sortBy SortField, sortOrder SortOrder, opts ...IteratorOps) *GoalIterator {
+	return &GoalIterator{pager: newPager(ctx, func(ctx context.Context, page, resultsPerPage int) ([]*Goal, int, error) {
+		results, _, err := c.ListGoalsByCampaignWithContext(ctx, campaignID, &GoalListOptions{ListOptions{
+			Page: page, ResultsPerPage: resultsPerPage, SortBy: sortBy, SortOrder: sortOrder,
+		}})
+		if err != nil || results == nil {
+			return nil, 0, err
+		}
+		return results.Goals, results.ResultsPerPage, nil
+	}, opts...)}
+}
@@ -0,0 +1,327 @@
+package tonicpow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestPageFetcher will return a fetch func that serves the total number of items in pages
+func newTestPageFetcher(total int, calls *int32) pageFetcher[int] {
+	return newTestCappedPageFetcher(total, 0, calls)
+}
+
+// newTestCappedPageFetcher will return a fetch func that serves the total number of items
+// in pages of at most maxPageSize items (0 is no limit), reporting the page size used
+func newTestCappedPageFetcher(total, maxPageSize int, calls *int32) pageFetcher[int] {
+	return func(ctx context.Context, page, resultsPerPage int) ([]int, int, error) {
+		atomic.AddInt32(calls, 1)
+		if maxPageSize > 0 && resultsPerPage > maxPageSize {
+			resultsPerPage = maxPageSize
+		}
+		var items []int
+		for i := (page - 1) * resultsPerPage; i < total && i < page*resultsPerPage; i++ {
+			items = append(items, i)
+		}
+		return items, resultsPerPage, nil
+	}
+}
+
+// collectPager will return all the items of the pager
+func collectPager(p *pager[int]) (items []int) {
+	for p.next() {
+		items = append(items, p.current)
+	}
+	return
+}
+
+// TestPager will test the internal pager
+func TestPager(t *testing.T) {
+	t.Parallel()
+
+	t.Run("multiple pages, last page partial", func(t *testing.T) {
+		var calls int32
+		p := newPager(context.Background(), newTestPageFetcher(7, &calls), WithPageSize(3))
+		assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6}, collectPager(p))
+		assert.NoError(t, p.err)
+		assert.Equal(t, int32(3), calls)
+		assert.False(t, p.next())
+	})
+
+	t.Run("last page full, stops on empty page", func(t *testing.T) {
+		var calls int32
+		p := newPager(context.Background(), newTestPageFetcher(6, &calls), WithPageSize(3))
+		assert.Equal(t, []int{0, 1, 2, 3, 4, 5}, collectPager(p))
+		assert.NoError(t, p.err)
+		assert.Equal(t, int32(3), calls)
+	})
+
+	t.Run("prefetch", func(t *testing.T) {
+		var calls int32
+		p := newPager(context.Background(), newTestPageFetcher(7, &calls), WithPageSize(3), WithPrefetch())
+		assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6}, collectPager(p))
+		assert.NoError(t, p.err)
+		assert.Equal(t, int32(3), calls)
+	})
+
+	t.Run("no results", func(t *testing.T) {
+		var calls int32
+		p := newPager(context.Background(), newTestPageFetcher(0, &calls))
+		assert.Nil(t, collectPager(p))
+		assert.NoError(t, p.err)
+		assert.Equal(t, int32(1), calls)
+	})
+
+	t.Run("not found ends iteration", func(t *testing.T) {
+		p := newPager(context.Background(), func(ctx context.Context, page, resultsPerPage int) ([]int, int, error) {
+			if page > 1 {
+				return nil, 0, &Error{StatusCode: http.StatusNotFound}
+			}
+			return []int{1, 2}, resultsPerPage, nil
+		}, WithPageSize(2), WithPrefetch())
+		assert.Equal(t, []int{1, 2}, collectPager(p))
+		assert.NoError(t, p.err)
+	})
+
+	t.Run("error stops iteration", func(t *testing.T) {
+		p := newPager(context.Background(), func(ctx context.Context, page, resultsPerPage int) ([]int, int, error) {
+			if page > 1 {
+				return nil, 0, errors.New("api is down")
+			}
+			return []int{1, 2}, resultsPerPage, nil
+		}, WithPageSize(2))
+		assert.Equal(t, []int{1, 2}, collectPager(p))
+		assert.Error(t, p.err)
+		assert.False(t, p.next())
+	})
+
+	t.Run("page size capped by the api", func(t *testing.T) {
+		var calls int32
+		p := newPager(context.Background(), newTestCappedPageFetcher(7, 3, &calls), WithPageSize(5))
+		assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6}, collectPager(p))
+		assert.NoError(t, p.err)
+		assert.Equal(t, int32(3), calls)
+	})
+
+	t.Run("page size not reported, stops on empty page", func(t *testing.T) {
+		var calls int32
+		p := newPager(context.Background(), func(ctx context.Context, page, resultsPerPage int) ([]int, int, error) {
+			items, _, err := newTestCappedPageFetcher(7, 3, &calls)(ctx, page, resultsPerPage)
+			return items, 0, err
+		}, WithPageSize(5))
+		assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6}, collectPager(p))
+		assert.NoError(t, p.err)
+		assert.Equal(t, int32(4), calls)
+	})
+
+	t.Run("invalid page size is ignored", func(t *testing.T) {
+		p := newPager(context.Background(), newTestPageFetcher(0, new(int32)), WithPageSize(0))
+		assert.Equal(t, defaultIteratorPageSize, p.options.pageSize)
+	})
+}
+
+// mockCampaignPages will mock the list campaigns endpoint for the given number of campaigns
+func mockCampaignPages(total, resultsPerPage int) {
+	httpmock.Reset()
+	for page := 1; page <= total/resultsPerPage+1; page++ {
+		results := &CampaignResults{CurrentPage: page, ResultsPerPage: resultsPerPage}
+		for i := (page - 1) * resultsPerPage; i < total && i < page*resultsPerPage; i++ {
+			campaign := newTestCampaign()
+			campaign.ID = uint64(i + 1)
+			results.Campaigns = append(results.Campaigns, campaign)
+		}
+		results.Results = len(results.Campaigns)
+		httpmock.RegisterResponder(http.MethodGet, fmt.Sprintf(
+			"%s/%s/list?%s=%d&%s=%d&%s=%s&%s=%s&%s=%s&%s=%d&%s=%t",
+			EnvironmentDevelopment.apiURL, modelCampaign,
+			fieldCurrentPage, page,
+			fieldResultsPerPage, resultsPerPage,
+			fieldSortBy, SortByFieldCreatedAt,
+			fieldSortOrder, SortOrderDesc,
+			fieldSearchQuery, "",
+			fieldMinimumBalance, 0,
+			fieldExpired, false,
+		), httpmock.NewJsonResponderOrPanic(http.StatusOK, results))
+	}
+}
+
+// TestClient_IterateCampaigns will test the method IterateCampaigns()
+func TestClient_IterateCampaigns(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	t.Run("iterate all pages", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		mockCampaignPages(5, 2)
+
+		var ids []uint64
+		iter := client.IterateCampaigns(context.Background(), "", "", "", 0, false, WithPageSize(2))
+		for iter.Next() {
+			ids = append(ids, iter.Campaign().ID)
+		}
+		assert.NoError(t, iter.Err())
+		assert.Equal(t, []uint64{1, 2, 3, 4, 5}, ids)
+	})
+
+	t.Run("iterate all pages (prefetch)", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		mockCampaignPages(4, 2)
+
+		var ids []uint64
+		iter := client.IterateCampaigns(context.Background(), "", "", "", 0, false, WithPageSize(2), WithPrefetch())
+		for iter.Next() {
+			ids = append(ids, iter.Campaign().ID)
+		}
+		assert.NoError(t, iter.Err())
+		assert.Equal(t, []uint64{1, 2, 3, 4}, ids)
+	})
+
+	t.Run("invalid sort field", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		iter := client.IterateCampaigns(context.Background(), "bad_field", "", "", 0, false)
+		assert.False(t, iter.Next())
+		assert.Error(t, iter.Err())
+		assert.Nil(t, iter.Campaign())
+	})
+}
+
+// TestClient_IterateCampaignsByURL will test the method IterateCampaignsByURL()
+func TestClient_IterateCampaignsByURL(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	client, err := newTestClient()
+	assert.NoError(t, err)
+	assert.NotNil(t, client)
+
+	err = mockResponseData(http.MethodGet, fmt.Sprintf("%s/%s/list?%s=%s&%s=%d&%s=%d&%s=%s&%s=%s",
+		EnvironmentDevelopment.apiURL, modelCampaign,
+		fieldTargetURL, url.QueryEscape(testCampaignTargetURL),
+		fieldCurrentPage, 1,
+		fieldResultsPerPage, 25,
+		fieldSortBy, SortByFieldCreatedAt,
+		fieldSortOrder, SortOrderDesc,
+	), http.StatusOK, newTestCampaignResults(1, 25))
+	assert.NoError(t, err)
+
+	iter := client.IterateCampaignsByURL(context.Background(), testCampaignTargetURL, "", "")
+	assert.True(t, iter.Next())
+	assert.Equal(t, testCampaignID, iter.Campaign().ID)
+	assert.False(t, iter.Next())
+	assert.NoError(t, iter.Err())
+}
+
+// TestClient_IterateCampaignsByAdvertiserProfile will test the method IterateCampaignsByAdvertiserProfile()
+func TestClient_IterateCampaignsByAdvertiserProfile(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	client, err := newTestClient()
+	assert.NoError(t, err)
+	assert.NotNil(t, client)
+
+	err = mockResponseData(http.MethodGet, fmt.Sprintf("%s/%s/%s/%d?%s=%d&%s=%d&%s=%s&%s=%s",
+		EnvironmentDevelopment.apiURL,
+		modelAdvertiser, modelCampaign, testAdvertiserID,
+		fieldCurrentPage, 1,
+		fieldResultsPerPage, 25,
+		fieldSortBy, SortByFieldCreatedAt,
+		fieldSortOrder, SortOrderDesc,
+	), http.StatusOK, newTestCampaignResults(1, 25))
+	assert.NoError(t, err)
+
+	iter := client.IterateCampaignsByAdvertiserProfile(context.Background(), testAdvertiserID, "", "")
+	assert.True(t, iter.Next())
+	assert.Equal(t, testCampaignID, iter.Campaign().ID)
+	assert.False(t, iter.Next())
+	assert.NoError(t, iter.Err())
+}
+
+// TestClient_IterateAppsByAdvertiserProfile will test the method IterateAppsByAdvertiserProfile()
+func TestClient_IterateAppsByAdvertiserProfile(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	t.Run("single page", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		err = mockResponseData(http.MethodGet, fmt.Sprintf("%s/%s/%s/?%s=%d&%s=%d&%s=%d&%s=%s&%s=%s",
+			EnvironmentDevelopment.apiURL,
+			modelAdvertiser, modelApp,
+			fieldID, testAdvertiserID,
+			fieldCurrentPage, 1,
+			fieldResultsPerPage, 25,
+			fieldSortBy, SortByFieldCreatedAt,
+			fieldSortOrder, SortOrderDesc,
+		), http.StatusOK, newTestAppResults(1, 25))
+		assert.NoError(t, err)
+
+		iter := client.IterateAppsByAdvertiserProfile(context.Background(), testAdvertiserID, "", "")
+		assert.True(t, iter.Next())
+		assert.Equal(t, testAppID, iter.App().ID)
+		assert.False(t, iter.Next())
+		assert.NoError(t, iter.Err())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		err = mockResponseData(http.MethodGet, fmt.Sprintf("%s/%s/%s/?%s=%d&%s=%d&%s=%d&%s=%s&%s=%s",
+			EnvironmentDevelopment.apiURL,
+			modelAdvertiser, modelApp,
+			fieldID, testAdvertiserID,
+			fieldCurrentPage, 1,
+			fieldResultsPerPage, 25,
+			fieldSortBy, SortByFieldCreatedAt,
+			fieldSortOrder, SortOrderDesc,
+		), http.StatusNotFound, &Error{Message: "no apps found", StatusCode: http.StatusNotFound})
+		assert.NoError(t, err)
+
+		iter := client.IterateAppsByAdvertiserProfile(context.Background(), testAdvertiserID, "", "")
+		assert.False(t, iter.Next())
+		assert.NoError(t, iter.Err())
+	})
+}
+
+// ExampleClient_IterateCampaigns example using IterateCampaigns()
+//
+// See more examples in /examples/
+func ExampleClient_IterateCampaigns() {
+
+	// Load the client (using test client for example only)
+	client, err := newTestClient()
+	if err != nil {
+		fmt.Printf("error loading client: %s", err.Error())
+		return
+	}
+
+	// Mock response (for example only)
+	mockCampaignPages(3, 2)
+
+	// Iterate all campaigns (using mocking response)
+	iter := client.IterateCampaigns(context.Background(), "", "", "", 0, false, WithPageSize(2))
+	for iter.Next() {
+		fmt.Printf("campaign: %d ", iter.Campaign().ID)
+	}
+	if err = iter.Err(); err != nil {
+		fmt.Printf("error iterating campaigns: %s", err.Error())
+		return
+	}
+	// Output:campaign: 1 campaign: 2 campaign: 3
+}
@@ -0,0 +1,202 @@
+package tonicpow
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultResultsPerPage is the results per page of the list requests if not set
+const defaultResultsPerPage = 25
+
+// ListOptions are the pagination & sorting options of the list requests
+type ListOptions struct {
+	Page           int       // Page of results (starts at 1, default is 1)
+	ResultsPerPage int       // Number of results per page (default is 25)
+	SortBy         SortField // (optional) field to sort by (default is created_at, descending)
+	SortOrder      SortOrder // (optional) asc or desc
+	positional     bool      // Options of the positional List*() methods (see encodePositional)
+}
+
+// encode will validate the options and add them to the query (sort fields are the fields allowed)
+func (o *ListOptions) encode(query *queryValues, sortFields []SortField) error {
+	if o.positional {
+		return o.encodePositional(query, sortFields)
+	}
+	sortBy, sortOrder := SortField(strings.ToLower(string(o.SortBy))), SortOrder(strings.ToLower(string(o.SortOrder)))
+
+	// Do we know this field & order?
+	if len(sortBy) > 0 {
+		if !isInList(sortBy, sortFields) {
+			return fmt.Errorf("sort by %s is not valid", o.SortBy)
+		} else if len(sortOrder) > 0 && sortOrder != SortOrderAsc && sortOrder != SortOrderDesc {
+			return fmt.Errorf("sort order %s is not valid", o.SortOrder)
+		}
+	} else {
+		sortBy = SortByFieldCreatedAt
+		sortOrder = SortOrderDesc
+	}
+
+	// Default to the first page & the standard page size
+	page, resultsPerPage := o.Page, o.ResultsPerPage
+	if page < 1 {
+		page = 1
+	}
+	if resultsPerPage < 1 {
+		resultsPerPage = defaultResultsPerPage
+	}
+
+	query.add(fieldCurrentPage, strconv.Itoa(page))
+	query.add(fieldResultsPerPage, strconv.Itoa(resultsPerPage))
+	query.add(fieldSortBy, string(sortBy))
+	query.add(fieldSortOrder, string(sortOrder))
+	return nil
+}
+
+// encodePositional will add the options of the positional List*() methods to the query as given (escaped),
+// only the sort field is checked (not case-sensitive) and the page & sort order are not changed
+func (o *ListOptions) encodePositional(query *queryValues, sortFields []SortField) error {
+	sortBy, sortOrder := o.SortBy, o.SortOrder
+	if len(sortBy) > 0 {
+		if !isInList(SortField(strings.ToLower(string(sortBy))), sortFields) {
+			return fmt.Errorf("sort by %s is not valid", sortBy)
+		}
+	} else {
+		sortBy = SortByFieldCreatedAt
+		sortOrder = SortOrderDesc
+	}
+
+	query.add(fieldCurrentPage, strconv.Itoa(o.Page))
+	query.add(fieldResultsPerPage, strconv.Itoa(o.ResultsPerPage))
+	query.add(fieldSortBy, string(sortBy))
+	query.add(fieldSortOrder, string(sortOrder))
+	return nil
+}
+
+// newPositionalListOptions will return the list options of the positional List*() methods
+func newPositionalListOptions(page, resultsPerPage int, sortBy, sortOrder string) ListOptions {
+	return ListOptions{
+		Page: page, ResultsPerPage: resultsPerPage, SortBy: SortField(sortBy), SortOrder: SortOrder(sortOrder),
+		positional: true,
+	}
+}
+
+// AdvertiserListOptions are the options for listing advertiser profiles (see ListAdvertiserProfiles)
+type AdvertiserListOptions struct {
+	ListOptions
+}
+
+// encode will validate the options and add them to the query
+func (o *AdvertiserListOptions) encode(query *queryValues) error {
+	return o.ListOptions.encode(query, advertiserSortFields)
+}
+
+// AppListOptions are the options for listing apps (see QueryAppsByAdvertiserProfile)
+type AppListOptions struct {
+	ListOptions
+}
+
+// encode will validate the options and add them to the query
+func (o *AppListOptions) encode(query *queryValues) error {
+	return o.ListOptions.encode(query, appSortFields)
+}
+
+// CampaignListOptions are the options for listing campaigns (see QueryCampaigns)
+//
+// TargetURL lists the campaigns for the target url, and cannot be combined with the search options
+// (SearchQuery, MinimumBalance and IncludeExpired)
+type CampaignListOptions struct {
+	ListOptions
+	IncludeExpired bool   // (optional) include the expired campaigns
+	MinimumBalance uint64 // (optional) minimum balance of the campaigns (satoshis)
+	SearchQuery    string // (optional) search the campaigns
+	TargetURL      string // (optional) only the campaigns for the target url
+}
+
+// encode will validate the options and add them to the query
+func (o *CampaignListOptions) encode(query *queryValues) error {
+
+	// Either by target url or searching
+	if len(o.TargetURL) > 0 {
+		if len(o.SearchQuery) > 0 || o.MinimumBalance > 0 || o.IncludeExpired {
+			return errors.New("target_url cannot be combined with query, minimum_balance or expired")
+		}
+		query.add(fieldTargetURL, o.TargetURL)
+		return o.ListOptions.encode(query, campaignSortFields)
+	}
+	if err := o.ListOptions.encode(query, campaignSortFields); err != nil {
+		return err
+	}
+	query.add(fieldSearchQuery, o.SearchQuery)
+	query.add(fieldMinimumBalance, strconv.FormatUint(o.MinimumBalance, 10))
+	query.add(fieldExpired, strconv.FormatBool(o.IncludeExpired))
+	return nil
+}
+
+// ConversionListOptions are the options for listing conversions (see ListConversionsByCampaign)
+//
+// From and To filter on the creation time of the conversions (From is inclusive, To is exclusive)
+type ConversionListOptions struct {
+	ListOptions
+	From        time.Time   // (optional) only the conversions created at or after this time
+	PayoutState PayoutState // (optional) only the paid or unpaid conversions
+	Status      string      // (optional) only the conversions with the status (ConversionStatusPaid, etc.)
+	To          time.Time   // (optional) only the conversions created before this time
+}
+
+// encode will validate the options and add them to the query (only the filters set are added)
+func (o *ConversionListOptions) encode(query *queryValues) error {
+	status, payoutState := strings.ToLower(o.Status), PayoutState(strings.ToLower(string(o.PayoutState)))
+
+	// Known filters only
+	if len(status) > 0 && !isInList(status, conversionStatuses) {
+		return fmt.Errorf("status %s is not valid", o.Status)
+	} else if len(payoutState) > 0 && payoutState != PayoutStatePaid && payoutState != PayoutStateUnpaid {
+		return fmt.Errorf("payout state %s is not valid", o.PayoutState)
+	} else if !o.From.IsZero() && !o.To.IsZero() && !o.To.After(o.From) {
+		return errors.New("to must be after from")
+	}
+
+	if err := o.ListOptions.encode(query, conversionSortFields); err != nil {
+		return err
+	}
+	if len(status) > 0 {
+		query.add(fieldStatus, status)
+	}
+	if len(payoutState) > 0 {
+		query.add(fieldPayoutState, string(payoutState))
+	}
+	if !o.From.IsZero() {
+		query.add(fieldFrom, o.From.UTC().Format(timestampLayout))
+	}
+	if !o.To.IsZero() {
+		query.add(fieldTo, o.To.UTC().Format(timestampLayout))
+	}
+	return nil
+}
+
+// GoalListOptions are the options for listing goals (see ListGoalsByCampaign)
+type GoalListOptions struct {
+	ListOptions
+}
+
+// encode will validate the options and add them to the query
+func (o *GoalListOptions) encode(query *queryValues) error {
+	return o.ListOptions.encode(query, goalSortFields)
+}
+
+// queryValues is an escaped query string (unlike url.Values, the values are encoded in the order added)
+type queryValues []string
+
+// add will add the escaped key & value
+func (q *queryValues) add(key, value string) {
+	*q = append(*q, url.QueryEscape(key)+"="+url.QueryEscape(value))
+}
+
+// String will return the query string
+func (q queryValues) String() string {
+	return strings.Join(q, "&")
+}
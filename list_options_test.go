@@ -0,0 +1,266 @@
+package tonicpow
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestListOptions_encode will test the method encode()
+func TestListOptions_encode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		options  ListOptions
+		expected string
+		err      string
+	}{
+		{"default sorting", ListOptions{Page: 1, ResultsPerPage: 25},
+			"current_page=1&results_per_page=25&sort_by=created_at&sort_order=desc", ""},
+		{"sort order is ignored without a field", ListOptions{SortOrder: SortOrderAsc},
+			"current_page=1&results_per_page=25&sort_by=created_at&sort_order=desc", ""},
+		{"sort field & order", ListOptions{Page: 2, ResultsPerPage: 10, SortBy: SortByFieldBalance, SortOrder: SortOrderAsc},
+			"current_page=2&results_per_page=10&sort_by=balance&sort_order=asc", ""},
+		{"upper case", ListOptions{SortBy: "BALANCE", SortOrder: "DESC"},
+			"current_page=1&results_per_page=25&sort_by=balance&sort_order=desc", ""},
+		{"no sort order", ListOptions{SortBy: SortByFieldPaidClicks},
+			"current_page=1&results_per_page=25&sort_by=paid_clicks&sort_order=", ""},
+		{"negative page & results per page", ListOptions{Page: -1, ResultsPerPage: -10},
+			"current_page=1&results_per_page=25&sort_by=created_at&sort_order=desc", ""},
+		{"invalid sort field", ListOptions{SortBy: "name"}, "", "sort by name is not valid"},
+		{"injected sort field", ListOptions{SortBy: "balance&expired=true"}, "",
+			"sort by balance&expired=true is not valid"},
+		{"invalid sort order", ListOptions{SortBy: SortByFieldBalance, SortOrder: "random"}, "",
+			"sort order random is not valid"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var query queryValues
+			err := test.options.encode(&query, campaignSortFields)
+			if len(test.err) > 0 {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, query.String())
+		})
+	}
+}
+
+// TestCampaignListOptions_encode will test the method encode()
+func TestCampaignListOptions_encode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("search values are escaped", func(t *testing.T) {
+		options := &CampaignListOptions{SearchQuery: "cats & dogs #1", MinimumBalance: 100, IncludeExpired: true}
+		var query queryValues
+		require.NoError(t, options.encode(&query))
+		assert.Equal(t, "current_page=1&results_per_page=25&sort_by=created_at&sort_order=desc"+
+			"&query=cats+%26+dogs+%231&minimum_balance=100&expired=true", query.String())
+	})
+
+	t.Run("target url is escaped", func(t *testing.T) {
+		options := &CampaignListOptions{TargetURL: "https://tonicpow.com/?a=1&b=2#top"}
+		var query queryValues
+		require.NoError(t, options.encode(&query))
+		assert.Equal(t, "target_url=https%3A%2F%2Ftonicpow.com%2F%3Fa%3D1%26b%3D2%23top"+
+			"&current_page=1&results_per_page=25&sort_by=created_at&sort_order=desc", query.String())
+	})
+
+	t.Run("target url cannot be combined with searching", func(t *testing.T) {
+		options := &CampaignListOptions{TargetURL: "https://tonicpow.com", SearchQuery: "cats"}
+		var query queryValues
+		assert.Error(t, options.encode(&query))
+	})
+
+	t.Run("app sort fields", func(t *testing.T) {
+		var query queryValues
+		require.NoError(t, (&AppListOptions{ListOptions{SortBy: SortByFieldName}}).encode(&query))
+		assert.Error(t, (&AppListOptions{ListOptions{SortBy: SortByFieldBalance}}).encode(&query))
+	})
+}
+
+// TestConversionListOptions_encode will test the method encode()
+func TestConversionListOptions_encode(t *testing.T) {
+	t.Parallel()
+
+	from := time.Date(2021, 1, 1, 0, 0, 0, 0, time.FixedZone("EST", -5*60*60))
+	tests := []struct {
+		name     string
+		options  ConversionListOptions
+		expected string
+		err      string
+	}{
+		{"no filters", ConversionListOptions{},
+			"current_page=1&results_per_page=25&sort_by=created_at&sort_order=desc", ""},
+		{"status & payout state", ConversionListOptions{Status: "PAID", PayoutState: PayoutStatePaid},
+			"current_page=1&results_per_page=25&sort_by=created_at&sort_order=desc&status=paid&payout_state=paid", ""},
+		{"date range in utc", ConversionListOptions{From: from, To: from.Add(time.Hour)},
+			"current_page=1&results_per_page=25&sort_by=created_at&sort_order=desc" +
+				"&from=2021-01-01+05%3A00%3A00&to=2021-01-01+06%3A00%3A00", ""},
+		{"sort by amount", ConversionListOptions{ListOptions: ListOptions{SortBy: SortByFieldAmount}},
+			"current_page=1&results_per_page=25&sort_by=amount&sort_order=", ""},
+		{"invalid status", ConversionListOptions{Status: "refunded"}, "", "status refunded is not valid"},
+		{"invalid payout state", ConversionListOptions{PayoutState: "partial"}, "", "payout state partial is not valid"},
+		{"invalid date range", ConversionListOptions{From: from, To: from}, "", "to must be after from"},
+		{"invalid sort field", ConversionListOptions{ListOptions: ListOptions{SortBy: SortByFieldBalance}}, "",
+			"sort by balance is not valid"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var query queryValues
+			err := test.options.encode(&query)
+			if len(test.err) > 0 {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, query.String())
+		})
+	}
+}
+
+// TestClient_QueryCampaigns will test the method QueryCampaigns()
+func TestClient_QueryCampaigns(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	t.Run("search query with reserved characters", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		endpoint := fmt.Sprintf(
+			"%s/%s/list?current_page=1&results_per_page=25&sort_by=balance&sort_order=asc"+
+				"&query=a+%%26+b&minimum_balance=0&expired=false",
+			EnvironmentDevelopment.apiURL, modelCampaign,
+		)
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestCampaignResults(1, 25))
+		require.NoError(t, err)
+
+		var results *CampaignResults
+		results, _, err = client.QueryCampaigns(&CampaignListOptions{
+			ListOptions: ListOptions{Page: 1, ResultsPerPage: 25, SortBy: SortByFieldBalance, SortOrder: SortOrderAsc},
+			SearchQuery: "a & b",
+		})
+		require.NoError(t, err)
+		require.NotNil(t, results)
+		assert.Equal(t, testCampaignID, results.Campaigns[0].ID)
+	})
+
+	t.Run("nil options", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		endpoint := fmt.Sprintf(
+			"%s/%s/list?current_page=1&results_per_page=25&sort_by=created_at&sort_order=desc"+
+				"&query=&minimum_balance=0&expired=false",
+			EnvironmentDevelopment.apiURL, modelCampaign,
+		)
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestCampaignResults(1, 25))
+		require.NoError(t, err)
+
+		_, _, err = client.QueryCampaigns(nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("positional method with string sorting (same query)", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+
+		endpoint := fmt.Sprintf(
+			"%s/%s/list?current_page=1&results_per_page=25&sort_by=balance&sort_order=asc"+
+				"&query=a+%%26+b&minimum_balance=0&expired=false",
+			EnvironmentDevelopment.apiURL, modelCampaign,
+		)
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestCampaignResults(1, 25))
+		require.NoError(t, err)
+
+		sortBy, sortOrder := "balance", SortOrderAsc // Both are string variables
+		_, _, err = client.ListCampaigns(1, 25, sortBy, sortOrder, "a & b", 0, false)
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid options are not sent", func(t *testing.T) {
+		client, err := newTestClient()
+		require.NoError(t, err)
+		httpmock.Reset()
+
+		_, _, err = client.QueryCampaigns(&CampaignListOptions{
+			ListOptions: ListOptions{SortBy: SortByFieldBalance, SortOrder: "sideways"},
+		})
+		assert.EqualError(t, err, "sort order sideways is not valid")
+		assert.Equal(t, 0, httpmock.GetTotalCallCount())
+	})
+}
+
+// TestClient_GetCampaignBySlug_escaped will test the method GetCampaignBySlug() with reserved characters
+func TestClient_GetCampaignBySlug_escaped(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	client, err := newTestClient()
+	require.NoError(t, err)
+
+	endpoint := fmt.Sprintf("%s/%s/details/?%s=%s", EnvironmentDevelopment.apiURL, modelCampaign,
+		fieldSlug, "a%26b%23c+d")
+	err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestCampaign())
+	require.NoError(t, err)
+
+	var campaign *Campaign
+	campaign, _, err = client.GetCampaignBySlug("a&b#c d")
+	require.NoError(t, err)
+	assert.Equal(t, testCampaignID, campaign.ID)
+}
+
+// TestClient_QueryAppsByAdvertiserProfile will test the method QueryAppsByAdvertiserProfile()
+func TestClient_QueryAppsByAdvertiserProfile(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	client, err := newTestClient()
+	require.NoError(t, err)
+
+	endpoint := fmt.Sprintf(
+		"%s/%s/%s/?%s=%d&current_page=2&results_per_page=5&sort_by=name&sort_order=asc",
+		EnvironmentDevelopment.apiURL, modelAdvertiser, modelApp, fieldID, testAdvertiserID,
+	)
+	err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestAppResults(2, 5))
+	require.NoError(t, err)
+
+	var apps *AppResults
+	apps, _, err = client.QueryAppsByAdvertiserProfile(testAdvertiserID, &AppListOptions{ListOptions{
+		Page: 2, ResultsPerPage: 5, SortBy: SortByFieldName, SortOrder: SortOrderAsc,
+	}})
+	require.NoError(t, err)
+	require.NotNil(t, apps)
+	assert.Equal(t, testAppID, apps.Apps[0].ID)
+
+	_, _, err = client.QueryAppsByAdvertiserProfile(0, nil)
+	assert.Error(t, err)
+}
+
+// TestClient_QueryCampaignsByAdvertiserProfile will test the method QueryCampaignsByAdvertiserProfile()
+func TestClient_QueryCampaignsByAdvertiserProfile(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	client, err := newTestClient()
+	require.NoError(t, err)
+
+	endpoint := fmt.Sprintf(
+		"%s/%s/%s/%d?current_page=1&results_per_page=10&sort_by=links_created&sort_order=desc",
+		EnvironmentDevelopment.apiURL, modelAdvertiser, modelCampaign, testAdvertiserID,
+	)
+	err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestCampaignResults(1, 10))
+	require.NoError(t, err)
+
+	var results *CampaignResults
+	results, _, err = client.QueryCampaignsByAdvertiserProfile(testAdvertiserID, &ListOptions{
+		Page: 1, ResultsPerPage: 10, SortBy: SortByFieldLinksCreated, SortOrder: SortOrderDesc,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, results)
+	assert.Equal(t, testCampaignID, results.Campaigns[0].ID)
+}
@@ -0,0 +1,60 @@
+package tonicpow
+
+import (
+	"context"
+)
+
+// Call is a single API call (all attempts of a request) passed to the middleware
+type Call struct {
+	Attempts     int         // Number of attempts made (set when the call is done, 0 if served from the cache)
+	Data         interface{} // Request data (PUT & POST)
+	Endpoint     string      // Endpoint of the request (/campaigns/details/?id=1)
+	ExpectedCode int         // Expected status code
+	IDs          CallIDs     // IDs of the models of the call (set by the service methods)
+	Method       string      // HTTP method
+	Operation    string      // Name of the service method (GetCampaign), or Request if called directly
+}
+
+// CallIDs are the IDs of the models an API call is made for (0 if not known or not related to the call)
+//
+// ListConversionsByCampaign sets the CampaignID, GetConversion sets the ConversionID, etc.
+type CallIDs struct {
+	AdvertiserProfileID uint64 // Advertiser profile of the call
+	CampaignID          uint64 // Campaign of the call
+	ConversionID        uint64 // Conversion of the call
+	GoalID              uint64 // Goal of the call
+}
+
+// RequestHandler handles an API call (see RequestMiddleware)
+type RequestHandler func(ctx context.Context, call *Call) (*StandardResponse, error)
+
+// RequestMiddleware wraps every API call made by the client (see WithMiddleware)
+//
+// Unlike Hooks (called for every attempt), the middleware is called once per call
+// and can change the context used for all attempts (tracing, deadlines, etc.)
+type RequestMiddleware func(next RequestHandler) RequestHandler
+
+// WithMiddleware will add middleware that wraps every API call (the first middleware is the outermost)
+func WithMiddleware(middleware ...RequestMiddleware) ClientOps {
+	return func(c *ClientOptions) {
+		for _, m := range middleware {
+			if m != nil {
+				c.middleware = append(c.middleware, m)
+			}
+		}
+	}
+}
+
+// withOperation will set the name of the service method making the request (Call.Operation)
+func withOperation(operation string) RequestOps {
+	return func(r *requestOptions) {
+		r.operation = operation
+	}
+}
+
+// withCallIDs will set the IDs of the models the request is made for (Call.IDs)
+func withCallIDs(ids CallIDs) RequestOps {
+	return func(r *requestOptions) {
+		r.ids = ids
+	}
+}
@@ -0,0 +1,92 @@
+package tonicpow
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testContextKey is the context key used to test the middleware
+type testContextKey struct{}
+
+// TestClient_Middleware will test the request middleware
+func TestClient_Middleware(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	campaignURL := EnvironmentDevelopment.apiURL + campaignEndpoint(testCampaignID)
+
+	t.Run("middleware wraps every call", func(t *testing.T) {
+		var order []string
+		var calls []*Call
+		record := func(name string) RequestMiddleware {
+			return func(next RequestHandler) RequestHandler {
+				return func(ctx context.Context, call *Call) (*StandardResponse, error) {
+					order = append(order, name+" before")
+					response, err := next(context.WithValue(ctx, testContextKey{}, name), call)
+					order = append(order, name+" after")
+					if name == "outer" {
+						calls = append(calls, call)
+					}
+					return response, err
+				}
+			}
+		}
+
+		var contextValue interface{}
+		client, err := newRetryTestClient(
+			WithRetryPolicy(newTestRetryPolicy(2)),
+			WithMiddleware(record("outer"), nil, record("inner")),
+			WithHooks(&Hooks{BeforeRequest: func(ctx context.Context, _ *RequestInfo) {
+				contextValue = ctx.Value(testContextKey{})
+			}}),
+		)
+		require.NoError(t, err)
+
+		mockResponseSequence(http.MethodGet, campaignURL, nil, http.StatusBadGateway, http.StatusOK)
+		_, _, err = client.GetCampaign(testCampaignID)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"outer before", "inner before", "inner after", "outer after"}, order)
+		assert.Equal(t, "inner", contextValue)
+		require.Len(t, calls, 1)
+		assert.Equal(t, "GetCampaign", calls[0].Operation)
+		assert.Equal(t, 2, calls[0].Attempts)
+		assert.Equal(t, http.MethodGet, calls[0].Method)
+		assert.Equal(t, campaignEndpoint(testCampaignID), calls[0].Endpoint)
+		assert.Equal(t, http.StatusOK, calls[0].ExpectedCode)
+		assert.Equal(t, CallIDs{CampaignID: testCampaignID}, calls[0].IDs)
+	})
+
+	t.Run("operation names", func(t *testing.T) {
+		var operations []string
+		client, err := newRetryTestClient(WithRetryCount(0), WithMiddleware(
+			func(next RequestHandler) RequestHandler {
+				return func(ctx context.Context, call *Call) (*StandardResponse, error) {
+					operations = append(operations, call.Operation)
+					return next(ctx, call)
+				}
+			},
+		))
+		require.NoError(t, err)
+
+		mockResponseSequence(http.MethodGet, campaignURL, nil, http.StatusOK)
+		_, _, _ = client.GetCampaignWithContext(context.Background(), testCampaignID)
+		_, _ = client.Request(http.MethodGet, campaignEndpoint(testCampaignID), nil, http.StatusOK)
+		_, _, _ = client.CampaignsFeedParsed(FeedTypeRSS)
+		_, _ = client.UpdateCampaign(newTestCampaign())
+		_, _, _ = client.ListCampaigns(1, 10, "", "", "", 0, false)
+		_, _, _ = client.QueryCampaigns(nil)
+		_, _, _ = client.ListConversionsByCampaign(testCampaignID, nil)
+		_, _, _ = client.ListConversionsByGoal(testGoalID, nil)
+		_, _, _ = client.ListConversionsByUserWithContext(context.Background(), testUserID, nil)
+		_, _, _ = client.UpdateAppWebhookURL(testAppID, "https://example.com/webhooks")
+
+		assert.Equal(t, []string{
+			"GetCampaign", "Request", "CampaignsFeed", "UpdateCampaign", "ListCampaigns", "QueryCampaigns",
+			"ListConversionsByCampaign", "ListConversionsByGoal", "ListConversionsByUser", "UpdateAppWebhookURL",
+		}, operations)
+	})
+}
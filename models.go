@@ -49,7 +49,7 @@ type Campaign struct {
 	Goals                 []*Goal               `json:"goals"`
 	Images                []*CampaignImage      `json:"images"`
 	CreatedAt             string                `json:"created_at"`
-	Currency              string                `json:"currency"`
+	Currency              Currency              `json:"currency"`
 	Description           string                `json:"description"`
 	ExpiresAt             string                `json:"expires_at"`
 	FundingAddress        string                `json:"funding_address"`
@@ -59,14 +59,14 @@ type Campaign struct {
 	PublicGUID            string                `json:"public_guid"`
 	Slug                  string                `json:"slug"`
 	TargetURL             string                `json:"target_url"`
-	TargetType            string                `json:"target_type"`
+	TargetType            TargetType            `json:"target_type"`
 	TargetData            string                `json:"target_data"`
 	Title                 string                `json:"title"`
 	TxID                  string                `json:"-"`
 	AdvertiserProfile     *AdvertiserProfile    `json:"advertiser_profile"`
-	Balance               float64               `json:"balance"`
-	BalanceAlertThreshold float64               `json:"balance_alert_threshold"`
-	PayPerClickRate       float64               `json:"pay_per_click_rate"`
+	Balance               Decimal               `json:"balance"`
+	BalanceAlertThreshold Decimal               `json:"balance_alert_threshold"`
+	PayPerClickRate       Decimal               `json:"pay_per_click_rate"`
 	AdvertiserProfileID   uint64                `json:"advertiser_profile_id"`
 	BalanceSatoshis       uint64                `json:"balance_satoshis"`
 	ID                    uint64                `json:"id,omitempty"`
@@ -74,7 +74,7 @@ type Campaign struct {
 	LinkServiceDomainID   uint64                `json:"link_service_domain_id"`
 	PaidClicks            uint64                `json:"paid_clicks"`
 	PaidConversions       uint64                `json:"paid_conversions"`
-	PayoutMode            int                   `json:"payout_mode"`
+	PayoutMode            PayoutMode            `json:"payout_mode"`
 	Requirements          *CampaignRequirements `json:"requirements"`
 	BotProtection         bool                  `json:"bot_protection"`
 	ContributeEnabled     bool                  `json:"contribute_enabled"`
@@ -118,8 +118,9 @@ type CampaignResults struct {
 //
 // For more information: https://docs.tonicpow.com/#75c837d5-3336-4d87-a686-d80c6f8938b9
 type Conversion struct {
-	Amount           float64 `json:"amount,omitempty"`
+	Amount           Decimal `json:"amount"` // Omitted from JSON if zero (see MarshalJSON)
 	CampaignID       uint64  `json:"campaign_id"`
+	CreatedAt        string  `json:"created_at,omitempty"`
 	CustomDimensions string  `json:"custom_dimensions"`
 	GoalID           uint64  `json:"goal_id"`
 	GoalName         string  `json:"goal_name,omitempty"`
@@ -131,29 +132,45 @@ type Conversion struct {
 	UserID           uint64  `json:"user_id"`
 }
 
+// ConversionResults is the page response for conversion results from listing
+type ConversionResults struct {
+	Conversions    []*Conversion `json:"conversions"`
+	CurrentPage    int           `json:"current_page"`
+	Results        int           `json:"results"`
+	ResultsPerPage int           `json:"results_per_page"`
+}
+
 // Goal is the goal model (child of Campaign)
 //
 // For more information: https://docs.tonicpow.com/#316b77ab-4900-4f3d-96a7-e67c00af10ca
 type Goal struct {
-	CampaignID      uint64  `json:"campaign_id"`
-	Description     string  `json:"description"`
-	ID              uint64  `json:"id,omitempty"`
-	LastConvertedAt string  `json:"last_converted_at"`
-	MaxPerPromoter  int16   `json:"max_per_promoter"`
-	MaxPerVisitor   int16   `json:"max_per_visitor"`
-	Name            string  `json:"name"`
-	PayoutInstant   bool    `json:"payout_instant"`
-	PayoutRate      float64 `json:"payout_rate"`
-	Payouts         int     `json:"payouts"`
-	PayoutType      string  `json:"payout_type"`
-	Title           string  `json:"title"`
+	CampaignID      uint64     `json:"campaign_id"`
+	Description     string     `json:"description"`
+	ID              uint64     `json:"id,omitempty"`
+	LastConvertedAt string     `json:"last_converted_at"`
+	MaxPerPromoter  int16      `json:"max_per_promoter"`
+	MaxPerVisitor   int16      `json:"max_per_visitor"`
+	Name            string     `json:"name"`
+	PayoutInstant   bool       `json:"payout_instant"`
+	PayoutRate      Decimal    `json:"payout_rate"`
+	Payouts         int        `json:"payouts"`
+	PayoutType      PayoutType `json:"payout_type"`
+	Title           string     `json:"title"`
+}
+
+// GoalResults is the page response for goal results from listing
+type GoalResults struct {
+	CurrentPage    int     `json:"current_page"`
+	Goals          []*Goal `json:"goals"`
+	Results        int     `json:"results"`
+	ResultsPerPage int     `json:"results_per_page"`
 }
 
 // Rate is the rate results
 //
 // For more information: https://docs.tonicpow.com/#fb00736e-61b9-4ec9-acaf-e3f9bb046c89
 type Rate struct {
-	Currency        string  `json:"currency"`
-	CurrencyAmount  float64 `json:"currency_amount"`
-	PriceInSatoshis int64   `json:"price_in_satoshis"`
+	Currency        Currency `json:"currency"`
+	CurrencyAmount  Decimal  `json:"currency_amount"`
+	PriceInSatoshis int64    `json:"price_in_satoshis"`
 }
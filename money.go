@@ -0,0 +1,361 @@
+package tonicpow
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DecimalPlaces is the number of decimal places of a Decimal (the precision of a satoshi)
+	DecimalPlaces = 8
+
+	// decimalScale is 10^DecimalPlaces
+	decimalScale = 100000000
+
+	// SatoshisPerBitcoin is the number of satoshis in one BSV
+	SatoshisPerBitcoin = decimalScale
+)
+
+// Currency is an ISO 4217 (or crypto) currency code, in lower case as used by the API (usd, bsv, etc.)
+type Currency string
+
+const (
+	// CurrencyBSV is Bitcoin SV
+	CurrencyBSV Currency = "bsv"
+
+	// CurrencyEUR is the Euro
+	CurrencyEUR Currency = "eur"
+
+	// CurrencyUSD is the US Dollar (default currency of campaigns)
+	CurrencyUSD Currency = "usd"
+)
+
+// decimalPattern is the format of a valid decimal string (checked before parsing)
+var decimalPattern = regexp.MustCompile(`^[+-]?\d+(\.\d+)?$`)
+
+// jsonNumberPattern is the format of a valid JSON number (Go encodes small floats with an exponent: 5e-7)
+//
+// The exponent is limited to 3 digits, larger exponents are out of range (or round to 0) and are costly to parse
+var jsonNumberPattern = regexp.MustCompile(`^-?(0|[1-9]\d*)(\.\d+)?([eE][+-]?\d{1,3})?$`)
+
+// ErrInvalidDecimal is returned when parsing an invalid (or out of range) decimal (can be used with errors.Is())
+var ErrInvalidDecimal = errors.New("invalid decimal")
+
+// Decimal is an exact decimal amount with 8 decimal places (no float rounding errors)
+//
+// The zero value is 0. Decimals are marshaled to JSON as numbers (13.37) and can be
+// unmarshaled from numbers (including exponents: 1e-8) or plain decimal strings, which is compatible with the API
+type Decimal struct {
+	units int64 // Amount in 10^-8 units
+}
+
+// NewDecimal will return the decimal value * 10^-places (NewDecimal(1337, 2) is 13.37)
+//
+// Digits beyond 8 decimal places are rounded (half away from zero)
+func NewDecimal(value int64, places int32) Decimal {
+	r := new(big.Rat).SetInt64(value)
+	if places != 0 {
+		exp := int64(places)
+		if exp < 0 {
+			exp = -exp
+		}
+		pow := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(exp), nil))
+		if places > 0 {
+			r.Quo(r, pow)
+		} else {
+			r.Mul(r, pow)
+		}
+	}
+	d, _ := decimalFromRat(r)
+	return d
+}
+
+// NewDecimalFromFloat will return the decimal of the float (using the shortest representation of the float)
+//
+// Digits beyond 8 decimal places are rounded (half away from zero)
+func NewDecimalFromFloat(value float64) Decimal {
+	d, _ := ParseDecimal(strconv.FormatFloat(value, 'f', -1, 64))
+	return d
+}
+
+// ParseDecimal will parse a plain decimal string (13.37, -0.5, +120)
+//
+// Exponents (1e-5), fractions (1/3), other bases (0x10) and digit separators (1_000) are not valid.
+// Digits beyond 8 decimal places are rounded (half away from zero)
+func ParseDecimal(value string) (Decimal, error) {
+	value = strings.TrimSpace(value)
+	if !decimalPattern.MatchString(value) {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, value)
+	}
+	r, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, value)
+	}
+	d, err := decimalFromRat(r)
+	if err != nil {
+		return Decimal{}, fmt.Errorf("%w: %q", err, value)
+	}
+	return d, nil
+}
+
+// MustParseDecimal is the same as ParseDecimal but panics on an invalid decimal (for constants and tests)
+func MustParseDecimal(value string) Decimal {
+	d, err := ParseDecimal(value)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// decimalFromRat will round the rational number to 8 decimal places
+func decimalFromRat(r *big.Rat) (Decimal, error) {
+	units := roundRat(new(big.Rat).Mul(r, new(big.Rat).SetInt64(decimalScale)))
+	if !units.IsInt64() {
+		return Decimal{}, fmt.Errorf("%w: out of range", ErrInvalidDecimal)
+	}
+	return Decimal{units: units.Int64()}, nil
+}
+
+// roundRat will round the rational number to an integer (half away from zero)
+func roundRat(r *big.Rat) *big.Int {
+	num := new(big.Int).Abs(r.Num())
+	quo, rem := new(big.Int).QuoRem(num, r.Denom(), new(big.Int))
+	if rem.Mul(rem, big.NewInt(2)).Cmp(r.Denom()) >= 0 {
+		quo.Add(quo, big.NewInt(1))
+	}
+	if r.Sign() < 0 {
+		quo.Neg(quo)
+	}
+	return quo
+}
+
+// rat will return the decimal as a rational number
+func (d Decimal) rat() *big.Rat {
+	return big.NewRat(d.units, decimalScale)
+}
+
+// Add will return d + o
+func (d Decimal) Add(o Decimal) Decimal {
+	return Decimal{units: d.units + o.units}
+}
+
+// Sub will return d - o
+func (d Decimal) Sub(o Decimal) Decimal {
+	return Decimal{units: d.units - o.units}
+}
+
+// Mul will return d * o (rounded to 8 decimal places)
+func (d Decimal) Mul(o Decimal) Decimal {
+	result, _ := decimalFromRat(new(big.Rat).Mul(d.rat(), o.rat()))
+	return result
+}
+
+// Div will return d / o (rounded to 8 decimal places), or an error if o is zero
+func (d Decimal) Div(o Decimal) (Decimal, error) {
+	if o.IsZero() {
+		return Decimal{}, fmt.Errorf("%w: division by zero", ErrInvalidDecimal)
+	}
+	return decimalFromRat(new(big.Rat).Quo(d.rat(), o.rat()))
+}
+
+// Cmp will return -1 if d < o, 0 if d == o and +1 if d > o
+func (d Decimal) Cmp(o Decimal) int {
+	switch {
+	case d.units < o.units:
+		return -1
+	case d.units > o.units:
+		return 1
+	}
+	return 0
+}
+
+// IsZero will return true if the decimal is zero
+func (d Decimal) IsZero() bool {
+	return d.units == 0
+}
+
+// Sign will return -1 if d < 0, 0 if d == 0 and +1 if d > 0
+func (d Decimal) Sign() int {
+	return d.Cmp(Decimal{})
+}
+
+// Float64 will return the (nearest) float of the decimal
+func (d Decimal) Float64() float64 {
+	f, _ := d.rat().Float64()
+	return f
+}
+
+// String will return the decimal without trailing zeros (13.37)
+func (d Decimal) String() string {
+	units := d.units
+	var sign string
+	abs := uint64(units)
+	if units < 0 {
+		sign = "-"
+		abs = uint64(-(units + 1)) + 1 // Also correct for math.MinInt64
+	}
+	integer, fraction := abs/decimalScale, abs%decimalScale
+	if fraction == 0 {
+		return sign + strconv.FormatUint(integer, 10)
+	}
+	digits := strings.TrimRight(fmt.Sprintf("%08d", fraction), "0")
+	return sign + strconv.FormatUint(integer, 10) + "." + digits
+}
+
+// MarshalJSON will marshal the decimal as a JSON number (implements json.Marshaler)
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON will unmarshal the decimal from a JSON number or string (implements json.Unmarshaler)
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	value := string(data)
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		if len(unquoted) == 0 {
+			*d = Decimal{}
+			return nil
+		}
+		parsed, err := ParseDecimal(unquoted)
+		if err != nil {
+			return err
+		}
+		*d = parsed
+		return nil
+	}
+	parsed, err := parseJSONNumber(value)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// parseJSONNumber will parse a JSON number, which can have an exponent (1e-8)
+//
+// Digits beyond 8 decimal places are rounded (half away from zero), the same as ParseDecimal
+func parseJSONNumber(value string) (Decimal, error) {
+	if !jsonNumberPattern.MatchString(value) {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, value)
+	}
+	r, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidDecimal, value)
+	}
+	d, err := decimalFromRat(r)
+	if err != nil {
+		return Decimal{}, fmt.Errorf("%w: %q", err, value)
+	}
+	return d, nil
+}
+
+// MarshalText will marshal the decimal as text (implements encoding.TextMarshaler)
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText will parse the decimal from text (implements encoding.TextUnmarshaler)
+func (d *Decimal) UnmarshalText(text []byte) error {
+	parsed, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Satoshis is an amount of satoshis (1 BSV is 100,000,000 satoshis)
+type Satoshis int64
+
+// BSV will return the amount in BSV
+func (s Satoshis) BSV() Decimal {
+	return Decimal{units: int64(s)}
+}
+
+// Money is an exact amount in a currency
+type Money struct {
+	Amount   Decimal  `json:"amount"`
+	Currency Currency `json:"currency"`
+}
+
+// NewMoney will return the amount in the currency
+func NewMoney(amount Decimal, currency Currency) Money {
+	return Money{Amount: amount, Currency: currency.normalize()}
+}
+
+// String will return the amount and the currency (13.37 usd)
+func (m Money) String() string {
+	return m.Amount.String() + " " + string(m.Currency)
+}
+
+// normalize will return the currency code in lower case (as used by the API)
+func (c Currency) normalize() Currency {
+	return Currency(strings.ToLower(strings.TrimSpace(string(c))))
+}
+
+// BalanceMoney will return the balance of the campaign in the campaign currency
+func (c *Campaign) BalanceMoney() Money {
+	return NewMoney(c.Balance, c.Currency)
+}
+
+// PayPerClick will return the pay-per-click rate in the campaign currency
+func (c *Campaign) PayPerClick() Money {
+	return NewMoney(c.PayPerClickRate, c.Currency)
+}
+
+// MarshalJSON will marshal the conversion, omitting the amount if zero (implements json.Marshaler)
+//
+// The omitempty tag has no effect on a struct (and omitzero requires Go 1.24)
+func (c Conversion) MarshalJSON() ([]byte, error) {
+	type conversion Conversion // Without the methods (no recursion)
+	if c.Amount.IsZero() {
+		return json.Marshal(&struct {
+			Amount *Decimal `json:"amount,omitempty"` // Shadows the embedded amount
+			*conversion
+		}{conversion: (*conversion)(&c)})
+	}
+	return json.Marshal((*conversion)(&c))
+}
+
+// Satoshis will convert the amount (in the rate currency) to satoshis using PriceInSatoshis
+// (PriceInSatoshis is the price of CurrencyAmount), rounded to the nearest satoshi
+func (r *Rate) Satoshis(amount Decimal) (Satoshis, error) {
+	if r.CurrencyAmount.Sign() <= 0 || r.PriceInSatoshis <= 0 {
+		return 0, fmt.Errorf("invalid rate: %s is %d satoshis", r.CurrencyAmount, r.PriceInSatoshis)
+	}
+	satoshis := new(big.Rat).Mul(amount.rat(), new(big.Rat).SetInt64(r.PriceInSatoshis))
+	satoshis.Quo(satoshis, r.CurrencyAmount.rat())
+	rounded := roundRat(satoshis)
+	if !rounded.IsInt64() {
+		return 0, fmt.Errorf("%w: out of range", ErrInvalidDecimal)
+	}
+	return Satoshis(rounded.Int64()), nil
+}
+
+// Amount will convert the satoshis to an amount in the rate currency (rounded to 8 decimal places)
+func (r *Rate) Amount(satoshis Satoshis) (Decimal, error) {
+	if r.CurrencyAmount.Sign() <= 0 || r.PriceInSatoshis <= 0 {
+		return Decimal{}, fmt.Errorf("invalid rate: %s is %d satoshis", r.CurrencyAmount, r.PriceInSatoshis)
+	}
+	amount := new(big.Rat).Mul(new(big.Rat).SetInt64(int64(satoshis)), r.CurrencyAmount.rat())
+	return decimalFromRat(amount.Quo(amount, new(big.Rat).SetInt64(r.PriceInSatoshis)))
+}
+
+// MoneyToSatoshis will convert the money to satoshis (the currency must be the rate currency)
+func (r *Rate) MoneyToSatoshis(money Money) (Satoshis, error) {
+	if money.Currency.normalize() == CurrencyBSV {
+		return Satoshis(money.Amount.units), nil
+	} else if money.Currency.normalize() != r.Currency.normalize() {
+		return 0, fmt.Errorf("currency mismatch: %s is not %s", money.Currency, r.Currency)
+	}
+	return r.Satoshis(money.Amount)
+}
@@ -0,0 +1,225 @@
+package tonicpow
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseDecimal will test the method ParseDecimal()
+func TestParseDecimal(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"0", "0"},
+		{"13.37", "13.37"},
+		{"-0.5", "-0.5"},
+		{" 120.000000 ", "120"},
+		{"+2.5", "2.5"},
+		{"007", "7"},
+		{"0.000000015", "0.00000002"},
+		{"-0.000000015", "-0.00000002"},
+		{"0.000000014", "0.00000001"},
+		{"92233720368.54775807", "92233720368.54775807"},
+	}
+	for _, test := range tests {
+		d, err := ParseDecimal(test.input)
+		require.NoError(t, err, test.input)
+		assert.Equal(t, test.expected, d.String(), test.input)
+	}
+
+	for _, invalid := range []string{
+		"", "abc", "1/3", "1.2.3", "92233720368.54775808", "0x10", "0X10", "0b11", "0o17", "1_000", "1e-5",
+		"1E5", ".5", "5.", "- 1", "1 000", "Inf", "NaN", "١٢", "0x1p-2",
+	} {
+		_, err := ParseDecimal(invalid)
+		assert.ErrorIs(t, err, ErrInvalidDecimal, invalid)
+	}
+
+	assert.Panics(t, func() { MustParseDecimal("abc") })
+	assert.Equal(t, "-92233720368.54775808", Decimal{units: math.MinInt64}.String())
+}
+
+// TestNewDecimal will test the methods NewDecimal() and NewDecimalFromFloat()
+func TestNewDecimal(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "13.37", NewDecimal(1337, 2).String())
+	assert.Equal(t, "1300", NewDecimal(13, -2).String())
+	assert.Equal(t, "0.00000001", NewDecimal(5, 9).String())
+	assert.Equal(t, "0.3", NewDecimalFromFloat(0.1+0.2).String())
+	assert.Equal(t, 13.37, NewDecimal(1337, 2).Float64())
+}
+
+// TestDecimal_Math will test the math methods of Decimal
+func TestDecimal_Math(t *testing.T) {
+	t.Parallel()
+
+	a, b := MustParseDecimal("0.1"), MustParseDecimal("0.2")
+	assert.Equal(t, MustParseDecimal("0.3"), a.Add(b))
+	assert.Equal(t, MustParseDecimal("-0.1"), a.Sub(b))
+	assert.Equal(t, MustParseDecimal("0.02"), a.Mul(b))
+
+	quotient, err := MustParseDecimal("1").Div(MustParseDecimal("3"))
+	require.NoError(t, err)
+	assert.Equal(t, "0.33333333", quotient.String())
+	_, err = a.Div(Decimal{})
+	assert.ErrorIs(t, err, ErrInvalidDecimal)
+
+	assert.Equal(t, -1, a.Cmp(b))
+	assert.Equal(t, 1, b.Cmp(a))
+	assert.Equal(t, 0, a.Cmp(MustParseDecimal("0.10")))
+	assert.Equal(t, -1, a.Sub(b).Sign())
+	assert.True(t, Decimal{}.IsZero())
+}
+
+// TestDecimal_JSON will test the JSON marshaling of Decimal (compatible with the API)
+func TestDecimal_JSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("marshal as a number", func(t *testing.T) {
+		data, err := json.Marshal(&Rate{Currency: CurrencyUSD, CurrencyAmount: NewDecimal(1, 2), PriceInSatoshis: 4200})
+		require.NoError(t, err)
+		assert.Equal(t, `{"currency":"usd","currency_amount":0.01,"price_in_satoshis":4200}`, string(data))
+	})
+
+	t.Run("unmarshal numbers, strings and null", func(t *testing.T) {
+		campaign := new(Campaign)
+		err := json.Unmarshal(
+			[]byte(`{"balance":13.37,"balance_alert_threshold":"0.5","pay_per_click_rate":null,"currency":"usd"}`),
+			campaign,
+		)
+		require.NoError(t, err)
+		assert.Equal(t, NewDecimal(1337, 2), campaign.Balance)
+		assert.Equal(t, NewDecimal(5, 1), campaign.BalanceAlertThreshold)
+		assert.True(t, campaign.PayPerClickRate.IsZero())
+		assert.Equal(t, CurrencyUSD, campaign.Currency)
+		assert.Equal(t, "13.37 usd", campaign.BalanceMoney().String())
+	})
+
+	t.Run("unmarshal numbers with an exponent", func(t *testing.T) {
+		campaign := new(Campaign)
+		err := json.Unmarshal([]byte(`{"balance":1e-8,"balance_alert_threshold":5E-7,"pay_per_click_rate":1.5e+2}`), campaign)
+		require.NoError(t, err)
+		assert.Equal(t, NewDecimal(1, 8), campaign.Balance)
+		assert.Equal(t, NewDecimal(5, 7), campaign.BalanceAlertThreshold)
+		assert.Equal(t, NewDecimal(150, 0), campaign.PayPerClickRate)
+
+		// Go encodes small floats with an exponent
+		data, err := json.Marshal(map[string]float64{"currency_amount": 0.0000005})
+		require.NoError(t, err)
+		require.Equal(t, `{"currency_amount":5e-7}`, string(data))
+
+		rate := new(Rate)
+		require.NoError(t, json.Unmarshal(data, rate))
+		assert.Equal(t, "0.0000005", rate.CurrencyAmount.String())
+	})
+
+	t.Run("invalid decimal", func(t *testing.T) {
+		err := json.Unmarshal([]byte(`{"payout_rate":"abc"}`), new(Goal))
+		assert.ErrorIs(t, err, ErrInvalidDecimal)
+
+		for _, invalid := range []string{`"0x10"`, `"0b11"`, `"1_000"`, `"1/3"`, `"1e-5"`, `1e1000000`} {
+			err = json.Unmarshal([]byte(`{"payout_rate":`+invalid+`}`), new(Goal))
+			assert.ErrorIs(t, err, ErrInvalidDecimal, invalid)
+		}
+	})
+
+	t.Run("conversion amount is omitted if zero", func(t *testing.T) {
+		data, err := json.Marshal(&Conversion{GoalID: testGoalID})
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), `"amount"`)
+		assert.Contains(t, string(data), fmt.Sprintf(`"goal_id":%d`, testGoalID))
+
+		data, err = json.Marshal(&Conversion{Amount: NewDecimal(5, 1), GoalID: testGoalID})
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"amount":0.5`)
+	})
+
+	t.Run("text", func(t *testing.T) {
+		d := new(Decimal)
+		require.NoError(t, d.UnmarshalText([]byte("1.5")))
+		text, err := d.MarshalText()
+		require.NoError(t, err)
+		assert.Equal(t, "1.5", string(text))
+		assert.Error(t, d.UnmarshalText([]byte("x")))
+	})
+}
+
+// TestRate_Conversions will test converting amounts to and from satoshis
+func TestRate_Conversions(t *testing.T) {
+	t.Parallel()
+
+	rate := newTestRate() // 0.01 usd is 4200 sats
+
+	satoshis, err := rate.Satoshis(NewDecimal(1, 0))
+	require.NoError(t, err)
+	assert.Equal(t, Satoshis(420000), satoshis)
+
+	satoshis, err = rate.Satoshis(MustParseDecimal("0.000001"))
+	require.NoError(t, err)
+	assert.Equal(t, Satoshis(0), satoshis)
+
+	amount, err := rate.Amount(Satoshis(420000))
+	require.NoError(t, err)
+	assert.Equal(t, "1", amount.String())
+
+	satoshis, err = rate.MoneyToSatoshis(NewMoney(NewDecimal(2, 0), "USD"))
+	require.NoError(t, err)
+	assert.Equal(t, Satoshis(840000), satoshis)
+
+	satoshis, err = rate.MoneyToSatoshis(NewMoney(NewDecimal(1, 0), CurrencyBSV))
+	require.NoError(t, err)
+	assert.Equal(t, Satoshis(SatoshisPerBitcoin), satoshis)
+	assert.Equal(t, "1", satoshis.BSV().String())
+
+	_, err = rate.MoneyToSatoshis(NewMoney(NewDecimal(1, 0), CurrencyEUR))
+	assert.Error(t, err)
+
+	_, err = (&Rate{Currency: CurrencyUSD}).Satoshis(NewDecimal(1, 0))
+	assert.Error(t, err)
+	_, err = (&Rate{Currency: CurrencyUSD}).Amount(1)
+	assert.Error(t, err)
+}
+
+// TestConversionOptions_payload will test the purchase amount in the conversion payload
+func TestConversionOptions_payload(t *testing.T) {
+	t.Parallel()
+
+	options := new(conversionOptions)
+	WithPurchaseAmountDecimal(MustParseDecimal("120.50"))(options)
+	assert.Equal(t, "120.5", options.payload()[fieldAmount])
+
+	WithPurchaseAmount(120.25)(options)
+	assert.Equal(t, "120.25", options.payload()[fieldAmount])
+
+	WithPurchaseAmountDecimal(Decimal{})(options)
+	_, ok := options.payload()[fieldAmount]
+	assert.False(t, ok)
+}
+
+// ExampleRate_Satoshis example using Satoshis()
+func ExampleRate_Satoshis() {
+	rate := &Rate{Currency: CurrencyUSD, CurrencyAmount: NewDecimal(1, 0), PriceInSatoshis: 2000000}
+	satoshis, err := rate.Satoshis(MustParseDecimal("12.34"))
+	if err != nil {
+		fmt.Printf("error converting amount: " + err.Error())
+		return
+	}
+	fmt.Printf("12.34 usd is %d sats (%s bsv)", satoshis, satoshis.BSV())
+	// Output:12.34 usd is 24680000 sats (0.2468 bsv)
+}
+
+// BenchmarkParseDecimal benchmarks the method ParseDecimal()
+func BenchmarkParseDecimal(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = ParseDecimal("13.37")
+	}
+}
@@ -0,0 +1,481 @@
+package tonicpow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultOutboxPollInterval is the default interval between deliveries when started (see Outbox.Start)
+	defaultOutboxPollInterval = 30 * time.Second
+
+	// Outbox retry defaults (see WithOutboxRetryPolicy)
+	defaultOutboxBaseDelay  = 30 * time.Second // Delay before the first redelivery (doubles every attempt)
+	defaultOutboxMaxDelay   = time.Hour        // Maximum delay between deliveries
+	defaultOutboxMaxRetries = 10               // Maximum number of redeliveries before the entry is dead
+)
+
+// ErrOutboxEntryNotFound is returned when the outbox has no entry with the ID
+var ErrOutboxEntryNotFound = errors.New("outbox entry not found")
+
+// OutboxStatus is the delivery status of an outbox entry
+type OutboxStatus string
+
+// Outbox statuses
+const (
+	OutboxStatusDead      OutboxStatus = "dead"      // Failed permanently (or too many times), see Outbox.Replay
+	OutboxStatusDelivered OutboxStatus = "delivered" // The conversion was created
+	OutboxStatusPending   OutboxStatus = "pending"   // Waiting to be delivered
+)
+
+// ConversionRequest is a conversion that can be stored and created later (see Outbox)
+//
+// It holds the same values as the ConversionOps used with CreateConversion
+type ConversionRequest struct {
+	CustomDimensions string  `json:"custom_dimensions,omitempty"`
+	DelayInMinutes   uint64  `json:"delay_in_minutes,omitempty"`
+	GoalID           uint64  `json:"goal_id,omitempty"`
+	GoalName         string  `json:"goal_name,omitempty"`
+	IdempotencyKey   string  `json:"idempotency_key,omitempty"`
+	PurchaseAmount   Decimal `json:"purchase_amount"`
+	ShortCode        string  `json:"short_code,omitempty"`
+	TncpwSession     string  `json:"tncpw_session,omitempty"`
+	TwitterID        string  `json:"twitter_id,omitempty"`
+	UserID           uint64  `json:"user_id,omitempty"`
+}
+
+// Options will return the conversion options of the request (see CreateConversion)
+func (r *ConversionRequest) Options() []ConversionOps {
+	return []ConversionOps{
+		WithGoalID(r.GoalID),
+		WithGoalName(r.GoalName),
+		WithTncpwSession(r.TncpwSession),
+		WithUserID(r.UserID),
+		WithShortCode(r.ShortCode),
+		WithTwitterID(r.TwitterID),
+		WithDelay(r.DelayInMinutes),
+		WithPurchaseAmountDecimal(r.PurchaseAmount),
+		WithCustomDimensions(r.CustomDimensions),
+		WithConversionIdempotencyKey(r.IdempotencyKey),
+	}
+}
+
+// validate will check the request before it is queued
+func (r *ConversionRequest) validate() error {
+	options := new(conversionOptions)
+	for _, opt := range r.Options() {
+		opt(options)
+	}
+	return options.validate()
+}
+
+// OutboxEntry is a queued conversion and its delivery status
+type OutboxEntry struct {
+	Attempts      int                `json:"attempts"`                // Number of delivery attempts
+	ConversionID  uint64             `json:"conversion_id,omitempty"` // ID of the created conversion (once delivered)
+	CreatedAt     time.Time          `json:"created_at"`              // When the conversion was queued
+	DeliveredAt   time.Time          `json:"delivered_at"`            // When the conversion was created
+	ID            string             `json:"id"`                      // Entry ID (the idempotency key of the conversion)
+	LastError     string             `json:"last_error,omitempty"`    // Error of the last failed attempt
+	NextAttemptAt time.Time          `json:"next_attempt_at"`         // When the next delivery is due (pending entries)
+	Request       *ConversionRequest `json:"request"`                 // The conversion
+	Status        OutboxStatus       `json:"status"`                  // Delivery status
+}
+
+// OutboxStats is the number of outbox entries by status
+type OutboxStats struct {
+	Dead      int `json:"dead"`
+	Delivered int `json:"delivered"`
+	Pending   int `json:"pending"`
+}
+
+// OutboxOps allow functional options to be supplied to NewOutbox
+type OutboxOps func(o *Outbox)
+
+// WithOutboxPollInterval will set the interval between deliveries when started (default is 30 seconds)
+func WithOutboxPollInterval(interval time.Duration) OutboxOps {
+	return func(o *Outbox) {
+		if interval > 0 {
+			o.pollInterval = interval
+		}
+	}
+}
+
+// WithOutboxRetryPolicy will set the policy deciding when a failed delivery is attempted again
+// (default is a BackoffPolicy with 10 retries, from 30 seconds up to an hour apart)
+//
+// Entries that are not retried (permanent errors or too many attempts) become dead letters
+func WithOutboxRetryPolicy(policy RetryPolicy) OutboxOps {
+	return func(o *Outbox) {
+		if policy != nil {
+			o.retryPolicy = policy
+		}
+	}
+}
+
+// withOutboxClock will set the clock of the outbox (for tests)
+func withOutboxClock(now func() time.Time) OutboxOps {
+	return func(o *Outbox) {
+		o.now = now
+	}
+}
+
+// Outbox is a durable queue of conversions that are created asynchronously (using CreateConversion)
+//
+// Every entry is persisted in the OutboxStore before it is delivered, so queued conversions
+// survive process restarts and API outages. Every conversion has an idempotency key,
+// so a conversion is never paid out twice when a delivery is repeated.
+//
+// An Outbox is safe for concurrent use
+type Outbox struct {
+	delivering   sync.Mutex
+	done         chan struct{}
+	entries      map[string]*OutboxEntry
+	mu           sync.RWMutex
+	now          func() time.Time
+	pollInterval time.Duration
+	retryPolicy  RetryPolicy
+	service      ConversionService
+	stop         context.CancelFunc
+	store        OutboxStore
+	wake         chan struct{}
+}
+
+// NewOutbox will return an outbox delivering the conversions using the service (the Client),
+// the entries already in the store are loaded (and delivered on the next Flush)
+//
+// No conversions are delivered until Flush or Start is called
+func NewOutbox(service ConversionService, store OutboxStore, opts ...OutboxOps) (*Outbox, error) {
+	o := &Outbox{
+		entries:      make(map[string]*OutboxEntry),
+		now:          time.Now,
+		pollInterval: defaultOutboxPollInterval,
+		retryPolicy: &BackoffPolicy{
+			BaseDelay:     defaultOutboxBaseDelay,
+			MaxDelay:      defaultOutboxMaxDelay,
+			MaxRetries:    defaultOutboxMaxRetries,
+			MaxRetryAfter: defaultOutboxMaxDelay,
+		},
+		service: service,
+		store:   store,
+		wake:    make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	entries, err := store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the outbox: %w", err)
+	}
+	for _, entry := range entries {
+		o.entries[entry.ID] = entry
+	}
+	return o, nil
+}
+
+// Enqueue will persist the conversion and queue it for delivery
+//
+// An idempotency key is generated if not set, it is also the ID of the entry.
+// Enqueuing a conversion with the same idempotency key again returns the existing entry.
+func (o *Outbox) Enqueue(request *ConversionRequest) (*OutboxEntry, error) {
+	if request == nil {
+		return nil, errors.New("missing conversion request")
+	} else if err := request.validate(); err != nil {
+		return nil, err
+	}
+
+	queued := *request
+	if len(queued.IdempotencyKey) == 0 {
+		key, err := newIdempotencyKey()
+		if err != nil {
+			return nil, err
+		}
+		queued.IdempotencyKey = key
+	}
+
+	o.mu.Lock()
+	if existing, ok := o.entries[queued.IdempotencyKey]; ok {
+		o.mu.Unlock()
+		return existing.clone(), nil
+	}
+	now := o.now()
+	entry := &OutboxEntry{
+		CreatedAt:     now,
+		ID:            queued.IdempotencyKey,
+		NextAttemptAt: now,
+		Request:       &queued,
+		Status:        OutboxStatusPending,
+	}
+	if err := o.store.Save(entry); err != nil {
+		o.mu.Unlock()
+		return nil, fmt.Errorf("failed to save the outbox entry: %w", err)
+	}
+	o.entries[entry.ID] = entry
+	o.mu.Unlock()
+
+	o.notify() // Deliver now (if started)
+	return entry.clone(), nil
+}
+
+// Flush will deliver every pending conversion that is due (oldest first)
+//
+// Failed deliveries are recorded on the entries, the error is only returned
+// if the context is done or the store failed to save an entry
+func (o *Outbox) Flush(ctx context.Context) error {
+	o.delivering.Lock()
+	defer o.delivering.Unlock()
+
+	var errs []error
+	for _, entry := range o.due() {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		if err := o.deliver(ctx, entry); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// due will return the pending entries that are due for delivery (oldest first)
+func (o *Outbox) due() []*OutboxEntry {
+	now := o.now()
+	var entries []*OutboxEntry
+	for _, entry := range o.Entries(OutboxStatusPending) {
+		if !entry.NextAttemptAt.After(now) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// deliver will create the conversion of the entry and save the result
+func (o *Outbox) deliver(ctx context.Context, entry *OutboxEntry) error {
+	conversion, response, err := o.service.CreateConversionWithContext(ctx, entry.Request.Options()...)
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err() // Stopped (not a failed delivery)
+	}
+
+	entry.Attempts++
+	if err == nil {
+		entry.Status, entry.DeliveredAt, entry.LastError = OutboxStatusDelivered, o.now(), ""
+		if conversion != nil {
+			entry.ConversionID = conversion.ID
+		}
+	} else {
+		entry.LastError = err.Error()
+		if delay, retry := o.retryPolicy.Backoff(newOutboxAttempt(entry.Attempts, response, err)); retry {
+			entry.NextAttemptAt = o.now().Add(delay)
+		} else {
+			entry.Status = OutboxStatusDead
+		}
+	}
+	return o.save(entry)
+}
+
+// newOutboxAttempt will return the failed attempt used by the retry policy
+//
+// Only transport errors, 429 and 5xx responses are retried (API errors such as 422 are permanent),
+// the headers of the response are used for the Retry-After delay
+func newOutboxAttempt(attempt int, response *StandardResponse, err error) *RetryAttempt {
+	retryAttempt := &RetryAttempt{Attempt: attempt, Idempotent: true, Method: http.MethodPost}
+	if response != nil {
+		retryAttempt.Header = response.Header
+	}
+	var apiError *Error
+	if errors.As(err, &apiError) {
+		retryAttempt.StatusCode = apiError.statusCode()
+	} else {
+		retryAttempt.Err = err
+	}
+	return retryAttempt
+}
+
+// save will persist the entry and replace the cached entry
+func (o *Outbox) save(entry *OutboxEntry) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if _, ok := o.entries[entry.ID]; !ok {
+		return nil // Removed while delivering
+	}
+	if err := o.store.Save(entry); err != nil {
+		return fmt.Errorf("failed to save the outbox entry %s: %w", entry.ID, err)
+	}
+	o.entries[entry.ID] = entry
+	return nil
+}
+
+// Start will deliver the due conversions now, on every poll interval and when a conversion is queued,
+// until Stop is called or the context is done
+func (o *Outbox) Start(ctx context.Context) {
+	o.Stop()
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	o.mu.Lock()
+	o.stop, o.done = cancel, done
+	o.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(o.pollInterval)
+		defer ticker.Stop()
+		for {
+			_ = o.Flush(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-o.wake:
+			}
+		}
+	}()
+}
+
+// Stop will stop delivering conversions (waiting for the current delivery)
+func (o *Outbox) Stop() {
+	o.mu.Lock()
+	stop, done := o.stop, o.done
+	o.stop, o.done = nil, nil
+	o.mu.Unlock()
+
+	if stop != nil {
+		stop()
+		<-done
+	}
+}
+
+// notify will wake up the delivery loop (if started)
+func (o *Outbox) notify() {
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Entry will return the entry with the ID (the idempotency key of the conversion)
+func (o *Outbox) Entry(id string) (*OutboxEntry, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	entry, ok := o.entries[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrOutboxEntryNotFound, id)
+	}
+	return entry.clone(), nil
+}
+
+// Entries will return the entries with the status, or all entries if empty (oldest first)
+func (o *Outbox) Entries(status OutboxStatus) []*OutboxEntry {
+	o.mu.RLock()
+	entries := make([]*OutboxEntry, 0, len(o.entries))
+	for _, entry := range o.entries {
+		if len(status) == 0 || entry.Status == status {
+			entries = append(entries, entry.clone())
+		}
+	}
+	o.mu.RUnlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].CreatedAt.Equal(entries[j].CreatedAt) {
+			return entries[i].ID < entries[j].ID
+		}
+		return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+	})
+	return entries
+}
+
+// DeadLetters will return the conversions that failed permanently (oldest first), see Replay
+func (o *Outbox) DeadLetters() []*OutboxEntry {
+	return o.Entries(OutboxStatusDead)
+}
+
+// Stats will return the number of entries by status
+func (o *Outbox) Stats() OutboxStats {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	var stats OutboxStats
+	for _, entry := range o.entries {
+		switch entry.Status {
+		case OutboxStatusDead:
+			stats.Dead++
+		case OutboxStatusDelivered:
+			stats.Delivered++
+		case OutboxStatusPending:
+			stats.Pending++
+		}
+	}
+	return stats
+}
+
+// Replay will queue the dead letters with the IDs for delivery again (all dead letters if no IDs are given),
+// the attempts are reset. Returns the number of entries queued.
+func (o *Outbox) Replay(ids ...string) (int, error) {
+	if len(ids) == 0 {
+		for _, entry := range o.DeadLetters() {
+			ids = append(ids, entry.ID)
+		}
+	}
+
+	o.mu.Lock()
+	var replayed int
+	var errs []error
+	for _, id := range ids {
+		entry, ok := o.entries[id]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrOutboxEntryNotFound, id))
+			continue
+		} else if entry.Status != OutboxStatusDead {
+			continue
+		}
+		replay := entry.clone()
+		replay.Attempts, replay.NextAttemptAt, replay.Status = 0, o.now(), OutboxStatusPending
+		if err := o.store.Save(replay); err != nil {
+			errs = append(errs, fmt.Errorf("failed to save the outbox entry %s: %w", id, err))
+			continue
+		}
+		o.entries[id] = replay
+		replayed++
+	}
+	o.mu.Unlock()
+
+	if replayed > 0 {
+		o.notify()
+	}
+	return replayed, errors.Join(errs...)
+}
+
+// Prune will remove the delivered entries (delivered before the time) from the outbox and the store,
+// returns the number of entries removed
+func (o *Outbox) Prune(deliveredBefore time.Time) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var removed int
+	for id, entry := range o.entries {
+		if entry.Status != OutboxStatusDelivered || !entry.DeliveredAt.Before(deliveredBefore) {
+			continue
+		}
+		if err := o.store.Delete(id); err != nil {
+			return removed, fmt.Errorf("failed to delete the outbox entry %s: %w", id, err)
+		}
+		delete(o.entries, id)
+		removed++
+	}
+	return removed, nil
+}
+
+// clone will return a copy of the entry (safe to modify)
+func (e *OutboxEntry) clone() *OutboxEntry {
+	entry := *e
+	if e.Request != nil {
+		request := *e.Request
+		entry.Request = &request
+	}
+	return &entry
+}
@@ -0,0 +1,172 @@
+package tonicpow
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// outboxFileExtension is the extension of the entry files (see FileOutboxStore)
+const outboxFileExtension = ".json"
+
+// OutboxStore persists the entries of an Outbox (implement it to use a database, BoltDB, etc.)
+//
+// The outbox serializes its calls to the store
+type OutboxStore interface {
+	// Delete will remove the entry (no error if the entry does not exist)
+	Delete(id string) error
+
+	// Load will return all the entries
+	Load() ([]*OutboxEntry, error)
+
+	// Save will add or replace the entry (the entry must be durable once Save returns)
+	Save(entry *OutboxEntry) error
+}
+
+// MemoryOutboxStore is an in-memory OutboxStore (entries do not survive restarts, useful for tests)
+type MemoryOutboxStore struct {
+	entries map[string]*OutboxEntry
+	mu      sync.Mutex
+}
+
+// NewMemoryOutboxStore will return an empty in-memory store
+func NewMemoryOutboxStore() *MemoryOutboxStore {
+	return &MemoryOutboxStore{entries: make(map[string]*OutboxEntry)}
+}
+
+// Delete will remove the entry (implements OutboxStore)
+func (s *MemoryOutboxStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, id)
+	return nil
+}
+
+// Load will return all the entries (implements OutboxStore)
+func (s *MemoryOutboxStore) Load() ([]*OutboxEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries := make([]*OutboxEntry, 0, len(s.entries))
+	for _, entry := range s.entries {
+		entries = append(entries, entry.clone())
+	}
+	return entries, nil
+}
+
+// Save will add or replace the entry (implements OutboxStore)
+func (s *MemoryOutboxStore) Save(entry *OutboxEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[entry.ID] = entry.clone()
+	return nil
+}
+
+// FileOutboxStore is an OutboxStore keeping every entry in a JSON file in a directory
+//
+// Entries are written to a temporary file and renamed, so an entry is never partially written
+type FileOutboxStore struct {
+	dir string
+}
+
+// NewFileOutboxStore will return a store using the directory (created if it does not exist)
+func NewFileOutboxStore(dir string) (*FileOutboxStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create the outbox directory: %w", err)
+	}
+	return &FileOutboxStore{dir: dir}, nil
+}
+
+// Delete will remove the entry file (implements OutboxStore)
+func (s *FileOutboxStore) Delete(id string) error {
+	if err := os.Remove(s.path(id)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Load will read all the entry files (implements OutboxStore)
+func (s *FileOutboxStore) Load() ([]*OutboxEntry, error) {
+	files, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*OutboxEntry
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), outboxFileExtension) {
+			continue
+		}
+		var data []byte
+		if data, err = os.ReadFile(filepath.Join(s.dir, file.Name())); err != nil {
+			return nil, err
+		}
+		entry := new(OutboxEntry)
+		if err = json.Unmarshal(data, entry); err != nil {
+			return nil, fmt.Errorf("invalid outbox entry %s: %w", file.Name(), err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Save will write the entry file (implements OutboxStore)
+func (s *FileOutboxStore) Save(entry *OutboxEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	// Write (and sync) a temporary file, then replace the entry file (and sync the directory)
+	var file *os.File
+	if file, err = os.CreateTemp(s.dir, "entry-*.tmp"); err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(file.Name())
+	}()
+	if _, err = file.Write(data); err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if err = os.Rename(file.Name(), s.path(entry.ID)); err != nil {
+		return err
+	}
+	return syncDir(s.dir)
+}
+
+// syncDir will flush the directory (so a renamed file survives a crash)
+//
+// Directories cannot be synced on Windows (renames are flushed by the file system)
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	err = d.Sync()
+	if closeErr := d.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// path will return the file path of the entry (the ID is hashed, it can be any idempotency key)
+func (s *FileOutboxStore) path(id string) string {
+	hash := sha256.Sum256([]byte(id))
+	return filepath.Join(s.dir, hex.EncodeToString(hash[:])+outboxFileExtension)
+}
@@ -0,0 +1,87 @@
+package tonicpow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestOutboxEntry will return a pending entry
+func newTestOutboxEntry(id string) *OutboxEntry {
+	return &OutboxEntry{
+		CreatedAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		ID:        id,
+		Request:   newTestConversionRequest(id),
+		Status:    OutboxStatusPending,
+	}
+}
+
+// TestOutboxStores will test the outbox stores
+func TestOutboxStores(t *testing.T) {
+	t.Parallel()
+
+	stores := map[string]func(t *testing.T) OutboxStore{
+		"memory": func(_ *testing.T) OutboxStore { return NewMemoryOutboxStore() },
+		"file": func(t *testing.T) OutboxStore {
+			store, err := NewFileOutboxStore(filepath.Join(t.TempDir(), "outbox"))
+			require.NoError(t, err)
+			return store
+		},
+	}
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			store := newStore(t)
+
+			entries, err := store.Load()
+			require.NoError(t, err)
+			assert.Empty(t, entries)
+
+			require.NoError(t, store.Save(newTestOutboxEntry("order-1")))
+			require.NoError(t, store.Save(newTestOutboxEntry("orders/2")))
+
+			updated := newTestOutboxEntry("order-1")
+			updated.Attempts, updated.Status = 1, OutboxStatusDead
+			require.NoError(t, store.Save(updated))
+
+			entries, err = store.Load()
+			require.NoError(t, err)
+			require.Len(t, entries, 2)
+			for _, entry := range entries {
+				if entry.ID == "order-1" {
+					assert.Equal(t, updated, entry)
+				} else {
+					assert.Equal(t, newTestOutboxEntry("orders/2"), entry)
+				}
+			}
+
+			require.NoError(t, store.Delete("order-1"))
+			require.NoError(t, store.Delete("unknown"))
+			entries, err = store.Load()
+			require.NoError(t, err)
+			require.Len(t, entries, 1)
+			assert.Equal(t, "orders/2", entries[0].ID)
+		})
+	}
+}
+
+// TestFileOutboxStore_Load will test loading invalid files
+func TestFileOutboxStore_Load(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	store, err := NewFileOutboxStore(dir)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0o600))
+	entries, err := store.Load()
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "invalid.json"), []byte("{"), 0o600))
+	_, err = store.Load()
+	require.Error(t, err)
+}
@@ -0,0 +1,390 @@
+package tonicpow
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testConversionService is a ConversionService that creates conversions (or fails with the queued errors)
+type testConversionService struct {
+	ConversionService
+	failures []error
+	header   http.Header // Headers of the failed responses (API errors)
+	keys     []string
+	mu       sync.Mutex
+}
+
+// CreateConversionWithContext will return the next queued error, or a new conversion
+func (s *testConversionService) CreateConversionWithContext(ctx context.Context,
+	opts ...ConversionOps) (*Conversion, *StandardResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	options := new(conversionOptions)
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = append(s.keys, options.idempotencyKey)
+	if len(s.failures) > 0 {
+		err := s.failures[0]
+		s.failures = s.failures[1:]
+		var apiError *Error
+		if errors.As(err, &apiError) {
+			return nil, &StandardResponse{Error: apiError, Header: s.header, StatusCode: apiError.StatusCode}, err
+		}
+		return nil, nil, err
+	}
+	return &Conversion{GoalID: options.goalID, ID: uint64(len(s.keys))}, nil, nil
+}
+
+// fail will queue errors returned by the next conversions
+func (s *testConversionService) fail(errs ...error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, errs...)
+}
+
+// idempotencyKeys will return the idempotency keys of all the requests
+func (s *testConversionService) idempotencyKeys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.keys...)
+}
+
+// testClock is a clock that only moves when advanced
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// Now will return the current time of the clock
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance will move the clock forward
+func (c *testClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// newTestConversionRequest will return a valid conversion request
+func newTestConversionRequest(idempotencyKey string) *ConversionRequest {
+	return &ConversionRequest{
+		GoalID:         testGoalID,
+		IdempotencyKey: idempotencyKey,
+		PurchaseAmount: NewDecimal(1999, 2),
+		TncpwSession:   testTncpwSession,
+	}
+}
+
+// newTestOutbox will return an outbox using an in-memory store, a test service and a test clock
+func newTestOutbox(t *testing.T, opts ...OutboxOps) (*Outbox, *testConversionService, *testClock) {
+	service := new(testConversionService)
+	clock := &testClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	outbox, err := NewOutbox(service, NewMemoryOutboxStore(), append([]OutboxOps{
+		withOutboxClock(clock.Now),
+		WithOutboxRetryPolicy(&BackoffPolicy{BaseDelay: time.Minute, MaxDelay: time.Minute, MaxRetries: 2}),
+	}, opts...)...)
+	require.NoError(t, err)
+	return outbox, service, clock
+}
+
+// TestOutbox_Enqueue will test queueing conversions
+func TestOutbox_Enqueue(t *testing.T) {
+	t.Parallel()
+
+	t.Run("queue and deliver", func(t *testing.T) {
+		outbox, service, _ := newTestOutbox(t)
+
+		entry, err := outbox.Enqueue(newTestConversionRequest("order-1"))
+		require.NoError(t, err)
+		assert.Equal(t, "order-1", entry.ID)
+		assert.Equal(t, OutboxStatusPending, entry.Status)
+		assert.Equal(t, OutboxStats{Pending: 1}, outbox.Stats())
+
+		require.NoError(t, outbox.Flush(context.Background()))
+		assert.Equal(t, []string{"order-1"}, service.idempotencyKeys())
+
+		entry, err = outbox.Entry("order-1")
+		require.NoError(t, err)
+		assert.Equal(t, OutboxStatusDelivered, entry.Status)
+		assert.Equal(t, uint64(1), entry.ConversionID)
+		assert.Equal(t, 1, entry.Attempts)
+		assert.False(t, entry.DeliveredAt.IsZero())
+		assert.Equal(t, OutboxStats{Delivered: 1}, outbox.Stats())
+
+		// Delivered entries are not delivered again
+		require.NoError(t, outbox.Flush(context.Background()))
+		assert.Len(t, service.idempotencyKeys(), 1)
+	})
+
+	t.Run("idempotency key is generated", func(t *testing.T) {
+		outbox, service, _ := newTestOutbox(t)
+
+		entry, err := outbox.Enqueue(newTestConversionRequest(""))
+		require.NoError(t, err)
+		assert.Len(t, entry.ID, 36)
+		assert.Equal(t, entry.ID, entry.Request.IdempotencyKey)
+
+		require.NoError(t, outbox.Flush(context.Background()))
+		assert.Equal(t, []string{entry.ID}, service.idempotencyKeys())
+	})
+
+	t.Run("duplicate idempotency key", func(t *testing.T) {
+		outbox, _, _ := newTestOutbox(t)
+
+		_, err := outbox.Enqueue(newTestConversionRequest("order-1"))
+		require.NoError(t, err)
+		request := newTestConversionRequest("order-1")
+		request.GoalID = 999
+		entry, err := outbox.Enqueue(request)
+		require.NoError(t, err)
+		assert.Equal(t, testGoalID, entry.Request.GoalID)
+		assert.Len(t, outbox.Entries(""), 1)
+	})
+
+	t.Run("invalid conversion", func(t *testing.T) {
+		outbox, _, _ := newTestOutbox(t)
+
+		_, err := outbox.Enqueue(&ConversionRequest{GoalID: testGoalID})
+		require.Error(t, err)
+		_, err = outbox.Enqueue(nil)
+		require.Error(t, err)
+		assert.Empty(t, outbox.Entries(""))
+	})
+}
+
+// TestOutbox_Flush will test delivering the queued conversions
+func TestOutbox_Flush(t *testing.T) {
+	t.Parallel()
+
+	t.Run("transient errors are retried", func(t *testing.T) {
+		outbox, service, clock := newTestOutbox(t)
+		service.fail(&Error{StatusCode: http.StatusServiceUnavailable}, errors.New("connection refused"))
+
+		_, err := outbox.Enqueue(newTestConversionRequest("order-1"))
+		require.NoError(t, err)
+		require.NoError(t, outbox.Flush(context.Background()))
+
+		entry, err := outbox.Entry("order-1")
+		require.NoError(t, err)
+		assert.Equal(t, OutboxStatusPending, entry.Status)
+		assert.Equal(t, 1, entry.Attempts)
+		assert.NotEmpty(t, entry.LastError)
+		assert.True(t, entry.NextAttemptAt.After(clock.Now()))
+
+		// Not due yet
+		require.NoError(t, outbox.Flush(context.Background()))
+		assert.Len(t, service.idempotencyKeys(), 1)
+
+		clock.Advance(2 * time.Minute)
+		require.NoError(t, outbox.Flush(context.Background()))
+		clock.Advance(2 * time.Minute)
+		require.NoError(t, outbox.Flush(context.Background()))
+
+		entry, err = outbox.Entry("order-1")
+		require.NoError(t, err)
+		assert.Equal(t, OutboxStatusDelivered, entry.Status)
+		assert.Equal(t, 3, entry.Attempts)
+		assert.Empty(t, entry.LastError)
+		assert.Equal(t, []string{"order-1", "order-1", "order-1"}, service.idempotencyKeys())
+	})
+
+	t.Run("retry after of the response is used", func(t *testing.T) {
+		outbox, service, clock := newTestOutbox(t, WithOutboxRetryPolicy(&BackoffPolicy{
+			BaseDelay: time.Minute, MaxDelay: time.Minute, MaxRetries: 2, MaxRetryAfter: time.Hour,
+		}))
+		service.header = http.Header{"Retry-After": []string{"1800"}}
+		service.fail(&Error{StatusCode: http.StatusTooManyRequests})
+
+		_, err := outbox.Enqueue(newTestConversionRequest("order-1"))
+		require.NoError(t, err)
+		require.NoError(t, outbox.Flush(context.Background()))
+
+		entry, err := outbox.Entry("order-1")
+		require.NoError(t, err)
+		assert.Equal(t, OutboxStatusPending, entry.Status)
+		assert.Equal(t, clock.Now().Add(30*time.Minute), entry.NextAttemptAt)
+	})
+
+	t.Run("permanent errors are dead letters", func(t *testing.T) {
+		outbox, service, _ := newTestOutbox(t)
+		service.fail(&Error{Message: "goal is not active", StatusCode: http.StatusUnprocessableEntity})
+
+		_, err := outbox.Enqueue(newTestConversionRequest("order-1"))
+		require.NoError(t, err)
+		_, err = outbox.Enqueue(newTestConversionRequest("order-2"))
+		require.NoError(t, err)
+		require.NoError(t, outbox.Flush(context.Background()))
+
+		dead := outbox.DeadLetters()
+		require.Len(t, dead, 1)
+		assert.Equal(t, "order-1", dead[0].ID)
+		assert.Equal(t, "goal is not active", dead[0].LastError)
+		assert.Equal(t, OutboxStats{Dead: 1, Delivered: 1}, outbox.Stats())
+	})
+
+	t.Run("too many attempts", func(t *testing.T) {
+		outbox, service, clock := newTestOutbox(t)
+		service.fail(&Error{StatusCode: http.StatusBadGateway}, &Error{StatusCode: http.StatusBadGateway},
+			&Error{StatusCode: http.StatusBadGateway})
+
+		_, err := outbox.Enqueue(newTestConversionRequest("order-1"))
+		require.NoError(t, err)
+		for i := 0; i < 5; i++ {
+			require.NoError(t, outbox.Flush(context.Background()))
+			clock.Advance(2 * time.Minute)
+		}
+
+		entry, err := outbox.Entry("order-1")
+		require.NoError(t, err)
+		assert.Equal(t, OutboxStatusDead, entry.Status)
+		assert.Equal(t, 3, entry.Attempts)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		outbox, _, _ := newTestOutbox(t)
+
+		_, err := outbox.Enqueue(newTestConversionRequest("order-1"))
+		require.NoError(t, err)
+		require.ErrorIs(t, outbox.Flush(newCanceledContext()), context.Canceled)
+
+		entry, err := outbox.Entry("order-1")
+		require.NoError(t, err)
+		assert.Equal(t, OutboxStatusPending, entry.Status)
+		assert.Equal(t, 0, entry.Attempts)
+	})
+}
+
+// TestOutbox_Replay will test replaying dead letters
+func TestOutbox_Replay(t *testing.T) {
+	t.Parallel()
+
+	outbox, service, _ := newTestOutbox(t)
+	service.fail(&Error{StatusCode: http.StatusBadRequest}, &Error{StatusCode: http.StatusBadRequest})
+
+	_, err := outbox.Enqueue(newTestConversionRequest("order-1"))
+	require.NoError(t, err)
+	_, err = outbox.Enqueue(newTestConversionRequest("order-2"))
+	require.NoError(t, err)
+	require.NoError(t, outbox.Flush(context.Background()))
+	require.Len(t, outbox.DeadLetters(), 2)
+
+	replayed, err := outbox.Replay("order-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, replayed)
+
+	entry, err := outbox.Entry("order-1")
+	require.NoError(t, err)
+	assert.Equal(t, OutboxStatusPending, entry.Status)
+	assert.Equal(t, 0, entry.Attempts)
+
+	// Replay all the dead letters (and unknown entries)
+	replayed, err = outbox.Replay()
+	require.NoError(t, err)
+	assert.Equal(t, 1, replayed)
+	_, err = outbox.Replay("unknown")
+	assert.ErrorIs(t, err, ErrOutboxEntryNotFound)
+
+	require.NoError(t, outbox.Flush(context.Background()))
+	assert.Equal(t, OutboxStats{Delivered: 2}, outbox.Stats())
+}
+
+// TestOutbox_Prune will test removing delivered entries
+func TestOutbox_Prune(t *testing.T) {
+	t.Parallel()
+
+	store := NewMemoryOutboxStore()
+	clock := &testClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	outbox, err := NewOutbox(new(testConversionService), store, withOutboxClock(clock.Now))
+	require.NoError(t, err)
+
+	_, err = outbox.Enqueue(newTestConversionRequest("order-1"))
+	require.NoError(t, err)
+	require.NoError(t, outbox.Flush(context.Background()))
+	clock.Advance(time.Hour)
+	_, err = outbox.Enqueue(newTestConversionRequest("order-2"))
+	require.NoError(t, err)
+
+	removed, err := outbox.Prune(clock.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	_, err = outbox.Entry("order-1")
+	assert.ErrorIs(t, err, ErrOutboxEntryNotFound)
+	entries, err := store.Load()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "order-2", entries[0].ID)
+}
+
+// TestOutbox_Start will test delivering in the background
+func TestOutbox_Start(t *testing.T) {
+	t.Parallel()
+
+	outbox, service, _ := newTestOutbox(t, WithOutboxPollInterval(time.Hour))
+	outbox.Start(context.Background())
+	defer outbox.Stop()
+
+	_, err := outbox.Enqueue(newTestConversionRequest("order-1"))
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return outbox.Stats().Delivered == 1
+	}, time.Second, time.Millisecond)
+
+	outbox.Stop()
+	_, err = outbox.Enqueue(newTestConversionRequest("order-2"))
+	require.NoError(t, err)
+	time.Sleep(10 * time.Millisecond)
+	assert.Len(t, service.idempotencyKeys(), 1)
+}
+
+// TestOutbox_Restart will test delivering the entries of a previous process
+func TestOutbox_Restart(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	store, err := NewFileOutboxStore(dir)
+	require.NoError(t, err)
+	service := new(testConversionService)
+	service.fail(errors.New("connection refused"))
+
+	outbox, err := NewOutbox(service, store)
+	require.NoError(t, err)
+	_, err = outbox.Enqueue(newTestConversionRequest("order-1"))
+	require.NoError(t, err)
+	_, err = outbox.Enqueue(newTestConversionRequest("order-2"))
+	require.NoError(t, err)
+	require.NoError(t, outbox.Flush(context.Background()))
+	assert.Equal(t, OutboxStats{Delivered: 1, Pending: 1}, outbox.Stats())
+
+	// Restart (the failed entry is due once the backoff passed)
+	store, err = NewFileOutboxStore(dir)
+	require.NoError(t, err)
+	outbox, err = NewOutbox(service, store, withOutboxClock(func() time.Time { return time.Now().Add(time.Hour) }))
+	require.NoError(t, err)
+	assert.Equal(t, OutboxStats{Delivered: 1, Pending: 1}, outbox.Stats())
+
+	entry, err := outbox.Entry("order-1")
+	require.NoError(t, err)
+	assert.Equal(t, NewDecimal(1999, 2), entry.Request.PurchaseAmount)
+	assert.Equal(t, "connection refused", entry.LastError)
+
+	require.NoError(t, outbox.Flush(context.Background()))
+	assert.Equal(t, OutboxStats{Delivered: 2}, outbox.Stats())
+	assert.Equal(t, []string{"order-1", "order-2", "order-1"}, service.idempotencyKeys())
+}
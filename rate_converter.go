@@ -0,0 +1,323 @@
+package tonicpow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultRateMaxAge is the default maximum age of a cached rate before it is stale
+	defaultRateMaxAge = 15 * time.Minute
+
+	// defaultRateRefreshInterval is the default interval between refreshes (see RateConverter.Start)
+	defaultRateRefreshInterval = 5 * time.Minute
+)
+
+var (
+	// ErrRateUnavailable is returned when there is no cached rate for the currency
+	ErrRateUnavailable = errors.New("rate unavailable")
+
+	// ErrRateStale is returned when the cached rate is older than the maximum age
+	ErrRateStale = errors.New("rate is stale")
+)
+
+// RateConverter converts amounts between currencies and satoshis offline, using rates
+// cached from GetCurrentRate (see Refresh and Start)
+//
+// A RateConverter is safe for concurrent use
+type RateConverter struct {
+	currencies      []Currency
+	done            chan struct{}
+	maxAge          time.Duration
+	mu              sync.RWMutex
+	now             func() time.Time
+	rates           map[Currency]*cachedRate
+	refreshInterval time.Duration
+	service         RateService
+	stop            context.CancelFunc
+}
+
+// cachedRate is a rate and the time it was fetched
+type cachedRate struct {
+	fetchedAt time.Time
+	rate      *Rate
+}
+
+// ConverterOps allow functional options to be supplied to NewRateConverter
+type ConverterOps func(r *RateConverter)
+
+// WithConverterCurrencies will set the currencies that are fetched on every refresh (default is usd)
+func WithConverterCurrencies(currencies ...Currency) ConverterOps {
+	return func(r *RateConverter) {
+		r.currencies = r.currencies[:0]
+		for _, currency := range currencies {
+			r.currencies = append(r.currencies, currency.normalize())
+		}
+	}
+}
+
+// WithRateMaxAge will set the maximum age of a cached rate (0 is no limit, default is 15 minutes)
+//
+// Conversions using a rate older than the maximum age return ErrRateStale
+func WithRateMaxAge(maxAge time.Duration) ConverterOps {
+	return func(r *RateConverter) {
+		r.maxAge = maxAge
+	}
+}
+
+// WithRateRefreshInterval will set the interval between refreshes when started (default is 5 minutes)
+func WithRateRefreshInterval(interval time.Duration) ConverterOps {
+	return func(r *RateConverter) {
+		if interval > 0 {
+			r.refreshInterval = interval
+		}
+	}
+}
+
+// withConverterClock will set the clock of the converter (for tests)
+func withConverterClock(now func() time.Time) ConverterOps {
+	return func(r *RateConverter) {
+		r.now = now
+	}
+}
+
+// NewRateConverter will return a new converter that fetches rates using the service (the Client)
+//
+// No rates are fetched until Refresh or Start is called (or rates are added using SetRate)
+func NewRateConverter(service RateService, opts ...ConverterOps) *RateConverter {
+	r := &RateConverter{
+		currencies:      []Currency{CurrencyUSD},
+		maxAge:          defaultRateMaxAge,
+		now:             time.Now,
+		rates:           make(map[Currency]*cachedRate),
+		refreshInterval: defaultRateRefreshInterval,
+		service:         service,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Refresh will fetch the current rate of every currency (configured or previously cached)
+//
+// Rates that fail to refresh keep their previous value (and will eventually become stale)
+func (r *RateConverter) Refresh(ctx context.Context) error {
+	var errs []error
+	for _, currency := range r.trackedCurrencies() {
+		rate, _, err := r.service.GetCurrentRateDecimalWithContext(ctx, currency, Decimal{})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to refresh rate for %s: %w", currency, err))
+			continue
+		}
+		r.SetRate(rate)
+	}
+	return errors.Join(errs...)
+}
+
+// Start will refresh the rates now and then on every refresh interval, until Stop is called
+// or the context is done
+//
+// The error of the first refresh is returned (the converter is started either way)
+func (r *RateConverter) Start(ctx context.Context) error {
+	r.Stop()
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	r.mu.Lock()
+	r.stop, r.done = cancel, done
+	r.mu.Unlock()
+
+	err := r.Refresh(ctx)
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(r.refreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_ = r.Refresh(ctx)
+			}
+		}
+	}()
+	return err
+}
+
+// Stop will stop refreshing the rates (the cached rates can still be used)
+func (r *RateConverter) Stop() {
+	r.mu.Lock()
+	stop, done := r.stop, r.done
+	r.stop, r.done = nil, nil
+	r.mu.Unlock()
+
+	if stop != nil {
+		stop()
+		<-done
+	}
+}
+
+// SetRate will add or replace the cached rate of the currency (fetched now)
+func (r *RateConverter) SetRate(rate *Rate) {
+	if rate == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rates[rate.Currency.normalize()] = &cachedRate{fetchedAt: r.now(), rate: rate}
+}
+
+// Rate will return the cached rate of the currency
+//
+// Returns ErrRateUnavailable if the rate is not cached, or ErrRateStale if it's older than the maximum age
+func (r *RateConverter) Rate(currency Currency) (*Rate, error) {
+	currency = currency.normalize()
+	if currency == CurrencyBSV {
+		return &Rate{Currency: CurrencyBSV, CurrencyAmount: NewDecimal(1, 0), PriceInSatoshis: SatoshisPerBitcoin}, nil
+	}
+
+	r.mu.RLock()
+	cached, ok := r.rates[currency]
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrRateUnavailable, currency)
+	} else if age := r.now().Sub(cached.fetchedAt); r.maxAge > 0 && age > r.maxAge {
+		return nil, fmt.Errorf("%w: %s rate is %s old", ErrRateStale, currency, age.Round(time.Second))
+	}
+	return cached.rate, nil
+}
+
+// ToSatoshis will convert the money to satoshis (rounded to the nearest satoshi)
+func (r *RateConverter) ToSatoshis(money Money) (Satoshis, error) {
+	rate, err := r.Rate(money.Currency)
+	if err != nil {
+		return 0, err
+	}
+	return rate.Satoshis(money.Amount)
+}
+
+// FromSatoshis will convert the satoshis to money in the currency (rounded to 8 decimal places)
+func (r *RateConverter) FromSatoshis(satoshis Satoshis, currency Currency) (Money, error) {
+	rate, err := r.Rate(currency)
+	if err != nil {
+		return Money{}, err
+	}
+	var amount Decimal
+	if amount, err = rate.Amount(satoshis); err != nil {
+		return Money{}, err
+	}
+	return NewMoney(amount, currency), nil
+}
+
+// Convert will convert the money to the currency (rounded to 8 decimal places)
+//
+// The conversion is exact (no rounding to satoshis in between)
+func (r *RateConverter) Convert(money Money, currency Currency) (Money, error) {
+	from, err := r.Rate(money.Currency)
+	if err != nil {
+		return Money{}, err
+	}
+	var to *Rate
+	if to, err = r.Rate(currency); err != nil {
+		return Money{}, err
+	}
+	if from.CurrencyAmount.Sign() <= 0 || from.PriceInSatoshis <= 0 || to.PriceInSatoshis <= 0 {
+		return Money{}, fmt.Errorf("invalid rate to convert %s to %s", money.Currency, currency)
+	}
+
+	// amount * (from.price / from.amount) * (to.amount / to.price)
+	amount := new(big.Rat).Mul(money.Amount.rat(), big.NewRat(from.PriceInSatoshis, 1))
+	amount.Quo(amount, from.CurrencyAmount.rat())
+	amount.Mul(amount, to.CurrencyAmount.rat())
+	amount.Quo(amount, big.NewRat(to.PriceInSatoshis, 1))
+
+	var converted Decimal
+	if converted, err = decimalFromRat(amount); err != nil {
+		return Money{}, err
+	}
+	return NewMoney(converted, currency), nil
+}
+
+// trackedCurrencies will return the configured and cached currencies (sorted)
+func (r *RateConverter) trackedCurrencies() []Currency {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	seen := make(map[Currency]bool)
+	var currencies []Currency
+	add := func(currency Currency) {
+		if !seen[currency] && currency != CurrencyBSV && len(currency) > 0 {
+			seen[currency] = true
+			currencies = append(currencies, currency)
+		}
+	}
+	for _, currency := range r.currencies {
+		add(currency)
+	}
+	for currency := range r.rates {
+		add(currency)
+	}
+	sort.Slice(currencies, func(i, j int) bool { return currencies[i] < currencies[j] })
+	return currencies
+}
+
+// CampaignRunway is the estimated runway of a campaign (how long the balance will last)
+type CampaignRunway struct {
+	Balance         Money             `json:"balance"`          // Balance in the campaign currency (at the current rate)
+	BalanceSatoshis Satoshis          `json:"balance_satoshis"` // Balance in satoshis
+	Clicks          uint64            `json:"clicks"`           // Paid clicks until the balance runs out (0 if not pay-per-click)
+	Conversions     map[string]uint64 `json:"conversions"`      // Conversions per goal name (flat payouts only)
+}
+
+// EstimateRunway will estimate how many paid clicks and conversions (per goal) the balance of the campaign covers
+//
+// All amounts are in the campaign currency (Campaign.Currency, default is usd). The satoshi balance
+// (BalanceSatoshis) is valued at the current rate, falling back to Balance if there is no satoshi balance
+func (r *RateConverter) EstimateRunway(campaign *Campaign) (*CampaignRunway, error) {
+	if campaign == nil {
+		return nil, fmt.Errorf("missing required attribute: %s", modelCampaign)
+	}
+	currency := campaign.Currency.normalize()
+	if len(currency) == 0 {
+		currency = CurrencyUSD
+	}
+
+	runway := &CampaignRunway{
+		BalanceSatoshis: Satoshis(campaign.BalanceSatoshis),
+		Conversions:     make(map[string]uint64),
+	}
+
+	var err error
+	if runway.BalanceSatoshis > 0 {
+		if runway.Balance, err = r.FromSatoshis(runway.BalanceSatoshis, currency); err != nil {
+			return nil, err
+		}
+	} else {
+		runway.Balance = NewMoney(campaign.Balance, currency)
+		if runway.BalanceSatoshis, err = r.ToSatoshis(runway.Balance); err != nil {
+			return nil, err
+		}
+	}
+
+	// Remaining payouts (using exact amounts in the campaign currency)
+	payouts := func(payout Decimal) uint64 {
+		if payout.Sign() <= 0 || runway.Balance.Amount.Sign() <= 0 {
+			return 0
+		}
+		return uint64(runway.Balance.Amount.units / payout.units)
+	}
+	runway.Clicks = payouts(campaign.PayPerClickRate)
+	for _, goal := range campaign.Goals {
+		if goal != nil && goal.PayoutType == PayoutTypeFlat {
+			runway.Conversions[goal.Name] = payouts(goal.PayoutRate)
+		}
+	}
+	return runway, nil
+}
@@ -0,0 +1,205 @@
+package tonicpow
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testRateService is a RateService that returns fixed rates (satoshis per 1 unit of currency)
+type testRateService struct {
+	calls int
+	mu    sync.Mutex
+	rates map[Currency]int64
+}
+
+// GetCurrentRate will return the rate of the currency
+func (s *testRateService) GetCurrentRate(currency string, customAmount float64) (*Rate, *StandardResponse, error) {
+	return s.GetCurrentRateWithContext(context.Background(), currency, customAmount)
+}
+
+// GetCurrentRateWithContext will return the rate of the currency
+func (s *testRateService) GetCurrentRateWithContext(ctx context.Context, currency string,
+	customAmount float64) (*Rate, *StandardResponse, error) {
+	return s.GetCurrentRateDecimalWithContext(ctx, Currency(currency), NewDecimalFromFloat(customAmount))
+}
+
+// GetCurrentRateDecimal will return the rate of the currency
+func (s *testRateService) GetCurrentRateDecimal(currency Currency, customAmount Decimal) (*Rate, *StandardResponse, error) {
+	return s.GetCurrentRateDecimalWithContext(context.Background(), currency, customAmount)
+}
+
+// GetCurrentRateDecimalWithContext will return the rate of the currency
+func (s *testRateService) GetCurrentRateDecimalWithContext(_ context.Context, currency Currency,
+	_ Decimal) (*Rate, *StandardResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	price, ok := s.rates[currency]
+	if !ok {
+		return nil, nil, &Error{Message: "currency is not supported", StatusCode: 400}
+	}
+	return &Rate{Currency: currency, CurrencyAmount: NewDecimal(1, 0), PriceInSatoshis: price}, nil, nil
+}
+
+// callCount will return the number of requests
+func (s *testRateService) callCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+// newTestRateService will return a service with usd (2,000,000 sats) and eur (2,500,000 sats)
+func newTestRateService() *testRateService {
+	return &testRateService{rates: map[Currency]int64{CurrencyUSD: 2000000, CurrencyEUR: 2500000}}
+}
+
+// TestRateConverter_Refresh will test refreshing the rates
+func TestRateConverter_Refresh(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no rates before refresh", func(t *testing.T) {
+		converter := NewRateConverter(newTestRateService())
+		_, err := converter.Rate(CurrencyUSD)
+		assert.ErrorIs(t, err, ErrRateUnavailable)
+	})
+
+	t.Run("refresh the configured currencies", func(t *testing.T) {
+		service := newTestRateService()
+		converter := NewRateConverter(service, WithConverterCurrencies("USD", CurrencyEUR, CurrencyBSV))
+		require.NoError(t, converter.Refresh(context.Background()))
+		assert.Equal(t, 2, service.callCount())
+
+		rate, err := converter.Rate(CurrencyEUR)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2500000), rate.PriceInSatoshis)
+	})
+
+	t.Run("failed refresh keeps the previous rate", func(t *testing.T) {
+		service := newTestRateService()
+		converter := NewRateConverter(service, WithConverterCurrencies(CurrencyUSD, "xyz"))
+		err := converter.Refresh(context.Background())
+		assert.ErrorIs(t, err, ErrValidation)
+
+		service.mu.Lock()
+		delete(service.rates, CurrencyUSD)
+		service.mu.Unlock()
+		assert.Error(t, converter.Refresh(context.Background()))
+
+		_, err = converter.Rate(CurrencyUSD)
+		assert.NoError(t, err)
+	})
+
+	t.Run("stale rates", func(t *testing.T) {
+		now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+		converter := NewRateConverter(
+			newTestRateService(), WithRateMaxAge(time.Minute), withConverterClock(func() time.Time { return now }),
+		)
+		require.NoError(t, converter.Refresh(context.Background()))
+
+		now = now.Add(time.Minute)
+		_, err := converter.Rate(CurrencyUSD)
+		assert.NoError(t, err)
+
+		now = now.Add(time.Second)
+		_, err = converter.Rate(CurrencyUSD)
+		assert.ErrorIs(t, err, ErrRateStale)
+		_, err = converter.ToSatoshis(NewMoney(NewDecimal(1, 0), CurrencyUSD))
+		assert.ErrorIs(t, err, ErrRateStale)
+
+		// BSV is never stale
+		_, err = converter.Rate(CurrencyBSV)
+		assert.NoError(t, err)
+	})
+
+	t.Run("start and stop", func(t *testing.T) {
+		service := newTestRateService()
+		converter := NewRateConverter(service, WithRateRefreshInterval(time.Millisecond))
+		require.NoError(t, converter.Start(context.Background()))
+		assert.Eventually(t, func() bool { return service.callCount() >= 3 }, time.Second, time.Millisecond)
+
+		converter.Stop()
+		calls := service.callCount()
+		time.Sleep(10 * time.Millisecond)
+		assert.Equal(t, calls, service.callCount())
+		converter.Stop()
+	})
+}
+
+// TestRateConverter_Convert will test the conversions
+func TestRateConverter_Convert(t *testing.T) {
+	t.Parallel()
+
+	converter := NewRateConverter(newTestRateService(), WithConverterCurrencies(CurrencyUSD, CurrencyEUR))
+	require.NoError(t, converter.Refresh(context.Background()))
+
+	satoshis, err := converter.ToSatoshis(NewMoney(MustParseDecimal("12.34"), CurrencyUSD))
+	require.NoError(t, err)
+	assert.Equal(t, Satoshis(24680000), satoshis)
+
+	var money Money
+	money, err = converter.FromSatoshis(24680000, CurrencyEUR)
+	require.NoError(t, err)
+	assert.Equal(t, "9.872 eur", money.String())
+
+	money, err = converter.Convert(NewMoney(NewDecimal(10, 0), CurrencyUSD), CurrencyEUR)
+	require.NoError(t, err)
+	assert.Equal(t, "8 eur", money.String())
+
+	money, err = converter.Convert(NewMoney(NewDecimal(1, 0), CurrencyBSV), CurrencyUSD)
+	require.NoError(t, err)
+	assert.Equal(t, "50 usd", money.String())
+
+	_, err = converter.Convert(NewMoney(NewDecimal(1, 0), CurrencyUSD), "xyz")
+	assert.True(t, errors.Is(err, ErrRateUnavailable))
+}
+
+// TestRateConverter_EstimateRunway will test the method EstimateRunway()
+func TestRateConverter_EstimateRunway(t *testing.T) {
+	t.Parallel()
+
+	converter := NewRateConverter(newTestRateService(), WithConverterCurrencies(CurrencyUSD))
+	require.NoError(t, converter.Refresh(context.Background()))
+
+	t.Run("satoshi balance", func(t *testing.T) {
+		campaign := newTestCampaign() // 11,333,377 sats and 1 usd per click
+		campaign.PayPerClickRate = MustParseDecimal("0.5")
+		campaign.Goals = []*Goal{
+			{Name: "signup", PayoutRate: MustParseDecimal("0.25"), PayoutType: "flat"},
+			{Name: "purchase", PayoutRate: NewDecimal(10, 0), PayoutType: "percent"},
+		}
+
+		runway, err := converter.EstimateRunway(campaign)
+		require.NoError(t, err)
+		assert.Equal(t, "5.6666885 usd", runway.Balance.String())
+		assert.Equal(t, Satoshis(11333377), runway.BalanceSatoshis)
+		assert.Equal(t, uint64(11), runway.Clicks)
+		assert.Equal(t, map[string]uint64{"signup": 22}, runway.Conversions)
+	})
+
+	t.Run("balance without satoshis", func(t *testing.T) {
+		campaign := &Campaign{Balance: NewDecimal(10, 0), PayPerClickRate: NewDecimal(3, 0)}
+		runway, err := converter.EstimateRunway(campaign)
+		require.NoError(t, err)
+		assert.Equal(t, "10 usd", runway.Balance.String())
+		assert.Equal(t, Satoshis(20000000), runway.BalanceSatoshis)
+		assert.Equal(t, uint64(3), runway.Clicks)
+	})
+
+	t.Run("unknown currency", func(t *testing.T) {
+		campaign := newTestCampaign()
+		campaign.Currency = CurrencyEUR
+		_, err := converter.EstimateRunway(campaign)
+		assert.ErrorIs(t, err, ErrRateUnavailable)
+	})
+
+	t.Run("missing campaign", func(t *testing.T) {
+		_, err := converter.EstimateRunway(nil)
+		assert.Error(t, err)
+	})
+}
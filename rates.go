@@ -1,9 +1,11 @@
 package tonicpow
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 )
 
 // GetCurrentRate will get a current rate for the given currency (using default currency amount)
@@ -11,6 +13,24 @@ import (
 // For more information: https://docs.tonicpow.com/#71b8b7fc-317a-4e68-bd2a-5b0da012361c
 func (c *Client) GetCurrentRate(currency string,
 	customAmount float64) (rate *Rate, response *StandardResponse, err error) {
+	return c.GetCurrentRateWithContext(context.Background(), currency, customAmount)
+}
+
+// GetCurrentRateWithContext is the same as GetCurrentRate but uses the given context
+func (c *Client) GetCurrentRateWithContext(ctx context.Context, currency string,
+	customAmount float64) (rate *Rate, response *StandardResponse, err error) {
+	return c.GetCurrentRateDecimalWithContext(ctx, Currency(currency), NewDecimalFromFloat(customAmount))
+}
+
+// GetCurrentRateDecimal is the same as GetCurrentRate but uses the Currency & exact Decimal types
+func (c *Client) GetCurrentRateDecimal(currency Currency,
+	customAmount Decimal) (rate *Rate, response *StandardResponse, err error) {
+	return c.GetCurrentRateDecimalWithContext(context.Background(), currency, customAmount)
+}
+
+// GetCurrentRateDecimalWithContext is the same as GetCurrentRateDecimal but uses the given context
+func (c *Client) GetCurrentRateDecimalWithContext(ctx context.Context, currency Currency,
+	customAmount Decimal) (rate *Rate, response *StandardResponse, err error) {
 
 	// Currency is required
 	if len(currency) == 0 {
@@ -19,10 +39,11 @@ func (c *Client) GetCurrentRate(currency string,
 	}
 
 	// Fire the Request
-	if response, err = c.Request(
-		http.MethodGet,
-		fmt.Sprintf("/%s/%s?%s=%f", modelRates, currency, fieldAmount, customAmount),
-		nil, http.StatusOK,
+	if response, err = c.RequestWithOptions(
+		ctx, http.MethodGet,
+		fmt.Sprintf("/%s/%s?%s=%s", modelRates, url.PathEscape(string(currency)), fieldAmount, customAmount),
+		nil, http.StatusOK, withCacheEndpoint(CacheEndpointRate),
+		withOperation("GetCurrentRate"),
 	); err != nil {
 		return
 	}
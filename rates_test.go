@@ -1,18 +1,20 @@
 package tonicpow
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
 
+	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/assert"
 )
 
 // newTestRate creates a dummy profile for testing
 func newTestRate() *Rate {
 	return &Rate{
-		Currency:        testRateCurrency,
-		CurrencyAmount:  0.01,
+		Currency:        Currency(testRateCurrency),
+		CurrencyAmount:  NewDecimal(1, 2),
 		PriceInSatoshis: 4200,
 	}
 }
@@ -29,9 +31,9 @@ func TestClient_GetCurrentRate(t *testing.T) {
 		rates := newTestRate()
 
 		endpoint := fmt.Sprintf(
-			"%s/%s/%s?%s=%f", EnvironmentDevelopment.apiURL,
+			"%s/%s/%s?%s=%s", EnvironmentDevelopment.apiURL,
 			modelRates, testRateCurrency,
-			fieldAmount, 0.0,
+			fieldAmount, Decimal{},
 		)
 
 		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, rates)
@@ -43,7 +45,7 @@ func TestClient_GetCurrentRate(t *testing.T) {
 		assert.NoError(t, err)
 		assert.NotNil(t, currentRate)
 		assert.NotNil(t, response)
-		assert.Equal(t, testRateCurrency, currentRate.Currency)
+		assert.Equal(t, Currency(testRateCurrency), currentRate.Currency)
 	})
 
 	t.Run("missing currency", func(t *testing.T) {
@@ -54,9 +56,9 @@ func TestClient_GetCurrentRate(t *testing.T) {
 		rates := newTestRate()
 
 		endpoint := fmt.Sprintf(
-			"%s/%s/%s?%s=%f", EnvironmentDevelopment.apiURL,
+			"%s/%s/%s?%s=%s", EnvironmentDevelopment.apiURL,
 			modelRates, testRateCurrency,
-			fieldAmount, 0.0,
+			fieldAmount, Decimal{},
 		)
 
 		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, rates)
@@ -78,9 +80,9 @@ func TestClient_GetCurrentRate(t *testing.T) {
 		rates := newTestRate()
 
 		endpoint := fmt.Sprintf(
-			"%s/%s/%s?%s=%f", EnvironmentDevelopment.apiURL,
+			"%s/%s/%s?%s=%s", EnvironmentDevelopment.apiURL,
 			modelRates, testRateCurrency,
-			fieldAmount, 0.0,
+			fieldAmount, Decimal{},
 		)
 
 		err = mockResponseData(http.MethodGet, endpoint, http.StatusBadRequest, rates)
@@ -100,9 +102,9 @@ func TestClient_GetCurrentRate(t *testing.T) {
 		assert.NotNil(t, client)
 
 		endpoint := fmt.Sprintf(
-			"%s/%s/%s?%s=%f", EnvironmentDevelopment.apiURL,
+			"%s/%s/%s?%s=%s", EnvironmentDevelopment.apiURL,
 			modelRates, testRateCurrency,
-			fieldAmount, 0.0,
+			fieldAmount, Decimal{},
 		)
 
 		apiError := &Error{
@@ -129,6 +131,62 @@ func TestClient_GetCurrentRate(t *testing.T) {
 	})
 }
 
+// TestClient_GetCurrentRateDecimal will test the method GetCurrentRateDecimal()
+func TestClient_GetCurrentRateDecimal(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	t.Run("get current rate (exact amount)", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		endpoint := fmt.Sprintf(
+			"%s/%s/%s?%s=%s", EnvironmentDevelopment.apiURL,
+			modelRates, testRateCurrency,
+			fieldAmount, "12.34567891",
+		)
+
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestRate())
+		assert.NoError(t, err)
+
+		var currentRate *Rate
+		currentRate, _, err = client.GetCurrentRateDecimal(CurrencyUSD, MustParseDecimal("12.34567891"))
+		assert.NoError(t, err)
+		assert.NotNil(t, currentRate)
+		assert.Equal(t, CurrencyUSD, currentRate.Currency)
+	})
+
+	t.Run("currency is escaped in the path", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		endpoint := fmt.Sprintf(
+			"%s/%s/%s?%s=%s", EnvironmentDevelopment.apiURL,
+			modelRates, "us%2Fd%3F",
+			fieldAmount, "1",
+		)
+
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestRate())
+		assert.NoError(t, err)
+
+		_, _, err = client.GetCurrentRateDecimal("us/d?", NewDecimal(1, 0))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, httpmock.GetTotalCallCount())
+	})
+
+	t.Run("missing currency", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		var currentRate *Rate
+		currentRate, _, err = client.GetCurrentRateDecimal("", Decimal{})
+		assert.Error(t, err)
+		assert.Nil(t, currentRate)
+	})
+}
+
 // ExampleClient_GetCurrentRate example using GetCurrentRate()
 //
 // See more examples in /examples/
@@ -148,9 +206,9 @@ func ExampleClient_GetCurrentRate() {
 	_ = mockResponseData(
 		http.MethodGet,
 		fmt.Sprintf(
-			"%s/%s/%s?%s=%f", EnvironmentDevelopment.apiURL,
+			"%s/%s/%s?%s=%s", EnvironmentDevelopment.apiURL,
 			modelRates, testRateCurrency,
-			fieldAmount, 0.0,
+			fieldAmount, Decimal{},
 		),
 		http.StatusOK,
 		rates,
@@ -164,8 +222,8 @@ func ExampleClient_GetCurrentRate() {
 		fmt.Printf("error getting profile: " + err.Error())
 		return
 	}
-	fmt.Printf("current rate: %s  %f usd is %d sats", currentRate.Currency, currentRate.CurrencyAmount, currentRate.PriceInSatoshis)
-	// Output:current rate: usd  0.010000 usd is 4200 sats
+	fmt.Printf("current rate: %s  %s usd is %d sats", currentRate.Currency, currentRate.CurrencyAmount, currentRate.PriceInSatoshis)
+	// Output:current rate: usd  0.01 usd is 4200 sats
 }
 
 // BenchmarkClient_GetCurrentRate benchmarks the method GetCurrentRate()
@@ -175,9 +233,9 @@ func BenchmarkClient_GetCurrentRate(b *testing.B) {
 	_ = mockResponseData(
 		http.MethodGet,
 		fmt.Sprintf(
-			"%s/%s/%s?%s=%f", EnvironmentDevelopment.apiURL,
+			"%s/%s/%s?%s=%s", EnvironmentDevelopment.apiURL,
 			modelRates, testRateCurrency,
-			fieldAmount, 0.0,
+			fieldAmount, Decimal{},
 		),
 		http.StatusOK,
 		rate,
@@ -186,3 +244,42 @@ func BenchmarkClient_GetCurrentRate(b *testing.B) {
 		_, _, _ = client.GetCurrentRate(testRateCurrency, 0.00)
 	}
 }
+
+// TestClient_GetCurrentRateWithContext will test the method GetCurrentRateWithContext()
+func TestClient_GetCurrentRateWithContext(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	endpoint := fmt.Sprintf(
+		"%s/%s/%s?%s=%s", EnvironmentDevelopment.apiURL,
+		modelRates, testRateCurrency,
+		fieldAmount, Decimal{},
+	)
+
+	t.Run("valid context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		err = mockResponseData(http.MethodGet, endpoint, http.StatusOK, newTestRate())
+		assert.NoError(t, err)
+
+		var rate *Rate
+		rate, _, err = client.GetCurrentRateWithContext(context.Background(), testRateCurrency, 0.00)
+		assert.NoError(t, err)
+		assert.NotNil(t, rate)
+		assert.Equal(t, Currency(testRateCurrency), rate.Currency)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		client, err := newTestClient()
+		assert.NoError(t, err)
+		assert.NotNil(t, client)
+
+		mockResponseBlocking(http.MethodGet, endpoint)
+
+		var rate *Rate
+		rate, _, err = client.GetCurrentRateWithContext(newCanceledContext(), testRateCurrency, 0.00)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, rate)
+	})
+}
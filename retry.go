@@ -0,0 +1,219 @@
+package tonicpow
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// Retry defaults
+	defaultRetryBaseDelay     = 250 * time.Millisecond // Delay before the first retry (doubles every attempt)
+	defaultRetryMaxDelay      = 10 * time.Second       // Maximum backoff delay between attempts
+	defaultRetryMaxRetryAfter = time.Minute            // Maximum Retry-After delay that will be honored
+)
+
+// RetryPolicy decides if (and when) a failed request should be retried
+//
+// The default policy is a BackoffPolicy using the retry count of the client (see WithRetryCount),
+// a custom policy can be set using WithRetryPolicy()
+type RetryPolicy interface {
+	// Backoff will return the delay before the next attempt, or false if the request should not be retried
+	Backoff(attempt *RetryAttempt) (delay time.Duration, retry bool)
+}
+
+// RetryAttempt holds the details of a failed request attempt
+type RetryAttempt struct {
+	Attempt    int         // Number of the attempt that failed (starting at 1)
+	Err        error       // Transport error (nil if a response was received)
+	Header     http.Header // Response headers (nil if no response was received)
+	Idempotent bool        // True if the request is safe to retry (idempotent method or opted in)
+	Method     string      // HTTP method of the request
+	StatusCode int         // Response status code (0 if no response was received)
+}
+
+// BackoffPolicy is the default RetryPolicy
+//
+// Failed requests are retried using exponential backoff with jitter, a Retry-After header
+// on the response is honored instead of the backoff. Only transport errors, 429 and 5xx
+// (500, 502, 503, 504) responses are retried, and only if the request is idempotent.
+type BackoffPolicy struct {
+	BaseDelay     time.Duration // Delay before the first retry (doubles every attempt)
+	MaxDelay      time.Duration // Maximum backoff delay between attempts
+	MaxRetries    int           // Maximum number of retries (0 disables retries)
+	MaxRetryAfter time.Duration // Retry-After delays longer than this are not retried
+}
+
+// NewBackoffPolicy will return a BackoffPolicy with the default delays
+func NewBackoffPolicy(maxRetries int) *BackoffPolicy {
+	return &BackoffPolicy{
+		BaseDelay:     defaultRetryBaseDelay,
+		MaxDelay:      defaultRetryMaxDelay,
+		MaxRetries:    maxRetries,
+		MaxRetryAfter: defaultRetryMaxRetryAfter,
+	}
+}
+
+// Backoff will return the delay before the next attempt (implements RetryPolicy)
+func (p *BackoffPolicy) Backoff(attempt *RetryAttempt) (time.Duration, bool) {
+	if attempt.Attempt > p.MaxRetries || !attempt.Idempotent || !isRetryable(attempt) {
+		return 0, false
+	}
+
+	// Honor the Retry-After header (if the server asked us to wait)
+	if delay, ok := retryAfter(attempt.Header, time.Now()); ok {
+		if delay > p.MaxRetryAfter {
+			return 0, false
+		}
+		return delay, true
+	}
+
+	// Exponential backoff with (equal) jitter
+	delay := p.BaseDelay << uint(attempt.Attempt-1)
+	if delay <= 0 || delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if half := int64(delay / 2); half > 0 {
+		delay = time.Duration(half + rand.Int63n(half+1)) //nolint:gosec // jitter does not need a secure random
+	}
+	return delay, true
+}
+
+// isRetryable will return true if the failed attempt is a transport error or a retryable status code
+func isRetryable(attempt *RetryAttempt) bool {
+	if attempt.Err != nil {
+		return true
+	}
+	switch attempt.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// isIdempotent will return true if the HTTP method is safe to retry
+func isIdempotent(httpMethod string) bool {
+	switch httpMethod {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryAfter will parse the Retry-After header (delay in seconds or an HTTP date)
+func retryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if len(value) == 0 {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := date.Sub(now); delay > 0 {
+			return delay, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// sleepContext will wait for the delay or until the context is done
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// rateLimiter is a client-side token bucket limiter shared by all requests of a client
+//
+// When the API responds with a 429 and a Retry-After header, the limiter is paused
+// so concurrent requests also wait instead of hammering the API
+type rateLimiter struct {
+	burst       float64
+	last        time.Time
+	mu          sync.Mutex
+	now         func() time.Time
+	pausedUntil time.Time
+	rate        float64 // Tokens per second
+	tokens      float64
+}
+
+// newRateLimiter will return a full token bucket
+func newRateLimiter(requestsPerSecond float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		burst:  float64(burst),
+		now:    time.Now,
+		rate:   requestsPerSecond,
+		tokens: float64(burst),
+	}
+}
+
+// wait will block until a token is available or the context is done
+func (l *rateLimiter) wait(ctx context.Context) error {
+	for {
+		delay := l.reserve()
+		if delay <= 0 {
+			return nil
+		}
+		if err := sleepContext(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// reserve will take a token if available, otherwise it returns the time to wait for the next token
+func (l *rateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Before(l.pausedUntil) {
+		return l.pausedUntil.Sub(now)
+	}
+
+	// Refill the bucket
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// pause will stop handing out tokens for the given duration
+func (l *rateLimiter) pause(delay time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until := l.now().Add(delay); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
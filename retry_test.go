@@ -0,0 +1,345 @@
+package tonicpow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRetryPolicy will return a fast retry policy for tests
+func newTestRetryPolicy(maxRetries int) *BackoffPolicy {
+	return &BackoffPolicy{
+		BaseDelay:     time.Millisecond,
+		MaxDelay:      5 * time.Millisecond,
+		MaxRetries:    maxRetries,
+		MaxRetryAfter: 2 * time.Second,
+	}
+}
+
+// newRetryTestClient will return a mock client using the given options
+func newRetryTestClient(opts ...ClientOps) (ClientInterface, error) {
+	client := resty.New()
+	httpmock.ActivateNonDefault(client.GetClient())
+	newClient, err := NewClient(append([]ClientOps{
+		WithAPIKey(testAPIKey),
+		WithEnvironment(EnvironmentDevelopment),
+	}, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+	newClient.WithCustomHTTPClient(client)
+	return newClient, nil
+}
+
+// mockResponseSequence is used for mocking a different status code on each attempt
+//
+// The last status code is repeated, the returned func will return the number of calls
+func mockResponseSequence(method, endpoint string, header http.Header, statusCodes ...int) func() int {
+	httpmock.Reset()
+	calls := 0
+	httpmock.RegisterResponder(method, endpoint, func(req *http.Request) (*http.Response, error) {
+		statusCode := statusCodes[len(statusCodes)-1]
+		if calls < len(statusCodes) {
+			statusCode = statusCodes[calls]
+		}
+		calls++
+		resp := httpmock.NewStringResponse(statusCode, `{}`)
+		for key, values := range header {
+			resp.Header[key] = values
+		}
+		return resp, nil
+	})
+	return func() int { return calls }
+}
+
+// TestBackoffPolicy_Backoff will test the method Backoff()
+func TestBackoffPolicy_Backoff(t *testing.T) {
+	t.Parallel()
+
+	policy := &BackoffPolicy{
+		BaseDelay:     100 * time.Millisecond,
+		MaxDelay:      time.Second,
+		MaxRetries:    5,
+		MaxRetryAfter: time.Minute,
+	}
+
+	t.Run("exponential backoff with jitter", func(t *testing.T) {
+		for attempt, maxDelay := range []time.Duration{
+			100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second,
+		} {
+			delay, retry := policy.Backoff(&RetryAttempt{
+				Attempt: attempt + 1, Idempotent: true, StatusCode: http.StatusServiceUnavailable,
+			})
+			assert.True(t, retry)
+			assert.GreaterOrEqual(t, delay, maxDelay/2)
+			assert.LessOrEqual(t, delay, maxDelay)
+		}
+	})
+
+	t.Run("max retries", func(t *testing.T) {
+		_, retry := policy.Backoff(&RetryAttempt{Attempt: 6, Idempotent: true, StatusCode: http.StatusBadGateway})
+		assert.False(t, retry)
+	})
+
+	t.Run("non-idempotent", func(t *testing.T) {
+		_, retry := policy.Backoff(&RetryAttempt{Attempt: 1, StatusCode: http.StatusServiceUnavailable})
+		assert.False(t, retry)
+	})
+
+	t.Run("transport error", func(t *testing.T) {
+		_, retry := policy.Backoff(&RetryAttempt{Attempt: 1, Err: errors.New("connection reset"), Idempotent: true})
+		assert.True(t, retry)
+	})
+
+	t.Run("not retryable status codes", func(t *testing.T) {
+		for _, statusCode := range []int{
+			http.StatusOK, http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound, http.StatusNotImplemented,
+		} {
+			_, retry := policy.Backoff(&RetryAttempt{Attempt: 1, Idempotent: true, StatusCode: statusCode})
+			assert.False(t, retry, statusCode)
+		}
+	})
+
+	t.Run("retry after header", func(t *testing.T) {
+		delay, retry := policy.Backoff(&RetryAttempt{
+			Attempt: 1, Header: http.Header{"Retry-After": []string{"3"}},
+			Idempotent: true, StatusCode: http.StatusTooManyRequests,
+		})
+		assert.True(t, retry)
+		assert.Equal(t, 3*time.Second, delay)
+	})
+
+	t.Run("retry after exceeds the maximum", func(t *testing.T) {
+		_, retry := policy.Backoff(&RetryAttempt{
+			Attempt: 1, Header: http.Header{"Retry-After": []string{"3600"}},
+			Idempotent: true, StatusCode: http.StatusTooManyRequests,
+		})
+		assert.False(t, retry)
+	})
+}
+
+// TestRetryAfter will test the method retryAfter()
+func TestRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	var tests = []struct {
+		value         string
+		expectedDelay time.Duration
+		expectedOk    bool
+	}{
+		{"", 0, false},
+		{"0", 0, true},
+		{"120", 2 * time.Minute, true},
+		{"-1", 0, false},
+		{"Tue, 01 Jun 2021 12:00:30 GMT", 30 * time.Second, true},
+		{"Tue, 01 Jun 2021 11:00:00 GMT", 0, true},
+		{"invalid", 0, false},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("value %q", test.value), func(t *testing.T) {
+			delay, ok := retryAfter(http.Header{"Retry-After": []string{test.value}}, now)
+			assert.Equal(t, test.expectedOk, ok)
+			assert.Equal(t, test.expectedDelay, delay)
+		})
+	}
+}
+
+// TestRateLimiter will test the token bucket rate limiter
+func TestRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("burst then wait for the next token", func(t *testing.T) {
+		now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+		limiter := newRateLimiter(2, 2)
+		limiter.now = func() time.Time { return now }
+
+		assert.Equal(t, time.Duration(0), limiter.reserve())
+		assert.Equal(t, time.Duration(0), limiter.reserve())
+		assert.Equal(t, 500*time.Millisecond, limiter.reserve())
+
+		now = now.Add(500 * time.Millisecond)
+		assert.Equal(t, time.Duration(0), limiter.reserve())
+	})
+
+	t.Run("paused", func(t *testing.T) {
+		now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+		limiter := newRateLimiter(10, 10)
+		limiter.now = func() time.Time { return now }
+
+		limiter.pause(3 * time.Second)
+		assert.Equal(t, 3*time.Second, limiter.reserve())
+
+		now = now.Add(3 * time.Second)
+		assert.Equal(t, time.Duration(0), limiter.reserve())
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		limiter := newRateLimiter(0.001, 1)
+		assert.NoError(t, limiter.wait(context.Background()))
+		assert.ErrorIs(t, limiter.wait(newCanceledContext()), context.Canceled)
+	})
+}
+
+// TestClient_RequestRetries will test the retries of the method RequestWithOptions()
+func TestClient_RequestRetries(t *testing.T) {
+	// t.Parallel() (Cannot run in parallel - issues with overriding the mock client)
+
+	path := fmt.Sprintf("/%s/details/%d", modelGoal, testGoalID)
+	endpoint := EnvironmentDevelopment.apiURL + path
+
+	t.Run("retry an idempotent request until success", func(t *testing.T) {
+		client, err := newRetryTestClient(WithRetryPolicy(newTestRetryPolicy(2)))
+		require.NoError(t, err)
+
+		calls := mockResponseSequence(
+			http.MethodGet, endpoint, nil, http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK,
+		)
+		var response *StandardResponse
+		response, err = client.Request(http.MethodGet, path, nil, http.StatusOK)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, 3, calls())
+	})
+
+	t.Run("give up after max retries", func(t *testing.T) {
+		client, err := newRetryTestClient(WithRetryPolicy(newTestRetryPolicy(2)))
+		require.NoError(t, err)
+
+		calls := mockResponseSequence(http.MethodGet, endpoint, nil, http.StatusServiceUnavailable)
+		var response *StandardResponse
+		response, err = client.Request(http.MethodGet, path, nil, http.StatusOK)
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusServiceUnavailable, response.StatusCode)
+		assert.Equal(t, 3, calls())
+	})
+
+	t.Run("honor retry after", func(t *testing.T) {
+		client, err := newRetryTestClient(WithRetryPolicy(newTestRetryPolicy(1)))
+		require.NoError(t, err)
+
+		calls := mockResponseSequence(
+			http.MethodGet, endpoint, http.Header{"Retry-After": []string{"1"}},
+			http.StatusTooManyRequests, http.StatusOK,
+		)
+		start := time.Now()
+		_, err = client.Request(http.MethodGet, path, nil, http.StatusOK)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, calls())
+		assert.GreaterOrEqual(t, time.Since(start), time.Second)
+	})
+
+	t.Run("retries of a custom http client are turned off", func(t *testing.T) {
+		client, err := newRetryTestClient(WithRetryPolicy(newTestRetryPolicy(2)))
+		require.NoError(t, err)
+		customHTTPClient := resty.New().SetRetryCount(3).SetRetryWaitTime(time.Millisecond).
+			SetRetryMaxWaitTime(time.Millisecond).AddRetryCondition(func(*resty.Response, error) bool { return true })
+		httpmock.ActivateNonDefault(customHTTPClient.GetClient())
+		client.WithCustomHTTPClient(customHTTPClient)
+		assert.Equal(t, 0, customHTTPClient.RetryCount)
+
+		calls := mockResponseSequence(http.MethodGet, endpoint, nil, http.StatusServiceUnavailable)
+		_, err = client.Request(http.MethodGet, path, nil, http.StatusOK)
+		assert.Error(t, err)
+		assert.Equal(t, 3, calls())
+	})
+
+	t.Run("do not retry a post by default", func(t *testing.T) {
+		client, err := newRetryTestClient(WithRetryPolicy(newTestRetryPolicy(2)))
+		require.NoError(t, err)
+
+		calls := mockResponseSequence(
+			http.MethodPost, EnvironmentDevelopment.apiURL+"/"+modelGoal, nil, http.StatusServiceUnavailable,
+		)
+		_, err = client.CreateGoal(newTestGoal())
+		assert.Error(t, err)
+		assert.Equal(t, 1, calls())
+	})
+
+	t.Run("retry a post if enabled", func(t *testing.T) {
+		client, err := newRetryTestClient(WithRetryPolicy(newTestRetryPolicy(2)), WithRetryNonIdempotent())
+		require.NoError(t, err)
+
+		calls := mockResponseSequence(
+			http.MethodPost, EnvironmentDevelopment.apiURL+"/"+modelGoal, nil,
+			http.StatusServiceUnavailable, http.StatusCreated,
+		)
+		_, err = client.CreateGoal(newTestGoal())
+		assert.NoError(t, err)
+		assert.Equal(t, 2, calls())
+	})
+
+	t.Run("do not retry a client error", func(t *testing.T) {
+		client, err := newRetryTestClient(WithRetryPolicy(newTestRetryPolicy(2)))
+		require.NoError(t, err)
+
+		calls := mockResponseSequence(http.MethodGet, endpoint, nil, http.StatusNotFound)
+		_, _, err = client.GetGoal(testGoalID)
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Equal(t, 1, calls())
+	})
+
+	t.Run("stop retrying when the context is done", func(t *testing.T) {
+		client, err := newRetryTestClient(WithRetryPolicy(&BackoffPolicy{
+			BaseDelay: time.Minute, MaxDelay: time.Minute, MaxRetries: 2,
+		}))
+		require.NoError(t, err)
+
+		calls := mockResponseSequence(http.MethodGet, endpoint, nil, http.StatusServiceUnavailable)
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, _, err = client.GetGoalWithContext(ctx, testGoalID)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 1, calls())
+	})
+
+	t.Run("rate limiter is paused on 429", func(t *testing.T) {
+		client, err := newRetryTestClient(WithRetryCount(0), WithRateLimit(100, 10))
+		require.NoError(t, err)
+
+		mockResponseSequence(
+			http.MethodGet, endpoint, http.Header{"Retry-After": []string{"60"}}, http.StatusTooManyRequests,
+		)
+		_, _, err = client.GetGoal(testGoalID)
+		assert.ErrorIs(t, err, ErrRateLimited)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, _, err = client.GetGoalWithContext(ctx, testGoalID)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+// TestNewClient_RetryOptions will test the retry & rate limit options of NewClient()
+func TestNewClient_RetryOptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("default retry policy", func(t *testing.T) {
+		client, err := NewClient(WithAPIKey(testAPIKey), WithRetryCount(3))
+		require.NoError(t, err)
+		policy, ok := client.Options().retryPolicy.(*BackoffPolicy)
+		require.True(t, ok)
+		assert.Equal(t, 3, policy.MaxRetries)
+		assert.Nil(t, client.(*Client).limiter)
+	})
+
+	t.Run("custom retry policy and rate limit", func(t *testing.T) {
+		policy := newTestRetryPolicy(1)
+		client, err := NewClient(
+			WithAPIKey(testAPIKey), WithRetryPolicy(policy), WithRetryNonIdempotent(), WithRateLimit(5, 10),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, policy, client.Options().retryPolicy)
+		assert.True(t, client.Options().retryNonIdempotent)
+		assert.NotNil(t, client.(*Client).limiter)
+	})
+}
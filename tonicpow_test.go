@@ -1,6 +1,7 @@
 package tonicpow
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -148,3 +149,19 @@ func mockResponseFeed(endpoint string, statusCode int, feedResults string) {
 	httpmock.Reset()
 	httpmock.RegisterResponder(http.MethodGet, endpoint, httpmock.NewStringResponder(statusCode, feedResults))
 }
+
+// mockResponseBlocking is used for mocking a response that never returns until the request context is done
+func mockResponseBlocking(method, endpoint string) {
+	httpmock.Reset()
+	httpmock.RegisterResponder(method, endpoint, func(req *http.Request) (*http.Response, error) {
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	})
+}
+
+// newCanceledContext will return a context that is already canceled
+func newCanceledContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}
@@ -0,0 +1,218 @@
+// Package tonicpowotel provides OpenTelemetry instrumentation (tracing & metrics) for the TonicPow client
+//
+// Every API call (all attempts of a request) is a client span named after the service method
+// (tonicpow.GetCampaign) with the campaign, goal and conversion IDs as attributes.
+// The duration, errors (by Error.Code) and retries of every call are recorded as metrics.
+//
+//	client, err := tonicpow.NewClient(
+//		tonicpow.WithAPIKey(apiKey),
+//		tonicpowotel.WithInstrumentation(),
+//	)
+//
+// If you have any suggestions or comments, please feel free to open an issue on
+// this GitHub repository!
+//
+// By TonicPow Inc (https://tonicpow.com)
+package tonicpowotel
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tonicpow/go-tonicpow"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// ScopeName is the instrumentation scope of the tracer and meter
+	ScopeName = "github.com/tonicpow/go-tonicpow/tonicpowotel"
+
+	// spanPrefix is the prefix of the span names (tonicpow.GetCampaign)
+	spanPrefix = "tonicpow."
+)
+
+// Metric names
+const (
+	MetricDuration = "tonicpow.client.duration" // Histogram of the call duration (seconds)
+	MetricErrors   = "tonicpow.client.errors"   // Counter of failed calls (by error code)
+	MetricRetries  = "tonicpow.client.retries"  // Counter of retried attempts
+)
+
+// Attribute keys
+const (
+	AttributeAdvertiserID = attribute.Key("tonicpow.advertiser.id")
+	AttributeAttempts     = attribute.Key("tonicpow.attempts")
+	AttributeCached       = attribute.Key("tonicpow.cached")
+	AttributeCampaignID   = attribute.Key("tonicpow.campaign.id")
+	AttributeConversionID = attribute.Key("tonicpow.conversion.id")
+	AttributeEndpoint     = attribute.Key("tonicpow.endpoint")
+	AttributeErrorCode    = attribute.Key("tonicpow.error.code")
+	AttributeGoalID       = attribute.Key("tonicpow.goal.id")
+	AttributeMethod       = attribute.Key("http.request.method")
+	AttributeOperation    = attribute.Key("tonicpow.operation")
+	AttributeStatusCode   = attribute.Key("http.response.status_code")
+)
+
+// config is the configuration of the instrumentation
+type config struct {
+	meterProvider  metric.MeterProvider
+	tracerProvider trace.TracerProvider
+}
+
+// Option is a functional option for the instrumentation
+type Option func(c *config)
+
+// WithTracerProvider will set the tracer provider (default is the global provider)
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider will set the meter provider (default is the global provider)
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = provider
+	}
+}
+
+// instrumentation holds the tracer and the metric instruments
+type instrumentation struct {
+	duration metric.Float64Histogram
+	errors   metric.Int64Counter
+	retries  metric.Int64Counter
+	tracer   trace.Tracer
+}
+
+// WithInstrumentation will instrument the client (see NewMiddleware)
+//
+// If the metric instruments cannot be created, the client is instrumented without metrics
+func WithInstrumentation(opts ...Option) tonicpow.ClientOps {
+	middleware, err := NewMiddleware(opts...)
+	if err != nil {
+		otel.Handle(err)
+	}
+	return tonicpow.WithMiddleware(middleware)
+}
+
+// NewMiddleware will return the middleware that emits a span and metrics for every API call
+//
+// Use WithInstrumentation or tonicpow.WithMiddleware to add the middleware to the client
+func NewMiddleware(opts ...Option) (tonicpow.RequestMiddleware, error) {
+	c := &config{
+		meterProvider:  otel.GetMeterProvider(),
+		tracerProvider: otel.GetTracerProvider(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	i := &instrumentation{tracer: c.tracerProvider.Tracer(ScopeName)}
+	meter := c.meterProvider.Meter(ScopeName)
+
+	var err, instrumentErr error
+	if i.duration, instrumentErr = meter.Float64Histogram(MetricDuration,
+		metric.WithDescription("Duration of the TonicPow API calls (including retries)"),
+		metric.WithUnit("s"),
+	); instrumentErr != nil {
+		err = errors.Join(err, instrumentErr)
+	}
+	if i.errors, instrumentErr = meter.Int64Counter(MetricErrors,
+		metric.WithDescription("Number of failed TonicPow API calls"),
+		metric.WithUnit("{call}"),
+	); instrumentErr != nil {
+		err = errors.Join(err, instrumentErr)
+	}
+	if i.retries, instrumentErr = meter.Int64Counter(MetricRetries,
+		metric.WithDescription("Number of retried TonicPow API requests"),
+		metric.WithUnit("{request}"),
+	); instrumentErr != nil {
+		err = errors.Join(err, instrumentErr)
+	}
+	return i.middleware, err
+}
+
+// middleware will wrap the API call with a span and record the metrics
+func (i *instrumentation) middleware(next tonicpow.RequestHandler) tonicpow.RequestHandler {
+	return func(ctx context.Context, call *tonicpow.Call) (*tonicpow.StandardResponse, error) {
+		ctx, span := i.tracer.Start(ctx, spanPrefix+call.Operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(append(callAttributes(call),
+				AttributeMethod.String(call.Method),
+				AttributeEndpoint.String(call.Endpoint),
+			)...),
+		)
+		defer span.End()
+
+		start := time.Now()
+		response, err := next(ctx, call)
+		duration := time.Since(start)
+
+		// Span attributes & status
+		metricAttributes := []attribute.KeyValue{
+			AttributeOperation.String(call.Operation),
+			AttributeMethod.String(call.Method),
+		}
+		span.SetAttributes(AttributeAttempts.Int(call.Attempts))
+		if response != nil {
+			span.SetAttributes(
+				AttributeStatusCode.Int(response.StatusCode),
+				AttributeCached.Bool(response.Cached),
+			)
+			metricAttributes = append(metricAttributes, AttributeStatusCode.Int(response.StatusCode))
+		}
+		if err != nil {
+			code := errorCode(err)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			span.SetAttributes(AttributeErrorCode.Int(code))
+			if i.errors != nil {
+				i.errors.Add(ctx, 1, metric.WithAttributes(append(metricAttributes, AttributeErrorCode.Int(code))...))
+			}
+		}
+
+		// Metrics
+		if i.duration != nil {
+			i.duration.Record(ctx, duration.Seconds(), metric.WithAttributes(metricAttributes...))
+		}
+		if i.retries != nil && call.Attempts > 1 {
+			i.retries.Add(ctx, int64(call.Attempts-1), metric.WithAttributes(metricAttributes...))
+		}
+		return response, err
+	}
+}
+
+// errorCode will return the Error.Code of an API error (or the status code), 0 if the call failed without a response
+func errorCode(err error) int {
+	var apiError *tonicpow.Error
+	if !errors.As(err, &apiError) {
+		return 0
+	} else if apiError.Code != 0 {
+		return apiError.Code
+	}
+	return apiError.StatusCode
+}
+
+// callAttributes will return the advertiser, campaign, conversion & goal IDs of the call (see tonicpow.CallIDs)
+func callAttributes(call *tonicpow.Call) []attribute.KeyValue {
+	attributes := make([]attribute.KeyValue, 0, 4)
+	for _, id := range []struct {
+		key   attribute.Key
+		value uint64
+	}{
+		{AttributeAdvertiserID, call.IDs.AdvertiserProfileID},
+		{AttributeCampaignID, call.IDs.CampaignID},
+		{AttributeConversionID, call.IDs.ConversionID},
+		{AttributeGoalID, call.IDs.GoalID},
+	} {
+		if id.value > 0 {
+			attributes = append(attributes, id.key.Int64(int64(id.value)))
+		}
+	}
+	return attributes
+}
@@ -0,0 +1,228 @@
+package tonicpowotel
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tonicpow/go-tonicpow"
+	"github.com/tonicpow/go-tonicpow/tonicpowtest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newTestClient will return an instrumented client for a seeded test server (campaign 3 & goal 4)
+func newTestClient(t *testing.T) (*tonicpowtest.Server, tonicpow.ClientInterface,
+	*tracetest.SpanRecorder, *sdkmetric.ManualReader) {
+	server := tonicpowtest.NewServer()
+	t.Cleanup(server.Close)
+
+	profile := server.AddAdvertiserProfile(&tonicpow.AdvertiserProfile{Name: "TonicPow", UserID: 43})
+	server.AddApp(&tonicpow.App{AdvertiserProfileID: profile.ID, Name: "TonicPow App", UserID: 43})
+	server.AddCampaign(&tonicpow.Campaign{
+		AdvertiserProfileID: profile.ID,
+		BalanceSatoshis:     20000000,
+		Goals:               []*tonicpow.Goal{{Name: "signup", PayoutRate: tonicpow.NewDecimal(5, 1), PayoutType: "flat"}},
+		TargetType:          "url",
+		TargetURL:           "https://tonicpow.com",
+		Title:               "TonicPow",
+	})
+
+	recorder := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	policy := tonicpow.NewBackoffPolicy(2)
+	policy.BaseDelay = time.Millisecond
+	policy.MaxDelay = time.Millisecond
+
+	client, err := server.NewClient(
+		tonicpow.WithRetryPolicy(policy),
+		WithInstrumentation(
+			WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))),
+			WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+		),
+	)
+	require.NoError(t, err)
+	return server, client, recorder, reader
+}
+
+// spanAttributes will return the attributes of the span as a map
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attributes := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attributes[kv.Key] = kv.Value
+	}
+	return attributes
+}
+
+// collectMetrics will return the metrics by name
+func collectMetrics(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Metrics {
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+
+	metrics := make(map[string]metricdata.Metrics)
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			metrics[m.Name] = m
+		}
+	}
+	return metrics
+}
+
+// TestNewMiddleware will test the spans and metrics of the API calls
+func TestNewMiddleware(t *testing.T) {
+	t.Parallel()
+
+	t.Run("span per call", func(t *testing.T) {
+		_, client, recorder, _ := newTestClient(t)
+
+		_, _, err := client.GetCampaign(3)
+		require.NoError(t, err)
+		_, _, err = client.GetGoal(4)
+		require.NoError(t, err)
+		_, _, err = client.CreateConversion(tonicpow.WithGoalID(4), tonicpow.WithTncpwSession("session"))
+		require.NoError(t, err)
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 3)
+
+		assert.Equal(t, "tonicpow.GetCampaign", spans[0].Name())
+		assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind())
+		attributes := spanAttributes(spans[0])
+		assert.Equal(t, int64(3), attributes[AttributeCampaignID].AsInt64())
+		assert.Equal(t, http.MethodGet, attributes[AttributeMethod].AsString())
+		assert.Equal(t, int64(http.StatusOK), attributes[AttributeStatusCode].AsInt64())
+		assert.Equal(t, int64(1), attributes[AttributeAttempts].AsInt64())
+		assert.Equal(t, codes.Unset, spans[0].Status().Code)
+
+		assert.Equal(t, "tonicpow.GetGoal", spans[1].Name())
+		assert.Equal(t, int64(4), spanAttributes(spans[1])[AttributeGoalID].AsInt64())
+
+		assert.Equal(t, "tonicpow.CreateConversion", spans[2].Name())
+		assert.Equal(t, int64(4), spanAttributes(spans[2])[AttributeGoalID].AsInt64())
+	})
+
+	t.Run("nested list endpoints", func(t *testing.T) {
+		_, client, recorder, _ := newTestClient(t)
+
+		_, _, err := client.CreateConversion(tonicpow.WithGoalID(4), tonicpow.WithTncpwSession("session"))
+		require.NoError(t, err)
+		_, _, _ = client.ListConversionsByCampaign(3, nil)
+		_, _, _ = client.ListConversionsByGoal(4, nil)
+		_, _, _ = client.ListConversionsByUser(43, nil)
+		_, _, _ = client.ListGoalsByCampaign(3, nil)
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 5)
+		expected := []struct {
+			name       string
+			attributes map[attribute.Key]int64
+		}{
+			{"tonicpow.ListConversionsByCampaign", map[attribute.Key]int64{AttributeCampaignID: 3}},
+			{"tonicpow.ListConversionsByGoal", map[attribute.Key]int64{AttributeGoalID: 4}},
+			{"tonicpow.ListConversionsByUser", map[attribute.Key]int64{}},
+			{"tonicpow.ListGoalsByCampaign", map[attribute.Key]int64{AttributeCampaignID: 3}},
+		}
+		for i, test := range expected {
+			span := spans[i+1]
+			assert.Equal(t, test.name, span.Name())
+			attributes := spanAttributes(span)
+			for _, key := range []attribute.Key{
+				AttributeAdvertiserID, AttributeCampaignID, AttributeConversionID, AttributeGoalID,
+			} {
+				id, ok := test.attributes[key]
+				assert.Equal(t, ok, attributes[key].Type() != attribute.INVALID, "%s %s", test.name, key)
+				if ok {
+					assert.Equal(t, id, attributes[key].AsInt64(), "%s %s", test.name, key)
+				}
+			}
+		}
+	})
+
+	t.Run("errors and retries", func(t *testing.T) {
+		server, client, recorder, reader := newTestClient(t)
+
+		server.FailNext(1, http.StatusBadGateway, nil)
+		_, _, err := client.GetCampaign(3)
+		require.NoError(t, err)
+
+		_, _, err = client.GetCampaign(999)
+		require.True(t, errors.Is(err, tonicpow.ErrNotFound))
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 2)
+		assert.Equal(t, int64(2), spanAttributes(spans[0])[AttributeAttempts].AsInt64())
+		assert.Equal(t, codes.Error, spans[1].Status().Code)
+		assert.Equal(t, int64(http.StatusNotFound), spanAttributes(spans[1])[AttributeErrorCode].AsInt64())
+		require.Len(t, spans[1].Events(), 1)
+		assert.Equal(t, "exception", spans[1].Events()[0].Name)
+
+		metrics := collectMetrics(t, reader)
+
+		duration, ok := metrics[MetricDuration].Data.(metricdata.Histogram[float64])
+		require.True(t, ok)
+		var calls uint64
+		for _, point := range duration.DataPoints {
+			calls += point.Count
+		}
+		assert.Equal(t, uint64(2), calls)
+
+		retries, ok := metrics[MetricRetries].Data.(metricdata.Sum[int64])
+		require.True(t, ok)
+		require.Len(t, retries.DataPoints, 1)
+		assert.Equal(t, int64(1), retries.DataPoints[0].Value)
+
+		errorCounts, ok := metrics[MetricErrors].Data.(metricdata.Sum[int64])
+		require.True(t, ok)
+		require.Len(t, errorCounts.DataPoints, 1)
+		assert.Equal(t, int64(1), errorCounts.DataPoints[0].Value)
+		code, _ := errorCounts.DataPoints[0].Attributes.Value(AttributeErrorCode)
+		assert.Equal(t, int64(http.StatusNotFound), code.AsInt64())
+		operation, _ := errorCounts.DataPoints[0].Attributes.Value(AttributeOperation)
+		assert.Equal(t, "GetCampaign", operation.AsString())
+	})
+
+	t.Run("default providers", func(t *testing.T) {
+		middleware, err := NewMiddleware()
+		require.NoError(t, err)
+		assert.NotNil(t, middleware)
+	})
+}
+
+// TestCallAttributes will test the IDs of the calls used as attributes
+func TestCallAttributes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		call     *tonicpow.Call
+		expected []attribute.KeyValue
+	}{
+		{"campaign", &tonicpow.Call{IDs: tonicpow.CallIDs{CampaignID: 23}},
+			[]attribute.KeyValue{AttributeCampaignID.Int64(23)}},
+		{"conversion", &tonicpow.Call{IDs: tonicpow.CallIDs{ConversionID: 99}},
+			[]attribute.KeyValue{AttributeConversionID.Int64(99)}},
+		{"all ids", &tonicpow.Call{IDs: tonicpow.CallIDs{
+			AdvertiserProfileID: 5, CampaignID: 23, ConversionID: 99, GoalID: 13,
+		}}, []attribute.KeyValue{
+			AttributeAdvertiserID.Int64(5), AttributeCampaignID.Int64(23),
+			AttributeConversionID.Int64(99), AttributeGoalID.Int64(13),
+		}},
+		{"endpoint is not parsed", &tonicpow.Call{Endpoint: "/conversions/campaigns/42?current_page=1"},
+			[]attribute.KeyValue{}},
+		{"no ids", &tonicpow.Call{Endpoint: "/campaigns/list?current_page=1"}, []attribute.KeyValue{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, callAttributes(test.call))
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package tonicpowtest
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/tonicpow/go-tonicpow"
+)
+
+// routeAdvertisers will route all the /advertisers requests
+func (s *Server) routeAdvertisers(w http.ResponseWriter, r *request) {
+	switch {
+	case r.is(http.MethodPost):
+		s.createAdvertiserProfile(w, r)
+	case r.is(http.MethodPut):
+		s.updateAdvertiserProfile(w, r)
+	case r.is(http.MethodGet, "details", "*"):
+		s.getAdvertiserProfile(w, r)
+	case r.is(http.MethodGet, "campaigns", "*"):
+		s.listCampaignsByAdvertiserProfile(w, r)
+	case r.is(http.MethodGet, "apps"):
+		s.listAppsByAdvertiserProfile(w, r)
+	case r.is(http.MethodGet, "list"):
+		s.listAdvertiserProfiles(w, r)
+	default:
+		s.writeError(w, r.req, http.StatusNotFound, "route not found", r.req.URL.Path)
+	}
+}
+
+// createAdvertiserProfile will create a new advertiser profile
+func (s *Server) createAdvertiserProfile(w http.ResponseWriter, r *request) {
+	profile := new(tonicpow.AdvertiserProfile)
+	if err := r.decode(profile); err != nil {
+		s.writeError(w, r.req, http.StatusBadRequest, err.Error(), nil)
+		return
+	} else if len(profile.Name) == 0 {
+		s.writeMissing(w, r.req, "name")
+		return
+	}
+
+	// Store the profile (owned by the same user as the seeded profiles)
+	profile.ID = s.nextID()
+	profile.DomainVerified = false
+	profile.PublicGUID = newID()
+	profile.UserID = s.advertiserUserID()
+	s.advertisers[profile.ID] = profile
+	s.writeJSON(w, http.StatusCreated, profile)
+}
+
+// advertiserUserID will return the user that owns the existing advertiser profiles
+func (s *Server) advertiserUserID() uint64 {
+	for _, id := range sortedKeys(s.advertisers) {
+		if s.advertisers[id].UserID > 0 {
+			return s.advertisers[id].UserID
+		}
+	}
+	return 0
+}
+
+// listAdvertiserProfiles will return a page of advertiser profiles
+func (s *Server) listAdvertiserProfiles(w http.ResponseWriter, r *request) {
+	p := newPagination(r.req.URL.Query())
+	switch p.sortBy {
+	case "", "created_at", "name":
+	default:
+		s.writeError(w, r.req, http.StatusBadRequest, "sort by "+p.sortBy+" is not valid", "sort_by")
+		return
+	}
+
+	profiles := make([]*tonicpow.AdvertiserProfile, 0, len(s.advertisers))
+	for _, id := range sortedKeys(s.advertisers) {
+		profiles = append(profiles, clone(s.advertisers[id]))
+	}
+	profiles = paginate(p, profiles, func(a, b *tonicpow.AdvertiserProfile) bool {
+		if p.sortBy == "name" {
+			return a.Name < b.Name
+		}
+		return a.ID < b.ID
+	})
+	if len(profiles) == 0 {
+		s.writeNotFound(w, r.req, "advertiser profiles")
+		return
+	}
+	s.writeJSON(w, http.StatusOK, &tonicpow.AdvertiserResults{
+		Advertisers:    profiles,
+		CurrentPage:    p.currentPage,
+		Results:        len(profiles),
+		ResultsPerPage: p.resultsPerPage,
+	})
+}
+
+// getAdvertiserProfile will return an advertiser profile by id
+func (s *Server) getAdvertiserProfile(w http.ResponseWriter, r *request) {
+	id, _ := strconv.ParseUint(r.segment(2), 10, 64)
+	profile, ok := s.advertisers[id]
+	if !ok {
+		s.writeNotFound(w, r.req, "advertiser profile")
+		return
+	}
+	s.writeJSON(w, http.StatusOK, profile)
+}
+
+// updateAdvertiserProfile will update an existing advertiser profile (only the fields sent are changed)
+func (s *Server) updateAdvertiserProfile(w http.ResponseWriter, r *request) {
+	var id struct {
+		ID uint64 `json:"id"`
+	}
+	if err := r.decode(&id); err != nil {
+		s.writeError(w, r.req, http.StatusBadRequest, err.Error(), nil)
+		return
+	} else if id.ID == 0 {
+		s.writeMissing(w, r.req, "id")
+		return
+	}
+	existing, ok := s.advertisers[id.ID]
+	if !ok {
+		s.writeNotFound(w, r.req, "advertiser profile")
+		return
+	}
+
+	// Apply the changes on a copy
+	profile := clone(existing)
+	if err := r.decode(profile); err != nil {
+		s.writeError(w, r.req, http.StatusBadRequest, err.Error(), nil)
+		return
+	} else if len(profile.Name) == 0 {
+		s.writeMissing(w, r.req, "name")
+		return
+	}
+
+	// Read-only fields
+	profile.DomainVerified = existing.DomainVerified
+	profile.PublicGUID = existing.PublicGUID
+	profile.UserID = existing.UserID
+
+	s.advertisers[profile.ID] = profile
+	s.writeJSON(w, http.StatusOK, profile)
+}
+
+// listCampaignsByAdvertiserProfile will return a page of campaigns for the advertiser profile
+func (s *Server) listCampaignsByAdvertiserProfile(w http.ResponseWriter, r *request) {
+	id, _ := strconv.ParseUint(r.segment(2), 10, 64)
+	if _, ok := s.advertisers[id]; !ok {
+		s.writeNotFound(w, r.req, "advertiser profile")
+		return
+	}
+	s.writeCampaignResults(w, r, func(campaign *tonicpow.Campaign) bool {
+		return campaign.AdvertiserProfileID == id
+	})
+}
+
+// listAppsByAdvertiserProfile will return a page of apps for the advertiser profile
+func (s *Server) listAppsByAdvertiserProfile(w http.ResponseWriter, r *request) {
+	id, _ := strconv.ParseUint(r.req.URL.Query().Get("id"), 10, 64)
+	if _, ok := s.advertisers[id]; !ok {
+		s.writeNotFound(w, r.req, "advertiser profile")
+		return
+	}
+
+	p := newPagination(r.req.URL.Query())
+	switch p.sortBy {
+	case "", "created_at", "name":
+	default:
+		s.writeError(w, r.req, http.StatusBadRequest, "sort by "+p.sortBy+" is not valid", "sort_by")
+		return
+	}
+
+	var apps []*tonicpow.App
+	for _, appID := range sortedKeys(s.apps) {
+		if s.apps[appID].AdvertiserProfileID == id {
+			apps = append(apps, clone(s.apps[appID]))
+		}
+	}
+	apps = paginate(p, apps, func(a, b *tonicpow.App) bool {
+		if p.sortBy == "name" {
+			return a.Name < b.Name
+		}
+		return a.ID < b.ID
+	})
+	if len(apps) == 0 {
+		s.writeNotFound(w, r.req, "apps")
+		return
+	}
+	s.writeJSON(w, http.StatusOK, &tonicpow.AppResults{
+		Apps:           apps,
+		CurrentPage:    p.currentPage,
+		Results:        len(apps),
+		ResultsPerPage: p.resultsPerPage,
+	})
+}
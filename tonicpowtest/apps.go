@@ -0,0 +1,202 @@
+package tonicpowtest
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/tonicpow/go-tonicpow"
+)
+
+// routeApps will route all the /apps requests
+func (s *Server) routeApps(w http.ResponseWriter, r *request) {
+	switch {
+	case r.is(http.MethodPost):
+		s.createApp(w, r)
+	case r.is(http.MethodPut):
+		s.updateApp(w, r)
+	case r.is(http.MethodDelete):
+		s.deleteApp(w, r)
+	case r.is(http.MethodGet, "details", "*"):
+		s.getApp(w, r)
+	case r.is(http.MethodPost, "webhook", "test"):
+		s.testAppWebhook(w, r)
+	default:
+		s.writeError(w, r.req, http.StatusNotFound, "route not found", r.req.URL.Path)
+	}
+}
+
+// createApp will create a new app
+func (s *Server) createApp(w http.ResponseWriter, r *request) {
+	app := new(tonicpow.App)
+	if err := r.decode(app); err != nil {
+		s.writeError(w, r.req, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	// Validate the app
+	profile, ok := s.advertisers[app.AdvertiserProfileID]
+	if app.AdvertiserProfileID == 0 {
+		s.writeMissing(w, r.req, "advertiser_profile_id")
+		return
+	} else if !ok {
+		s.writeNotFound(w, r.req, "advertiser profile")
+		return
+	} else if len(app.Name) == 0 {
+		s.writeMissing(w, r.req, "name")
+		return
+	} else if !validWebhookURL(app.WebhookURL) {
+		s.writeError(w, r.req, http.StatusBadRequest, "webhook_url is not valid", "webhook_url")
+		return
+	}
+
+	// Store the app (owned by the user of the advertiser profile)
+	app.ID = s.nextID()
+	app.UserID = profile.UserID
+	s.apps[app.ID] = app
+	s.writeJSON(w, http.StatusCreated, app)
+}
+
+// updateApp will update an existing app (only the fields sent are changed)
+func (s *Server) updateApp(w http.ResponseWriter, r *request) {
+	var id struct {
+		ID uint64 `json:"id"`
+	}
+	if err := r.decode(&id); err != nil {
+		s.writeError(w, r.req, http.StatusBadRequest, err.Error(), nil)
+		return
+	} else if id.ID == 0 {
+		s.writeMissing(w, r.req, "id")
+		return
+	}
+	existing, ok := s.apps[id.ID]
+	if !ok {
+		s.writeNotFound(w, r.req, "app")
+		return
+	}
+
+	// Apply the changes on a copy
+	app := clone(existing)
+	if err := r.decode(app); err != nil {
+		s.writeError(w, r.req, http.StatusBadRequest, err.Error(), nil)
+		return
+	} else if len(app.Name) == 0 {
+		s.writeMissing(w, r.req, "name")
+		return
+	} else if !validWebhookURL(app.WebhookURL) {
+		s.writeError(w, r.req, http.StatusBadRequest, "webhook_url is not valid", "webhook_url")
+		return
+	}
+
+	// Read-only fields
+	app.AdvertiserProfileID = existing.AdvertiserProfileID
+	app.UserID = existing.UserID
+
+	s.apps[app.ID] = app
+	s.writeJSON(w, http.StatusOK, app)
+}
+
+// deleteApp will delete an app
+func (s *Server) deleteApp(w http.ResponseWriter, r *request) {
+	id, _ := strconv.ParseUint(r.req.URL.Query().Get("id"), 10, 64)
+	if id == 0 {
+		s.writeMissing(w, r.req, "id")
+		return
+	} else if _, ok := s.apps[id]; !ok {
+		s.writeNotFound(w, r.req, "app")
+		return
+	}
+	delete(s.apps, id)
+	w.WriteHeader(http.StatusOK)
+}
+
+// getApp will return an app by id
+func (s *Server) getApp(w http.ResponseWriter, r *request) {
+	id, _ := strconv.ParseUint(r.segment(2), 10, 64)
+	app, ok := s.apps[id]
+	if !ok {
+		s.writeNotFound(w, r.req, "app")
+		return
+	}
+	s.writeJSON(w, http.StatusOK, app)
+}
+
+// testAppWebhook will deliver a signed sample event to the webhook url of the app and return the result
+//
+// The delivery is made without holding the lock, so the webhook receiver can call the server
+func (s *Server) testAppWebhook(w http.ResponseWriter, r *request) {
+	var test struct {
+		EventType tonicpow.WebhookEventType `json:"event_type"`
+		ID        uint64                    `json:"id"`
+	}
+	if err := r.decode(&test); err != nil {
+		s.writeError(w, r.req, http.StatusBadRequest, err.Error(), nil)
+		return
+	} else if test.ID == 0 {
+		s.writeMissing(w, r.req, "id")
+		return
+	}
+	app, ok := s.apps[test.ID]
+	if !ok {
+		s.writeNotFound(w, r.req, "app")
+		return
+	} else if len(app.WebhookURL) == 0 {
+		s.writeMissing(w, r.req, "webhook_url")
+		return
+	} else if len(test.EventType) == 0 {
+		test.EventType = tonicpow.WebhookEventConversionCreated
+	}
+
+	event, err := newSampleWebhookEvent(test.EventType)
+	if err != nil {
+		s.writeError(w, r.req, http.StatusBadRequest, err.Error(), "event_type")
+		return
+	}
+	var req *http.Request
+	if req, err = newWebhookRequest(app.WebhookURL, s.webhookSecret, event, s.now()); err != nil {
+		s.writeError(w, r.req, http.StatusBadRequest, err.Error(), "webhook_url")
+		return
+	}
+
+	// Deliver the event (a failed delivery is a successful test request), the request is built
+	// from copies of the app & event, so the lock (held by serveHTTP) is released while delivering
+	result := &tonicpow.WebhookTestResult{EventType: test.EventType, WebhookURL: app.WebhookURL}
+	var response *http.Response
+	s.mu.Unlock()
+	response, err = s.webhookClient.Do(req)
+	s.mu.Lock()
+	if err != nil {
+		result.Error = err.Error()
+	} else {
+		_, _ = io.Copy(io.Discard, response.Body)
+		_ = response.Body.Close()
+		result.StatusCode = response.StatusCode
+		result.Success = response.StatusCode >= http.StatusOK && response.StatusCode < http.StatusMultipleChoices
+	}
+	s.writeJSON(w, http.StatusOK, result)
+}
+
+// validWebhookURL will return true if the webhook url is empty or an absolute https url
+// (http is only allowed for localhost) without credentials or a fragment, the same rule as the client
+func validWebhookURL(webhookURL string) bool {
+	if len(webhookURL) == 0 {
+		return true
+	}
+	u, err := url.Parse(webhookURL)
+	if err != nil || len(u.Hostname()) == 0 || u.User != nil || len(u.Fragment) > 0 {
+		return false
+	}
+	return u.Scheme == "https" || (u.Scheme == "http" && isLoopbackHost(u.Hostname()))
+}
+
+// isLoopbackHost will return true if the host is localhost or a loopback ip address
+func isLoopbackHost(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,281 @@
+package tonicpowtest
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tonicpow/go-tonicpow"
+)
+
+// slugInvalidCharacters is used for generating slugs from titles
+var slugInvalidCharacters = regexp.MustCompile(`[^a-z0-9]+`)
+
+// routeCampaigns will route all the /campaigns requests
+func (s *Server) routeCampaigns(w http.ResponseWriter, r *request) {
+	switch {
+	case r.is(http.MethodPost):
+		s.createCampaign(w, r)
+	case r.is(http.MethodPut):
+		s.updateCampaign(w, r)
+	case r.is(http.MethodGet, "details"):
+		s.getCampaign(w, r)
+	case r.is(http.MethodGet, "feed"):
+		s.campaignsFeed(w, r)
+	case r.is(http.MethodGet, "goals", "*"):
+		s.listGoalsByCampaign(w, r)
+	case r.is(http.MethodGet, "list"):
+		s.listCampaigns(w, r)
+	default:
+		s.writeError(w, r.req, http.StatusNotFound, "route not found", r.req.URL.Path)
+	}
+}
+
+// createCampaign will create a new campaign
+func (s *Server) createCampaign(w http.ResponseWriter, r *request) {
+	campaign := new(tonicpow.Campaign)
+	if err := r.decode(campaign); err != nil {
+		s.writeError(w, r.req, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	// Validate the campaign
+	if campaign.AdvertiserProfileID == 0 {
+		s.writeMissing(w, r.req, "advertiser_profile_id")
+		return
+	} else if _, ok := s.advertisers[campaign.AdvertiserProfileID]; !ok {
+		s.writeNotFound(w, r.req, "advertiser profile")
+		return
+	} else if field := validateCampaign(campaign); len(field) > 0 {
+		s.writeMissing(w, r.req, field)
+		return
+	} else if len(campaign.Slug) > 0 && s.campaignBySlug(campaign.Slug) != nil {
+		s.writeError(w, r.req, http.StatusBadRequest, "slug is already in use", "slug")
+		return
+	}
+
+	// Store the campaign
+	campaign.ID = s.nextID()
+	campaign.Goals = nil
+	s.initCampaign(campaign)
+	s.campaigns[campaign.ID] = campaign
+	s.writeJSON(w, http.StatusCreated, s.campaignView(campaign))
+}
+
+// updateCampaign will update an existing campaign (only the fields sent are changed)
+func (s *Server) updateCampaign(w http.ResponseWriter, r *request) {
+	var id struct {
+		ID uint64 `json:"id"`
+	}
+	if err := r.decode(&id); err != nil {
+		s.writeError(w, r.req, http.StatusBadRequest, err.Error(), nil)
+		return
+	} else if id.ID == 0 {
+		s.writeMissing(w, r.req, "id")
+		return
+	}
+	existing, ok := s.campaigns[id.ID]
+	if !ok {
+		s.writeNotFound(w, r.req, "campaign")
+		return
+	}
+
+	// Apply the changes on a copy
+	campaign := clone(existing)
+	if err := r.decode(campaign); err != nil {
+		s.writeError(w, r.req, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	if field := validateCampaign(campaign); len(field) > 0 {
+		s.writeMissing(w, r.req, field)
+		return
+	} else if other := s.campaignBySlug(campaign.Slug); other != nil && other.ID != campaign.ID {
+		s.writeError(w, r.req, http.StatusBadRequest, "slug is already in use", "slug")
+		return
+	}
+
+	// Read-only fields
+	campaign.AdvertiserProfile = nil
+	campaign.AdvertiserProfileID = existing.AdvertiserProfileID
+	campaign.Balance = existing.Balance
+	campaign.BalanceSatoshis = existing.BalanceSatoshis
+	campaign.CreatedAt = existing.CreatedAt
+	campaign.FundingAddress = existing.FundingAddress
+	campaign.Goals = nil
+	campaign.LinksCreated = existing.LinksCreated
+	campaign.PaidClicks = existing.PaidClicks
+	campaign.PaidConversions = existing.PaidConversions
+	campaign.PublicGUID = existing.PublicGUID
+	if len(campaign.Slug) == 0 {
+		campaign.Slug = existing.Slug
+	}
+
+	s.campaigns[campaign.ID] = campaign
+	s.writeJSON(w, http.StatusOK, s.campaignView(campaign))
+}
+
+// getCampaign will return a campaign by id or slug
+func (s *Server) getCampaign(w http.ResponseWriter, r *request) {
+	query := r.req.URL.Query()
+	var campaign *tonicpow.Campaign
+	if slug := query.Get("slug"); len(slug) > 0 {
+		campaign = s.campaignBySlug(slug)
+	} else if id, err := strconv.ParseUint(query.Get("id"), 10, 64); err == nil && id > 0 {
+		campaign = s.campaigns[id]
+	} else {
+		s.writeMissing(w, r.req, "id")
+		return
+	}
+	if campaign == nil {
+		s.writeNotFound(w, r.req, "campaign")
+		return
+	}
+	s.writeJSON(w, http.StatusOK, s.campaignView(campaign))
+}
+
+// listCampaigns will return a page of listed campaigns (filtered by the query string)
+func (s *Server) listCampaigns(w http.ResponseWriter, r *request) {
+	query := r.req.URL.Query()
+	search := strings.ToLower(query.Get("query"))
+	targetURL := query.Get("target_url")
+	includeExpired, _ := strconv.ParseBool(query.Get("expired"))
+	minimumBalance, _ := strconv.ParseUint(query.Get("minimum_balance"), 10, 64)
+
+	s.writeCampaignResults(w, r, func(campaign *tonicpow.Campaign) bool {
+		if campaign.Unlisted && len(targetURL) == 0 {
+			return false
+		} else if len(targetURL) > 0 && campaign.TargetURL != targetURL {
+			return false
+		} else if campaign.BalanceSatoshis < minimumBalance {
+			return false
+		} else if !includeExpired && s.campaignExpired(campaign) {
+			return false
+		} else if len(search) > 0 && !strings.Contains(strings.ToLower(campaign.Title), search) &&
+			!strings.Contains(strings.ToLower(campaign.Description), search) {
+			return false
+		}
+		return true
+	})
+}
+
+// writeCampaignResults will write a page of campaigns that match the filter (404 if empty)
+func (s *Server) writeCampaignResults(w http.ResponseWriter, r *request, filter func(*tonicpow.Campaign) bool) {
+	p := newPagination(r.req.URL.Query())
+	switch p.sortBy {
+	case "", "balance", "created_at", "links_created", "paid_clicks", "pay_per_click_rate":
+	default:
+		s.writeError(w, r.req, http.StatusBadRequest, fmt.Sprintf("sort by %s is not valid", p.sortBy), "sort_by")
+		return
+	}
+
+	var campaigns []*tonicpow.Campaign
+	for _, id := range sortedKeys(s.campaigns) {
+		if filter(s.campaigns[id]) {
+			campaigns = append(campaigns, s.campaignView(s.campaigns[id]))
+		}
+	}
+	campaigns = paginate(p, campaigns, func(a, b *tonicpow.Campaign) bool {
+		switch p.sortBy {
+		case "balance":
+			return a.Balance.Cmp(b.Balance) < 0
+		case "links_created":
+			return a.LinksCreated < b.LinksCreated
+		case "paid_clicks":
+			return a.PaidClicks < b.PaidClicks
+		case "pay_per_click_rate":
+			return a.PayPerClickRate.Cmp(b.PayPerClickRate) < 0
+		}
+		return a.CreatedAt < b.CreatedAt || (a.CreatedAt == b.CreatedAt && a.ID < b.ID)
+	})
+	if len(campaigns) == 0 {
+		s.writeNotFound(w, r.req, "campaigns")
+		return
+	}
+	s.writeJSON(w, http.StatusOK, &tonicpow.CampaignResults{
+		Campaigns:      campaigns,
+		CurrentPage:    p.currentPage,
+		Results:        len(campaigns),
+		ResultsPerPage: p.resultsPerPage,
+	})
+}
+
+// initCampaign will set the generated fields of a new campaign
+func (s *Server) initCampaign(campaign *tonicpow.Campaign) {
+	if len(campaign.CreatedAt) == 0 {
+		campaign.CreatedAt = s.timestamp()
+	}
+	if len(campaign.Currency) == 0 {
+		campaign.Currency = tonicpow.CurrencyUSD
+	}
+	if len(campaign.FundingAddress) == 0 {
+		campaign.FundingAddress = "1" + newID()
+	}
+	if len(campaign.PublicGUID) == 0 {
+		campaign.PublicGUID = newID()
+	}
+	if len(campaign.Slug) == 0 {
+		base := strings.Trim(slugInvalidCharacters.ReplaceAllString(strings.ToLower(campaign.Title), "-"), "-")
+		if len(base) == 0 {
+			base = "campaign"
+		}
+		campaign.Slug = base
+		for i := 2; s.campaignBySlug(campaign.Slug) != nil; i++ {
+			campaign.Slug = fmt.Sprintf("%s-%d", base, i)
+		}
+	}
+}
+
+// campaignView will return a copy of the campaign including its goals and advertiser profile
+func (s *Server) campaignView(campaign *tonicpow.Campaign) *tonicpow.Campaign {
+	view := clone(campaign)
+	view.Goals = []*tonicpow.Goal{}
+	for _, id := range sortedKeys(s.goals) {
+		if s.goals[id].CampaignID == campaign.ID {
+			view.Goals = append(view.Goals, clone(s.goals[id]))
+		}
+	}
+	view.AdvertiserProfile = clone(s.advertisers[campaign.AdvertiserProfileID])
+	return view
+}
+
+// campaignBySlug will return the stored campaign with the slug (or nil)
+func (s *Server) campaignBySlug(slug string) *tonicpow.Campaign {
+	for _, campaign := range s.campaigns {
+		if strings.EqualFold(campaign.Slug, slug) {
+			return campaign
+		}
+	}
+	return nil
+}
+
+// campaignExpired will return true if the campaign has an expiration date in the past
+func (s *Server) campaignExpired(campaign *tonicpow.Campaign) bool {
+	if len(campaign.ExpiresAt) == 0 {
+		return false
+	}
+	for _, layout := range []string{timeFormat, time.RFC3339} {
+		if expiresAt, err := time.Parse(layout, campaign.ExpiresAt); err == nil {
+			return expiresAt.Before(s.now())
+		}
+	}
+	return false
+}
+
+// validateCampaign will return the name of the first missing required field (or empty)
+func validateCampaign(campaign *tonicpow.Campaign) string {
+	if len(campaign.Title) == 0 {
+		return "title"
+	} else if len(campaign.Description) == 0 {
+		return "description"
+	} else if len(campaign.TargetType) == 0 {
+		return "target_type"
+	} else if campaign.TargetType == "url" && len(campaign.TargetURL) == 0 {
+		return "target_url"
+	} else if campaign.TargetType == "hosted" && len(campaign.TargetData) == 0 {
+		return "target_data"
+	}
+	return ""
+}
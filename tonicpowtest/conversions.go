@@ -0,0 +1,235 @@
+package tonicpowtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tonicpow/go-tonicpow"
+)
+
+// minimumCancelWindow is the time that must remain on a delayed conversion for it to be canceled
+const minimumCancelWindow = time.Minute
+
+// routeConversions will route all the /conversions requests
+func (s *Server) routeConversions(w http.ResponseWriter, r *request) {
+	switch {
+	case r.is(http.MethodPost):
+		s.createConversion(w, r)
+	case r.is(http.MethodPut, "cancel"):
+		s.cancelConversion(w, r)
+	case r.is(http.MethodGet, "details", "*"):
+		s.getConversion(w, r)
+	case r.is(http.MethodGet, "campaigns", "*"), r.is(http.MethodGet, "goals", "*"), r.is(http.MethodGet, "users", "*"):
+		s.listConversions(w, r)
+	default:
+		s.writeError(w, r.req, http.StatusNotFound, "route not found", r.req.URL.Path)
+	}
+}
+
+// createConversion will create a new conversion for a goal (paid immediately unless delayed)
+func (s *Server) createConversion(w http.ResponseWriter, r *request) {
+	payload := make(map[string]string)
+	if err := r.decode(&payload); err != nil {
+		s.writeError(w, r.req, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	// Find the goal
+	var goal *tonicpow.Goal
+	if goalID, _ := strconv.ParseUint(payload["goal_id"], 10, 64); goalID > 0 {
+		goal = s.goals[goalID]
+	} else if name := payload["name"]; len(name) > 0 {
+		goal = s.goalByName(0, name)
+	} else {
+		s.writeMissing(w, r.req, "goal_id")
+		return
+	}
+	if goal == nil {
+		s.writeNotFound(w, r.req, "goal")
+		return
+	}
+
+	// Find the visitor
+	userID, _ := strconv.ParseUint(payload["user_id"], 10, 64)
+	if userID == 0 && len(payload["tncpw_session"]) == 0 &&
+		len(payload["short_code"]) == 0 && len(payload["twitter_id"]) == 0 {
+		s.writeMissing(w, r.req, "tncpw_session")
+		return
+	}
+
+	conversion := &tonicpow.Conversion{
+		Amount:           goal.PayoutRate,
+		CampaignID:       goal.CampaignID,
+		CreatedAt:        s.timestamp(),
+		CustomDimensions: payload["custom_dimensions"],
+		GoalID:           goal.ID,
+		GoalName:         goal.Name,
+		ID:               s.nextID(),
+		Status:           tonicpow.ConversionStatusPending,
+		UserID:           userID,
+	}
+	if purchaseAmount, _ := tonicpow.ParseDecimal(payload["amount"]); purchaseAmount.Sign() > 0 && goal.PayoutType == tonicpow.PayoutTypePercent {
+		conversion.Amount = purchaseAmount.Mul(goal.PayoutRate).Mul(tonicpow.NewDecimal(1, 2))
+	}
+
+	// Delayed or paid now
+	if delay, _ := strconv.ParseUint(payload["delay_in_minutes"], 10, 64); delay > 0 {
+		conversion.Status = tonicpow.ConversionStatusDelayed
+		conversion.PayoutAfter = s.now().UTC().Add(time.Duration(delay) * time.Minute).Format(timeFormat)
+	} else {
+		s.payConversion(conversion)
+	}
+
+	s.conversions[conversion.ID] = conversion
+	s.writeJSON(w, http.StatusCreated, conversion)
+}
+
+// getConversion will return a conversion by id
+func (s *Server) getConversion(w http.ResponseWriter, r *request) {
+	id, _ := strconv.ParseUint(r.segment(2), 10, 64)
+	conversion, ok := s.conversions[id]
+	if !ok {
+		s.writeNotFound(w, r.req, "conversion")
+		return
+	}
+	s.processConversion(conversion)
+	s.writeJSON(w, http.StatusOK, conversion)
+}
+
+// listConversions will return a page of conversions for the campaign, goal or user (filtered by the query)
+func (s *Server) listConversions(w http.ResponseWriter, r *request) {
+	id, _ := strconv.ParseUint(r.segment(2), 10, 64)
+	query := r.req.URL.Query()
+	status, payoutState := query.Get("status"), query.Get("payout_state")
+	from, to, ok := parseDateRange(query.Get("from"), query.Get("to"))
+	if !ok {
+		s.writeError(w, r.req, http.StatusBadRequest, "date range is not valid", "from")
+		return
+	}
+
+	p := newPagination(query)
+	switch p.sortBy {
+	case "", "amount", "created_at":
+	default:
+		s.writeError(w, r.req, http.StatusBadRequest, "sort by "+p.sortBy+" is not valid", "sort_by")
+		return
+	}
+
+	var conversions []*tonicpow.Conversion
+	for _, conversionID := range sortedKeys(s.conversions) {
+		conversion := s.conversions[conversionID]
+		s.processConversion(conversion)
+		switch r.segment(1) {
+		case "campaigns":
+			ok = conversion.CampaignID == id
+		case "goals":
+			ok = conversion.GoalID == id
+		default:
+			ok = conversion.UserID == id
+		}
+		createdAt, _ := time.Parse(timeFormat, conversion.CreatedAt)
+		if !ok || (len(status) > 0 && conversion.Status != status) ||
+			(payoutState == "paid" && len(conversion.TxID) == 0) ||
+			(payoutState == "unpaid" && len(conversion.TxID) > 0) ||
+			(!from.IsZero() && createdAt.Before(from)) || (!to.IsZero() && !createdAt.Before(to)) {
+			continue
+		}
+		conversions = append(conversions, clone(conversion))
+	}
+	conversions = paginate(p, conversions, func(a, b *tonicpow.Conversion) bool {
+		if p.sortBy == "amount" {
+			return a.Amount.Cmp(b.Amount) < 0
+		}
+		return a.CreatedAt < b.CreatedAt || (a.CreatedAt == b.CreatedAt && a.ID < b.ID)
+	})
+	if len(conversions) == 0 {
+		s.writeNotFound(w, r.req, "conversions")
+		return
+	}
+	s.writeJSON(w, http.StatusOK, &tonicpow.ConversionResults{
+		Conversions:    conversions,
+		CurrentPage:    p.currentPage,
+		Results:        len(conversions),
+		ResultsPerPage: p.resultsPerPage,
+	})
+}
+
+// parseDateRange will parse the (optional) from and to timestamps of a list request
+func parseDateRange(fromValue, toValue string) (from, to time.Time, ok bool) {
+	var err error
+	if len(fromValue) > 0 {
+		if from, err = time.Parse(timeFormat, fromValue); err != nil {
+			return
+		}
+	}
+	if len(toValue) > 0 {
+		if to, err = time.Parse(timeFormat, toValue); err != nil {
+			return
+		}
+	}
+	return from, to, true
+}
+
+// cancelConversion will cancel a delayed conversion (if more than a minute remains)
+func (s *Server) cancelConversion(w http.ResponseWriter, r *request) {
+	var payload struct {
+		ID     json.Number `json:"id"`
+		Reason string      `json:"reason"`
+	}
+	if err := r.decode(&payload); err != nil {
+		s.writeError(w, r.req, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	id, _ := strconv.ParseUint(payload.ID.String(), 10, 64)
+	if id == 0 {
+		s.writeMissing(w, r.req, "id")
+		return
+	}
+	conversion, ok := s.conversions[id]
+	if !ok {
+		s.writeNotFound(w, r.req, "conversion")
+		return
+	}
+
+	s.processConversion(conversion)
+	payoutAfter, err := time.Parse(timeFormat, conversion.PayoutAfter)
+	if conversion.Status != tonicpow.ConversionStatusDelayed || err != nil ||
+		payoutAfter.Sub(s.now()) <= minimumCancelWindow {
+		s.writeError(w, r.req, http.StatusBadRequest, "conversion can no longer be canceled", "status")
+		return
+	}
+
+	conversion.Status = tonicpow.ConversionStatusCanceled
+	conversion.StatusData = payload.Reason
+	s.writeJSON(w, http.StatusOK, conversion)
+}
+
+// processConversion will pay out a delayed conversion once the delay has passed
+func (s *Server) processConversion(conversion *tonicpow.Conversion) {
+	if conversion.Status != tonicpow.ConversionStatusDelayed {
+		return
+	}
+	if payoutAfter, err := time.Parse(timeFormat, conversion.PayoutAfter); err == nil && !s.now().Before(payoutAfter) {
+		s.payConversion(conversion)
+	}
+}
+
+// payConversion will pay out the conversion from the campaign balance
+func (s *Server) payConversion(conversion *tonicpow.Conversion) {
+	campaign, ok := s.campaigns[conversion.CampaignID]
+	if !ok || campaign.Balance.Cmp(conversion.Amount) < 0 {
+		conversion.Status = tonicpow.ConversionStatusFailed
+		conversion.StatusData = "campaign balance is too low"
+		return
+	}
+	campaign.Balance = campaign.Balance.Sub(conversion.Amount)
+	campaign.PaidConversions++
+	if goal, found := s.goals[conversion.GoalID]; found {
+		goal.Payouts++
+		goal.LastConvertedAt = s.timestamp()
+	}
+	conversion.Status = tonicpow.ConversionStatusPaid
+	conversion.TxID = newID() + newID()
+}
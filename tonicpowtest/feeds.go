@@ -0,0 +1,193 @@
+package tonicpowtest
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"time"
+
+	"github.com/tonicpow/go-tonicpow"
+)
+
+const (
+	// Feed metadata
+	feedDescription = "List of active campaigns, ordered by newest first"
+	feedLink        = "https://tonicpow.com"
+	feedTitle       = "TonicPow"
+)
+
+// rssFeed is the RSS 2.0 document
+type rssFeed struct {
+	XMLName xml.Name `xml:"rss"`
+	Version string   `xml:"version,attr"`
+	Channel struct {
+		Description string     `xml:"description"`
+		Items       []*rssItem `xml:"item"`
+		Link        string     `xml:"link"`
+		PubDate     string     `xml:"pubDate"`
+		Title       string     `xml:"title"`
+	} `xml:"channel"`
+}
+
+// rssItem is a single RSS item
+type rssItem struct {
+	Author      string        `xml:"author,omitempty"`
+	Description string        `xml:"description"`
+	Enclosure   *rssEnclosure `xml:"enclosure,omitempty"`
+	GUID        string        `xml:"guid,omitempty"`
+	Link        string        `xml:"link"`
+	PubDate     string        `xml:"pubDate,omitempty"`
+	Title       string        `xml:"title"`
+}
+
+// rssEnclosure is the image of an RSS item
+type rssEnclosure struct {
+	Length string `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+	URL    string `xml:"url,attr"`
+}
+
+// atomFeed is the Atom document
+type atomFeed struct {
+	XMLName  xml.Name     `xml:"http://www.w3.org/2005/Atom feed"`
+	Entries  []*atomEntry `xml:"entry"`
+	ID       string       `xml:"id"`
+	Link     atomLink     `xml:"link"`
+	Subtitle string       `xml:"subtitle"`
+	Title    string       `xml:"title"`
+	Updated  string       `xml:"updated"`
+}
+
+// atomEntry is a single Atom entry
+type atomEntry struct {
+	ID      string     `xml:"id"`
+	Links   []atomLink `xml:"link"`
+	Summary string     `xml:"summary"`
+	Title   string     `xml:"title"`
+	Updated string     `xml:"updated"`
+}
+
+// atomLink is a link of an Atom feed or entry
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+// jsonFeed is the JSON Feed (version 1) document
+type jsonFeed struct {
+	Description string          `json:"description"`
+	HomePageURL string          `json:"home_page_url"`
+	Items       []*jsonFeedItem `json:"items"`
+	Title       string          `json:"title"`
+	Version     string          `json:"version"`
+}
+
+// jsonFeedItem is a single JSON Feed item
+type jsonFeedItem struct {
+	DatePublished string `json:"date_published,omitempty"`
+	ID            string `json:"id"`
+	Image         string `json:"image,omitempty"`
+	Summary       string `json:"summary"`
+	Title         string `json:"title"`
+	URL           string `json:"url"`
+}
+
+// campaignsFeed will return the listed campaigns as an RSS, Atom or JSON feed
+func (s *Server) campaignsFeed(w http.ResponseWriter, r *request) {
+	var campaigns []*tonicpow.Campaign
+	for _, id := range sortedKeys(s.campaigns) {
+		if campaign := s.campaigns[id]; !campaign.Unlisted && !s.campaignExpired(campaign) {
+			campaigns = append([]*tonicpow.Campaign{campaign}, campaigns...) // newest first
+		}
+	}
+	if len(campaigns) == 0 {
+		s.writeNotFound(w, r.req, "campaigns")
+		return
+	}
+
+	switch tonicpow.GetFeedType(r.req.URL.Query().Get("feed_type")) {
+	case tonicpow.FeedTypeAtom:
+		feed := &atomFeed{
+			ID:       feedLink,
+			Link:     atomLink{Href: feedLink},
+			Subtitle: feedDescription,
+			Title:    feedTitle,
+			Updated:  s.now().UTC().Format(time.RFC3339),
+		}
+		for _, campaign := range campaigns {
+			entry := &atomEntry{
+				ID:      campaignLink(campaign),
+				Links:   []atomLink{{Href: campaignLink(campaign), Rel: "alternate"}},
+				Summary: campaign.Description,
+				Title:   campaign.Title,
+				Updated: feedDate(campaign.CreatedAt, time.RFC3339),
+			}
+			if len(campaign.ImageURL) > 0 {
+				entry.Links = append(entry.Links, atomLink{Href: campaign.ImageURL, Rel: "enclosure", Type: "image/jpeg"})
+			}
+			feed.Entries = append(feed.Entries, entry)
+		}
+		s.writeXML(w, feed)
+	case tonicpow.FeedTypeJSON:
+		feed := &jsonFeed{
+			Description: feedDescription,
+			HomePageURL: feedLink,
+			Title:       feedTitle,
+			Version:     "https://jsonfeed.org/version/1",
+		}
+		for _, campaign := range campaigns {
+			feed.Items = append(feed.Items, &jsonFeedItem{
+				DatePublished: feedDate(campaign.CreatedAt, time.RFC3339),
+				ID:            campaign.PublicGUID,
+				Image:         campaign.ImageURL,
+				Summary:       campaign.Description,
+				Title:         campaign.Title,
+				URL:           campaignLink(campaign),
+			})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(feed)
+	default:
+		feed := &rssFeed{Version: "2.0"}
+		feed.Channel.Description = feedDescription
+		feed.Channel.Link = feedLink
+		feed.Channel.PubDate = s.now().UTC().Format(time.RFC1123Z)
+		feed.Channel.Title = feedTitle
+		for _, campaign := range campaigns {
+			item := &rssItem{
+				Description: campaign.Description,
+				GUID:        campaign.PublicGUID,
+				Link:        campaignLink(campaign),
+				PubDate:     feedDate(campaign.CreatedAt, time.RFC1123Z),
+				Title:       campaign.Title,
+			}
+			if len(campaign.ImageURL) > 0 {
+				item.Enclosure = &rssEnclosure{Length: "0", Type: "image/jpeg", URL: campaign.ImageURL}
+			}
+			feed.Channel.Items = append(feed.Channel.Items, item)
+		}
+		s.writeXML(w, feed)
+	}
+}
+
+// writeXML will write the feed as an XML response
+func (s *Server) writeXML(w http.ResponseWriter, feed interface{}) {
+	w.Header().Set("Content-Type", "application/xml")
+	_, _ = w.Write([]byte(xml.Header))
+	_ = xml.NewEncoder(w).Encode(feed)
+}
+
+// campaignLink will return the public link of the campaign
+func campaignLink(campaign *tonicpow.Campaign) string {
+	return feedLink + "/campaign/" + campaign.Slug
+}
+
+// feedDate will convert an API timestamp into the feed date layout
+func feedDate(timestamp, layout string) string {
+	t, err := time.Parse(timeFormat, timestamp)
+	if err != nil {
+		return ""
+	}
+	return t.Format(layout)
+}
@@ -0,0 +1,170 @@
+package tonicpowtest
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/tonicpow/go-tonicpow"
+)
+
+// routeGoals will route all the /goals requests
+func (s *Server) routeGoals(w http.ResponseWriter, r *request) {
+	switch {
+	case r.is(http.MethodPost):
+		s.createGoal(w, r)
+	case r.is(http.MethodPut):
+		s.updateGoal(w, r)
+	case r.is(http.MethodDelete):
+		s.deleteGoal(w, r)
+	case r.is(http.MethodGet, "details", "*"):
+		s.getGoal(w, r)
+	default:
+		s.writeError(w, r.req, http.StatusNotFound, "route not found", r.req.URL.Path)
+	}
+}
+
+// createGoal will create a new goal for an existing campaign
+func (s *Server) createGoal(w http.ResponseWriter, r *request) {
+	goal := new(tonicpow.Goal)
+	if err := r.decode(goal); err != nil {
+		s.writeError(w, r.req, http.StatusBadRequest, err.Error(), nil)
+		return
+	} else if goal.CampaignID == 0 {
+		s.writeMissing(w, r.req, "campaign_id")
+		return
+	} else if len(goal.Name) == 0 {
+		s.writeMissing(w, r.req, "name")
+		return
+	} else if _, ok := s.campaigns[goal.CampaignID]; !ok {
+		s.writeNotFound(w, r.req, "campaign")
+		return
+	} else if s.goalByName(goal.CampaignID, goal.Name) != nil {
+		s.writeError(w, r.req, http.StatusBadRequest, "goal name is already in use", "name")
+		return
+	}
+
+	goal.ID = s.nextID()
+	goal.Payouts = 0
+	goal.LastConvertedAt = ""
+	s.goals[goal.ID] = goal
+	s.writeJSON(w, http.StatusCreated, goal)
+}
+
+// updateGoal will update an existing goal (only the fields sent are changed)
+func (s *Server) updateGoal(w http.ResponseWriter, r *request) {
+	var id struct {
+		ID uint64 `json:"id"`
+	}
+	if err := r.decode(&id); err != nil {
+		s.writeError(w, r.req, http.StatusBadRequest, err.Error(), nil)
+		return
+	} else if id.ID == 0 {
+		s.writeMissing(w, r.req, "id")
+		return
+	}
+	existing, ok := s.goals[id.ID]
+	if !ok {
+		s.writeNotFound(w, r.req, "goal")
+		return
+	}
+
+	// Apply the changes on a copy
+	goal := clone(existing)
+	if err := r.decode(goal); err != nil {
+		s.writeError(w, r.req, http.StatusBadRequest, err.Error(), nil)
+		return
+	} else if len(goal.Name) == 0 {
+		s.writeMissing(w, r.req, "name")
+		return
+	} else if other := s.goalByName(existing.CampaignID, goal.Name); other != nil && other.ID != goal.ID {
+		s.writeError(w, r.req, http.StatusBadRequest, "goal name is already in use", "name")
+		return
+	}
+
+	// Read-only fields
+	goal.CampaignID = existing.CampaignID
+	goal.LastConvertedAt = existing.LastConvertedAt
+	goal.Payouts = existing.Payouts
+
+	s.goals[goal.ID] = goal
+	s.writeJSON(w, http.StatusOK, goal)
+}
+
+// deleteGoal will delete an existing goal
+func (s *Server) deleteGoal(w http.ResponseWriter, r *request) {
+	id, _ := strconv.ParseUint(r.req.URL.Query().Get("id"), 10, 64)
+	if id == 0 {
+		s.writeMissing(w, r.req, "id")
+		return
+	} else if _, ok := s.goals[id]; !ok {
+		s.writeNotFound(w, r.req, "goal")
+		return
+	}
+	delete(s.goals, id)
+	w.WriteHeader(http.StatusOK)
+}
+
+// getGoal will return a goal by id
+func (s *Server) getGoal(w http.ResponseWriter, r *request) {
+	id, _ := strconv.ParseUint(r.segment(2), 10, 64)
+	goal, ok := s.goals[id]
+	if !ok {
+		s.writeNotFound(w, r.req, "goal")
+		return
+	}
+	s.writeJSON(w, http.StatusOK, goal)
+}
+
+// listGoalsByCampaign will return a page of goals for the campaign
+func (s *Server) listGoalsByCampaign(w http.ResponseWriter, r *request) {
+	id, _ := strconv.ParseUint(r.segment(2), 10, 64)
+	if _, ok := s.campaigns[id]; !ok {
+		s.writeNotFound(w, r.req, "campaign")
+		return
+	}
+
+	p := newPagination(r.req.URL.Query())
+	switch p.sortBy {
+	case "", "created_at", "name", "payouts":
+	default:
+		s.writeError(w, r.req, http.StatusBadRequest, "sort by "+p.sortBy+" is not valid", "sort_by")
+		return
+	}
+
+	var goals []*tonicpow.Goal
+	for _, goalID := range sortedKeys(s.goals) {
+		if s.goals[goalID].CampaignID == id {
+			goals = append(goals, clone(s.goals[goalID]))
+		}
+	}
+	goals = paginate(p, goals, func(a, b *tonicpow.Goal) bool {
+		switch p.sortBy {
+		case "name":
+			return a.Name < b.Name
+		case "payouts":
+			return a.Payouts < b.Payouts
+		}
+		return a.ID < b.ID
+	})
+	if len(goals) == 0 {
+		s.writeNotFound(w, r.req, "goals")
+		return
+	}
+	s.writeJSON(w, http.StatusOK, &tonicpow.GoalResults{
+		CurrentPage:    p.currentPage,
+		Goals:          goals,
+		Results:        len(goals),
+		ResultsPerPage: p.resultsPerPage,
+	})
+}
+
+// goalByName will return the goal with the name (any campaign if campaignID is 0)
+func (s *Server) goalByName(campaignID uint64, name string) *tonicpow.Goal {
+	for _, id := range sortedKeys(s.goals) {
+		goal := s.goals[id]
+		if goal.Name == name && (campaignID == 0 || goal.CampaignID == campaignID) {
+			return goal
+		}
+	}
+	return nil
+}
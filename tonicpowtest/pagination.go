@@ -0,0 +1,77 @@
+package tonicpowtest
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Pagination defaults
+	defaultResultsPerPage = 20
+	maxResultsPerPage     = 100
+
+	// Query fields used for listing
+	queryCurrentPage    = "current_page"
+	queryResultsPerPage = "results_per_page"
+	querySortBy         = "sort_by"
+	querySortOrder      = "sort_order"
+)
+
+// pagination is the page requested in the query string
+type pagination struct {
+	currentPage    int
+	resultsPerPage int
+	sortBy         string
+	sortDesc       bool
+}
+
+// newPagination will parse the pagination fields from the query string
+func newPagination(query url.Values) *pagination {
+	p := &pagination{
+		currentPage:    1,
+		resultsPerPage: defaultResultsPerPage,
+		sortBy:         strings.ToLower(query.Get(querySortBy)),
+		sortDesc:       strings.EqualFold(query.Get(querySortOrder), "desc"),
+	}
+	if page, err := strconv.Atoi(query.Get(queryCurrentPage)); err == nil && page > 0 {
+		p.currentPage = page
+	}
+	if perPage, err := strconv.Atoi(query.Get(queryResultsPerPage)); err == nil && perPage > 0 {
+		p.resultsPerPage = perPage
+	}
+	if p.resultsPerPage > maxResultsPerPage {
+		p.resultsPerPage = maxResultsPerPage
+	}
+	return p
+}
+
+// paginate will sort the items using the less func and return the requested page
+func paginate[T any](p *pagination, items []T, less func(a, b T) bool) []T {
+	sort.SliceStable(items, func(i, j int) bool {
+		if p.sortDesc {
+			return less(items[j], items[i])
+		}
+		return less(items[i], items[j])
+	})
+	start := (p.currentPage - 1) * p.resultsPerPage
+	if start >= len(items) {
+		return nil
+	}
+	end := start + p.resultsPerPage
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[start:end]
+}
+
+// sortedKeys will return the keys of the map in ascending order
+func sortedKeys[T any](m map[uint64]T) []uint64 {
+	keys := make([]uint64, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
@@ -0,0 +1,39 @@
+package tonicpowtest
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/tonicpow/go-tonicpow"
+)
+
+// routeRates will route all the /rates requests
+func (s *Server) routeRates(w http.ResponseWriter, r *request) {
+	switch {
+	case r.is(http.MethodGet, "*"):
+		s.getCurrentRate(w, r)
+	default:
+		s.writeError(w, r.req, http.StatusNotFound, "route not found", r.req.URL.Path)
+	}
+}
+
+// getCurrentRate will return the price in satoshis of the amount in the currency (default amount is 1)
+func (s *Server) getCurrentRate(w http.ResponseWriter, r *request) {
+	currency := tonicpow.Currency(strings.ToLower(r.segment(1)))
+	satoshisPerUnit, ok := s.rates[currency]
+	if !ok {
+		s.writeError(w, r.req, http.StatusBadRequest, "currency is not supported", "currency")
+		return
+	}
+	amount, _ := tonicpow.ParseDecimal(r.req.URL.Query().Get("amount"))
+	if amount.Sign() <= 0 {
+		amount = tonicpow.NewDecimal(1, 0)
+	}
+	unit := &tonicpow.Rate{CurrencyAmount: tonicpow.NewDecimal(1, 0), PriceInSatoshis: int64(satoshisPerUnit)}
+	price, _ := unit.Satoshis(amount)
+	s.writeJSON(w, http.StatusOK, &tonicpow.Rate{
+		Currency:        currency,
+		CurrencyAmount:  amount,
+		PriceInSatoshis: int64(price),
+	})
+}
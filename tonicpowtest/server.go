@@ -0,0 +1,511 @@
+package tonicpowtest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tonicpow/go-tonicpow"
+)
+
+const (
+	// DefaultAPIKey is the api key accepted by the server (unless changed with WithAPIKey)
+	DefaultAPIKey = "TestAPIKey12345678987654321"
+
+	// IdempotentReplayedHeader is set on a response that was replayed for a known idempotency key
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	// Header and field names used by the API
+	headerAPIKey = "api_key"
+
+	// Date format used by the API for timestamps
+	timeFormat = "2006-01-02 15:04:05"
+)
+
+// ServerOps allow functional options to be supplied
+// that overwrite default server options.
+type ServerOps func(s *Server)
+
+// WithAPIKey will overwrite the api key accepted by the server
+func WithAPIKey(apiKey string) ServerOps {
+	return func(s *Server) {
+		s.apiKey = apiKey
+	}
+}
+
+// WithClock will overwrite the clock used for timestamps and conversion delays
+func WithClock(now func() time.Time) ServerOps {
+	return func(s *Server) {
+		s.now = now
+	}
+}
+
+// WithWebhookSecret will overwrite the secret used to sign the test webhook deliveries
+func WithWebhookSecret(secret string) ServerOps {
+	return func(s *Server) {
+		s.webhookSecret = secret
+	}
+}
+
+// RecordedRequest is a request received by the server
+type RecordedRequest struct {
+	Body   []byte
+	Header http.Header
+	Method string
+	Path   string
+	Query  url.Values
+}
+
+// failure is an injected error response
+type failure struct {
+	header     http.Header
+	remaining  int
+	statusCode int
+}
+
+// idempotentResponse is a stored response for an idempotency key
+type idempotentResponse struct {
+	body        []byte
+	header      http.Header
+	requestBody []byte
+	statusCode  int
+}
+
+// Server is a stateful in-memory fake of the TonicPow API
+//
+// Point a client at the server using WithCustomEnvironment (see NewClient)
+type Server struct {
+	advertisers   map[uint64]*tonicpow.AdvertiserProfile
+	apiKey        string
+	apps          map[uint64]*tonicpow.App
+	campaigns     map[uint64]*tonicpow.Campaign
+	conversions   map[uint64]*tonicpow.Conversion
+	failures      []*failure
+	goals         map[uint64]*tonicpow.Goal
+	idempotent    map[string]*idempotentResponse
+	lastID        uint64
+	mu            sync.Mutex
+	now           func() time.Time
+	rates         map[tonicpow.Currency]tonicpow.Satoshis
+	requests      []*RecordedRequest
+	server        *httptest.Server
+	webhookClient *http.Client
+	webhookSecret string
+}
+
+// NewServer will create and start a new fake API server
+//
+// The server must be closed when finished (defer server.Close())
+func NewServer(opts ...ServerOps) *Server {
+	s := &Server{
+		advertisers:   make(map[uint64]*tonicpow.AdvertiserProfile),
+		apiKey:        DefaultAPIKey,
+		apps:          make(map[uint64]*tonicpow.App),
+		campaigns:     make(map[uint64]*tonicpow.Campaign),
+		conversions:   make(map[uint64]*tonicpow.Conversion),
+		goals:         make(map[uint64]*tonicpow.Goal),
+		idempotent:    make(map[string]*idempotentResponse),
+		now:           time.Now,
+		rates:         map[tonicpow.Currency]tonicpow.Satoshis{tonicpow.CurrencyUSD: 2000000}, // satoshis per 1 unit of currency
+		webhookClient: &http.Client{Timeout: webhookTimeout},
+		webhookSecret: DefaultWebhookSecret,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Close will shut down the server
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// URL will return the base url of the server (used as the api url of the Environment)
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// ClientOptions will return the options needed for a client to use the server
+func (s *Server) ClientOptions() []tonicpow.ClientOps {
+	return []tonicpow.ClientOps{
+		tonicpow.WithAPIKey(s.apiKey),
+		tonicpow.WithCustomEnvironment("tonicpowtest", "test", s.URL()),
+	}
+}
+
+// NewClient will create a new client that uses the server (additional options are applied last)
+func (s *Server) NewClient(opts ...tonicpow.ClientOps) (tonicpow.ClientInterface, error) {
+	return tonicpow.NewClient(append(s.ClientOptions(), opts...)...)
+}
+
+// Requests will return all the requests received by the server
+func (s *Server) Requests() []*RecordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*RecordedRequest{}, s.requests...)
+}
+
+// FailNext will respond to the next count requests with the status code (and optional headers)
+// instead of processing them, useful for testing retries and error handling
+func (s *Server) FailNext(count, statusCode int, header http.Header) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, &failure{header: header, remaining: count, statusCode: statusCode})
+}
+
+// AddAdvertiserProfile will store the profile (an ID is assigned if not set)
+func (s *Server) AddAdvertiserProfile(profile *tonicpow.AdvertiserProfile) *tonicpow.AdvertiserProfile {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	profile = clone(profile)
+	if profile.ID == 0 {
+		profile.ID = s.nextID()
+	}
+	if len(profile.PublicGUID) == 0 {
+		profile.PublicGUID = newID()
+	}
+	s.advertisers[profile.ID] = profile
+	return clone(profile)
+}
+
+// AddApp will store the app (an ID is assigned if not set)
+func (s *Server) AddApp(app *tonicpow.App) *tonicpow.App {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	app = clone(app)
+	if app.ID == 0 {
+		app.ID = s.nextID()
+	}
+	s.apps[app.ID] = app
+	return clone(app)
+}
+
+// AddCampaign will store the campaign (an ID is assigned if not set), goals on the campaign are also stored
+func (s *Server) AddCampaign(campaign *tonicpow.Campaign) *tonicpow.Campaign {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	campaign = clone(campaign)
+	if campaign.ID == 0 {
+		campaign.ID = s.nextID()
+	}
+	s.initCampaign(campaign)
+	for _, goal := range campaign.Goals {
+		goal.CampaignID = campaign.ID
+		if goal.ID == 0 {
+			goal.ID = s.nextID()
+		}
+		s.goals[goal.ID] = goal
+	}
+	campaign.Goals = nil
+	s.campaigns[campaign.ID] = campaign
+	return s.campaignView(campaign)
+}
+
+// AddGoal will store the goal (an ID is assigned if not set)
+func (s *Server) AddGoal(goal *tonicpow.Goal) *tonicpow.Goal {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	goal = clone(goal)
+	if goal.ID == 0 {
+		goal.ID = s.nextID()
+	}
+	s.goals[goal.ID] = goal
+	return clone(goal)
+}
+
+// SetRate will set the price of 1 unit of the currency in satoshis
+func (s *Server) SetRate(currency tonicpow.Currency, satoshisPerUnit tonicpow.Satoshis) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rates[tonicpow.Currency(strings.ToLower(string(currency)))] = satoshisPerUnit
+}
+
+// AdvertiserProfile will return a copy of the stored profile (or nil)
+func (s *Server) AdvertiserProfile(profileID uint64) *tonicpow.AdvertiserProfile {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return clone(s.advertisers[profileID])
+}
+
+// App will return a copy of the stored app (or nil)
+func (s *Server) App(appID uint64) *tonicpow.App {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return clone(s.apps[appID])
+}
+
+// Campaign will return a copy of the stored campaign including its goals (or nil)
+func (s *Server) Campaign(campaignID uint64) *tonicpow.Campaign {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if campaign, ok := s.campaigns[campaignID]; ok {
+		return s.campaignView(campaign)
+	}
+	return nil
+}
+
+// Conversion will return a copy of the stored conversion (or nil)
+func (s *Server) Conversion(conversionID uint64) *tonicpow.Conversion {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if conversion, ok := s.conversions[conversionID]; ok {
+		s.processConversion(conversion)
+		return clone(conversion)
+	}
+	return nil
+}
+
+// Conversions will return a copy of all the stored conversions
+func (s *Server) Conversions() []*tonicpow.Conversion {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	conversions := make([]*tonicpow.Conversion, 0, len(s.conversions))
+	for _, id := range sortedKeys(s.conversions) {
+		s.processConversion(s.conversions[id])
+		conversions = append(conversions, clone(s.conversions[id]))
+	}
+	return conversions
+}
+
+// Goal will return a copy of the stored goal (or nil)
+func (s *Server) Goal(goalID uint64) *tonicpow.Goal {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return clone(s.goals[goalID])
+}
+
+// serveHTTP will authenticate, record and route the request
+func (s *Server) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, &RecordedRequest{
+		Body:   body,
+		Header: req.Header.Clone(),
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  req.URL.Query(),
+	})
+
+	// Injected failures
+	if len(s.failures) > 0 {
+		f := s.failures[0]
+		if f.remaining--; f.remaining <= 0 {
+			s.failures = s.failures[1:]
+		}
+		for key, values := range f.header {
+			for _, value := range values {
+				w.Header().Add(key, value)
+			}
+		}
+		s.writeError(w, req, f.statusCode, http.StatusText(f.statusCode), nil)
+		return
+	}
+
+	// Authentication
+	if req.Header.Get(headerAPIKey) != s.apiKey {
+		s.writeError(w, req, http.StatusUnauthorized, "api key is invalid", headerAPIKey)
+		return
+	}
+
+	r := &request{body: body, req: req, segments: strings.Split(strings.Trim(req.URL.Path, "/"), "/")}
+
+	// Idempotent requests (POST with an idempotency key)
+	if key := req.Header.Get(tonicpow.IdempotencyKeyHeader); len(key) > 0 && req.Method == http.MethodPost {
+		s.serveIdempotent(w, r, req.URL.Path+" "+key)
+		return
+	} else if req.Method == http.MethodGet {
+		s.serveWithETag(w, r)
+		return
+	}
+	s.route(w, r)
+}
+
+// serveWithETag will set the ETag of a successful response and respond with a 304
+// if the ETag matches the If-None-Match header of the request
+func (s *Server) serveWithETag(w http.ResponseWriter, r *request) {
+	recorder := httptest.NewRecorder()
+	s.route(recorder, r)
+	for key, values := range recorder.Header() {
+		w.Header()[key] = values
+	}
+	if recorder.Code == http.StatusOK {
+		sum := sha256.Sum256(recorder.Body.Bytes())
+		etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+		w.Header().Set("ETag", etag)
+		if r.req.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+	w.WriteHeader(recorder.Code)
+	_, _ = w.Write(recorder.Body.Bytes())
+}
+
+// serveIdempotent will process the request once per key and replay the stored response for a known key
+//
+// Only successful responses are stored, so a failed request can be retried with the same key
+func (s *Server) serveIdempotent(w http.ResponseWriter, r *request, key string) {
+	stored, ok := s.idempotent[key]
+	if !ok {
+		recorder := httptest.NewRecorder()
+		s.route(recorder, r)
+		stored = &idempotentResponse{
+			body:        recorder.Body.Bytes(),
+			header:      recorder.Header(),
+			requestBody: r.body,
+			statusCode:  recorder.Code,
+		}
+		if stored.statusCode >= http.StatusOK && stored.statusCode < http.StatusMultipleChoices {
+			s.idempotent[key] = stored
+		}
+	} else if !bytes.Equal(stored.requestBody, r.body) {
+		s.writeError(
+			w, r.req, http.StatusUnprocessableEntity,
+			"idempotency key was already used for a different request", tonicpow.IdempotencyKeyHeader,
+		)
+		return
+	} else {
+		w.Header().Set(IdempotentReplayedHeader, "true")
+	}
+
+	for key, values := range stored.header {
+		w.Header()[key] = values
+	}
+	w.WriteHeader(stored.statusCode)
+	_, _ = w.Write(stored.body)
+}
+
+// route will handle the request using the first segment of the path
+func (s *Server) route(w http.ResponseWriter, r *request) {
+	req := r.req
+	switch r.segments[0] {
+	case "advertisers":
+		s.routeAdvertisers(w, r)
+	case "apps":
+		s.routeApps(w, r)
+	case "campaigns":
+		s.routeCampaigns(w, r)
+	case "conversions":
+		s.routeConversions(w, r)
+	case "goals":
+		s.routeGoals(w, r)
+	case "rates":
+		s.routeRates(w, r)
+	default:
+		s.writeError(w, req, http.StatusNotFound, "route not found", req.URL.Path)
+	}
+}
+
+// request is an incoming request with the path split into segments
+type request struct {
+	body     []byte
+	req      *http.Request
+	segments []string
+}
+
+// is will return true if the method and path segments (after the model) match
+func (r *request) is(method string, segments ...string) bool {
+	if r.req.Method != method || len(r.segments)-1 != len(segments) {
+		return false
+	}
+	for i, segment := range segments {
+		if segment != "*" && segment != r.segments[i+1] {
+			return false
+		}
+	}
+	return true
+}
+
+// segment will return the path segment at the index (0 is the model)
+func (r *request) segment(index int) string {
+	if index < len(r.segments) {
+		return r.segments[index]
+	}
+	return ""
+}
+
+// decode will decode the JSON body into the model
+func (r *request) decode(model interface{}) error {
+	return json.Unmarshal(r.body, model)
+}
+
+// writeJSON will write the model as a JSON response
+func (s *Server) writeJSON(w http.ResponseWriter, statusCode int, model interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(model)
+}
+
+// writeError will write an API error response
+func (s *Server) writeError(w http.ResponseWriter, req *http.Request, statusCode int,
+	message string, data interface{}) {
+	s.writeJSON(w, statusCode, &tonicpow.Error{
+		Code:        statusCode,
+		Data:        data,
+		IPAddress:   "127.0.0.1",
+		Message:     message,
+		Method:      req.Method,
+		RequestGUID: newID(),
+		StatusCode:  statusCode,
+		URL:         req.URL.String(),
+	})
+}
+
+// writeMissing will write a validation error for a missing attribute
+func (s *Server) writeMissing(w http.ResponseWriter, req *http.Request, field string) {
+	s.writeError(w, req, http.StatusBadRequest, fmt.Sprintf("missing required attribute: %s", field), field)
+}
+
+// writeNotFound will write a not found error for the model
+func (s *Server) writeNotFound(w http.ResponseWriter, req *http.Request, model string) {
+	s.writeError(w, req, http.StatusNotFound, fmt.Sprintf("%s not found", model), model)
+}
+
+// nextID will return the next unique ID
+func (s *Server) nextID() uint64 {
+	s.lastID++
+	for s.idExists(s.lastID) {
+		s.lastID++
+	}
+	return s.lastID
+}
+
+// idExists will return true if the ID is used by any model
+func (s *Server) idExists(id uint64) bool {
+	_, a := s.advertisers[id]
+	_, b := s.apps[id]
+	_, c := s.campaigns[id]
+	_, d := s.conversions[id]
+	_, e := s.goals[id]
+	return a || b || c || d || e
+}
+
+// timestamp will return the current time in the API format
+func (s *Server) timestamp() string {
+	return s.now().UTC().Format(timeFormat)
+}
+
+// clone will return a deep copy of the model
+func clone[T any](model *T) *T {
+	if model == nil {
+		return nil
+	}
+	data, _ := json.Marshal(model)
+	c := new(T)
+	_ = json.Unmarshal(data, c)
+	return c
+}
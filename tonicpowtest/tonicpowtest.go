@@ -0,0 +1,7 @@
+// Package tonicpowtest provides utilities for testing applications that use the TonicPow client
+//
+// If you have any suggestions or comments, please feel free to open an issue on
+// this GitHub repository!
+//
+// By TonicPow Inc (https://tonicpow.com)
+package tonicpowtest
@@ -0,0 +1,129 @@
+package tonicpowtest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/tonicpow/go-tonicpow"
+)
+
+const (
+	// DefaultWebhookURL is the url used for generated webhook deliveries
+	DefaultWebhookURL = "https://example.com/tonicpow/webhook"
+
+	// DefaultWebhookSecret is the secret used to sign the test deliveries of the server (unless changed with WithWebhookSecret)
+	DefaultWebhookSecret = "TestWebhookSecret12345678987654321"
+
+	// webhookTimeout is the maximum time to wait for the webhook url to respond to a test delivery
+	webhookTimeout = 10 * time.Second
+)
+
+// NewWebhookEvent will create a webhook event of the given type with the data encoded as JSON
+func NewWebhookEvent(eventType tonicpow.WebhookEventType, data interface{}) (*tonicpow.WebhookEvent, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return &tonicpow.WebhookEvent{
+		CreatedAt: time.Now().UTC(),
+		Data:      raw,
+		ID:        newID(),
+		Type:      eventType,
+	}, nil
+}
+
+// NewWebhookDelivery will create a signed webhook delivery (POST request) for the event
+//
+// The request can be passed directly to WebhookHandler.ServeHTTP() using an httptest.ResponseRecorder
+func NewWebhookDelivery(secret string, event *tonicpow.WebhookEvent) (*http.Request, error) {
+	return NewWebhookDeliveryAt(secret, event, time.Now())
+}
+
+// NewWebhookDeliveryAt will create a webhook delivery signed at the given time (useful for testing replays)
+func NewWebhookDeliveryAt(secret string, event *tonicpow.WebhookEvent, signedAt time.Time) (*http.Request, error) {
+	return newWebhookRequest(DefaultWebhookURL, secret, event, signedAt)
+}
+
+// newWebhookRequest will create a webhook delivery to the url signed at the given time
+func newWebhookRequest(webhookURL, secret string, event *tonicpow.WebhookEvent,
+	signedAt time.Time) (*http.Request, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	var req *http.Request
+	if req, err = http.NewRequest(http.MethodPost, webhookURL, bytes.NewReader(body)); err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(tonicpow.WebhookSignatureHeader, tonicpow.SignWebhook(secret, signedAt, body))
+	return req, nil
+}
+
+// NewSampleWebhookDelivery will create a signed webhook delivery for the event type using sample data
+func NewSampleWebhookDelivery(secret string, eventType tonicpow.WebhookEventType) (*http.Request, error) {
+	event, err := newSampleWebhookEvent(eventType)
+	if err != nil {
+		return nil, err
+	}
+	return NewWebhookDelivery(secret, event)
+}
+
+// newSampleWebhookEvent will create a webhook event of the given type using sample data
+func newSampleWebhookEvent(eventType tonicpow.WebhookEventType) (*tonicpow.WebhookEvent, error) {
+	var data interface{}
+	switch eventType {
+	case tonicpow.WebhookEventConversionCreated:
+		data = SampleConversion(tonicpow.ConversionStatusDelayed)
+	case tonicpow.WebhookEventConversionPaid:
+		data = SampleConversion(tonicpow.ConversionStatusPaid)
+	case tonicpow.WebhookEventConversionCanceled:
+		data = SampleConversion(tonicpow.ConversionStatusCanceled)
+	case tonicpow.WebhookEventCampaignLowBalance:
+		data = SampleCampaign()
+	default:
+		return nil, fmt.Errorf("unknown webhook event type: %s", eventType)
+	}
+	return NewWebhookEvent(eventType, data)
+}
+
+// SampleConversion will return a sample conversion with the given status
+func SampleConversion(status string) *tonicpow.Conversion {
+	return &tonicpow.Conversion{
+		Amount:     tonicpow.NewDecimal(1, 2),
+		CampaignID: 23,
+		GoalID:     13,
+		GoalName:   "example_goal",
+		ID:         99,
+		Status:     status,
+		UserID:     43,
+	}
+}
+
+// SampleCampaign will return a sample campaign with a balance below the alert threshold
+func SampleCampaign() *tonicpow.Campaign {
+	return &tonicpow.Campaign{
+		AdvertiserProfileID:   23,
+		Balance:               tonicpow.NewDecimal(5, 1),
+		BalanceAlertThreshold: tonicpow.NewDecimal(1, 0),
+		Currency:              tonicpow.CurrencyUSD,
+		ID:                    23,
+		PayPerClickRate:       tonicpow.NewDecimal(1, 2),
+		Slug:                  "tonicpow",
+		TargetType:            "url",
+		TargetURL:             "https://tonicpow.com",
+		Title:                 "TonicPow",
+	}
+}
+
+// newID will return a random hex identifier
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,97 @@
+package tonicpowtest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tonicpow/go-tonicpow"
+)
+
+const testWebhookSecret = "TestWebhookSecret12345678987654321"
+
+// TestNewSampleWebhookDelivery will test the method NewSampleWebhookDelivery()
+func TestNewSampleWebhookDelivery(t *testing.T) {
+	t.Parallel()
+
+	t.Run("all event types", func(t *testing.T) {
+		h, err := tonicpow.NewWebhookHandler(testWebhookSecret)
+		require.NoError(t, err)
+
+		statuses := make(map[tonicpow.WebhookEventType]string)
+		onConversion := func(ctx context.Context, e *tonicpow.WebhookEvent, c *tonicpow.Conversion) error {
+			statuses[e.Type] = c.Status
+			return nil
+		}
+		h.OnConversionCreated(onConversion)
+		h.OnConversionPaid(onConversion)
+		h.OnConversionCanceled(onConversion)
+		h.OnCampaignLowBalance(func(ctx context.Context, e *tonicpow.WebhookEvent, c *tonicpow.Campaign) error {
+			statuses[e.Type] = c.Slug
+			return nil
+		})
+
+		for _, eventType := range []tonicpow.WebhookEventType{
+			tonicpow.WebhookEventConversionCreated,
+			tonicpow.WebhookEventConversionPaid,
+			tonicpow.WebhookEventConversionCanceled,
+			tonicpow.WebhookEventCampaignLowBalance,
+		} {
+			var req *http.Request
+			req, err = NewSampleWebhookDelivery(testWebhookSecret, eventType)
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusOK, w.Code, string(eventType))
+		}
+
+		assert.Equal(t, tonicpow.ConversionStatusDelayed, statuses[tonicpow.WebhookEventConversionCreated])
+		assert.Equal(t, tonicpow.ConversionStatusPaid, statuses[tonicpow.WebhookEventConversionPaid])
+		assert.Equal(t, tonicpow.ConversionStatusCanceled, statuses[tonicpow.WebhookEventConversionCanceled])
+		assert.Equal(t, "tonicpow", statuses[tonicpow.WebhookEventCampaignLowBalance])
+	})
+
+	t.Run("unknown event type", func(t *testing.T) {
+		req, err := NewSampleWebhookDelivery(testWebhookSecret, "unknown")
+		assert.Error(t, err)
+		assert.Nil(t, req)
+	})
+}
+
+// TestNewWebhookDeliveryAt will test the method NewWebhookDeliveryAt()
+func TestNewWebhookDeliveryAt(t *testing.T) {
+	t.Parallel()
+
+	h, err := tonicpow.NewWebhookHandler(testWebhookSecret)
+	require.NoError(t, err)
+
+	var event *tonicpow.WebhookEvent
+	event, err = NewWebhookEvent(tonicpow.WebhookEventConversionPaid, SampleConversion(tonicpow.ConversionStatusPaid))
+	require.NoError(t, err)
+	assert.Len(t, event.ID, 32)
+
+	t.Run("replayed delivery is rejected", func(t *testing.T) {
+		var req *http.Request
+		req, err = NewWebhookDeliveryAt(testWebhookSecret, event, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("wrong secret is rejected", func(t *testing.T) {
+		var req *http.Request
+		req, err = NewWebhookDelivery("wrong-secret", event)
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
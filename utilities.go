@@ -1,7 +1,12 @@
 package tonicpow
 
-// isInList checks if string is known or not
-func isInList(test string, list []string) bool {
+import (
+	"fmt"
+	"time"
+)
+
+// isInList checks if the value is known or not
+func isInList[T comparable](test T, list []T) bool {
 	for _, a := range list {
 		if test == a {
 			return true
@@ -9,3 +14,15 @@ func isInList(test string, list []string) bool {
 	}
 	return false
 }
+
+// parseTimestamp will parse an API timestamp (2006-01-02 15:04:05 in UTC, or RFC3339)
+func parseTimestamp(value string) (time.Time, error) {
+	if t, err := time.Parse(timestampLayout, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp: %s", value)
+	}
+	return t, nil
+}
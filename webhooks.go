@@ -0,0 +1,342 @@
+package tonicpow
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// WebhookSignatureHeader is the header that holds the signature of a webhook delivery
+	WebhookSignatureHeader = "X-TonicPow-Signature"
+
+	// WebhookEventConversionCreated is delivered when a conversion is created
+	WebhookEventConversionCreated WebhookEventType = "conversion_created"
+
+	// WebhookEventConversionPaid is delivered when a conversion has been paid out
+	WebhookEventConversionPaid WebhookEventType = "conversion_paid"
+
+	// WebhookEventConversionCanceled is delivered when a (delayed) conversion is canceled
+	WebhookEventConversionCanceled WebhookEventType = "conversion_canceled"
+
+	// WebhookEventCampaignLowBalance is delivered when a campaign balance drops below the alert threshold
+	WebhookEventCampaignLowBalance WebhookEventType = "campaign_low_balance"
+
+	// Webhook defaults
+	defaultWebhookMaxBodySize int64 = 1 << 20         // Maximum size of a webhook delivery (1MB)
+	defaultWebhookTolerance         = 5 * time.Minute // Maximum age of a webhook delivery
+	webhookSignatureVersion         = "v1"            // Current version of the signature scheme
+	webhookTimestampKey             = "t"             // Timestamp key in the signature header
+)
+
+// Webhook errors that can be used with errors.Is()
+var (
+	// ErrWebhookSignature is returned when the webhook signature is missing or does not match
+	ErrWebhookSignature = errors.New("invalid webhook signature")
+
+	// ErrWebhookExpired is returned when the webhook timestamp is outside the tolerance (replay protection)
+	ErrWebhookExpired = errors.New("webhook timestamp is outside the tolerance")
+)
+
+// WebhookEventType is the type of event delivered to the App.WebhookURL
+type WebhookEventType string
+
+// WebhookEvent is the envelope of every webhook delivery
+type WebhookEvent struct {
+	CreatedAt time.Time        `json:"created_at"`
+	Data      json.RawMessage  `json:"data"`
+	ID        string           `json:"id"`
+	Type      WebhookEventType `json:"type"`
+}
+
+// Conversion will decode the event data as a Conversion (conversion_* events)
+func (e *WebhookEvent) Conversion() (conversion *Conversion, err error) {
+	err = json.Unmarshal(e.Data, &conversion)
+	return
+}
+
+// Campaign will decode the event data as a Campaign (campaign_* events)
+func (e *WebhookEvent) Campaign() (campaign *Campaign, err error) {
+	err = json.Unmarshal(e.Data, &campaign)
+	return
+}
+
+// ConversionEventHandler is the callback for conversion webhook events
+type ConversionEventHandler func(ctx context.Context, event *WebhookEvent, conversion *Conversion) error
+
+// CampaignEventHandler is the callback for campaign webhook events
+type CampaignEventHandler func(ctx context.Context, event *WebhookEvent, campaign *Campaign) error
+
+// WebhookOps allow functional options to be supplied
+// that overwrite default webhook handler options.
+type WebhookOps func(h *WebhookHandler)
+
+// WithWebhookTolerance will overwrite the maximum age of a delivery before it is rejected as a replay.
+// Default tolerance is 5 minutes (zero or a negative tolerance is ignored, see WithoutWebhookReplayProtection).
+func WithWebhookTolerance(tolerance time.Duration) WebhookOps {
+	return func(h *WebhookHandler) {
+		if tolerance > 0 {
+			h.tolerance = tolerance
+		}
+	}
+}
+
+// WithoutWebhookReplayProtection will accept deliveries of any age (the timestamp is not checked).
+// Only use this if replays are prevented another way, such as idempotent callbacks.
+func WithoutWebhookReplayProtection() WebhookOps {
+	return func(h *WebhookHandler) {
+		h.tolerance = 0
+	}
+}
+
+// WithWebhookMaxBodySize will overwrite the maximum size (in bytes) of a delivery.
+// Default is 1MB.
+func WithWebhookMaxBodySize(size int64) WebhookOps {
+	return func(h *WebhookHandler) {
+		h.maxBodySize = size
+	}
+}
+
+// WebhookHandler is an http.Handler that verifies, parses and dispatches TonicPow webhook deliveries
+type WebhookHandler struct {
+	campaignHandlers   map[WebhookEventType][]CampaignEventHandler
+	conversionHandlers map[WebhookEventType][]ConversionEventHandler
+	maxBodySize        int64
+	mu                 sync.RWMutex
+	now                func() time.Time
+	secret             []byte
+	tolerance          time.Duration // Zero if replay protection is disabled
+}
+
+// NewWebhookHandler creates a new webhook handler using the shared secret of the App
+func NewWebhookHandler(secret string, opts ...WebhookOps) (*WebhookHandler, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("missing a webhook secret")
+	}
+	h := &WebhookHandler{
+		campaignHandlers:   make(map[WebhookEventType][]CampaignEventHandler),
+		conversionHandlers: make(map[WebhookEventType][]ConversionEventHandler),
+		maxBodySize:        defaultWebhookMaxBodySize,
+		now:                time.Now,
+		secret:             []byte(secret),
+		tolerance:          defaultWebhookTolerance,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h, nil
+}
+
+// OnConversionCreated will register a callback for the conversion_created event
+func (h *WebhookHandler) OnConversionCreated(fn ConversionEventHandler) {
+	h.onConversion(WebhookEventConversionCreated, fn)
+}
+
+// OnConversionPaid will register a callback for the conversion_paid event
+func (h *WebhookHandler) OnConversionPaid(fn ConversionEventHandler) {
+	h.onConversion(WebhookEventConversionPaid, fn)
+}
+
+// OnConversionCanceled will register a callback for the conversion_canceled event
+func (h *WebhookHandler) OnConversionCanceled(fn ConversionEventHandler) {
+	h.onConversion(WebhookEventConversionCanceled, fn)
+}
+
+// OnCampaignLowBalance will register a callback for the campaign_low_balance event
+func (h *WebhookHandler) OnCampaignLowBalance(fn CampaignEventHandler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.campaignHandlers[WebhookEventCampaignLowBalance] = append(h.campaignHandlers[WebhookEventCampaignLowBalance], fn)
+}
+
+// onConversion will register a conversion callback for the given event type
+func (h *WebhookHandler) onConversion(eventType WebhookEventType, fn ConversionEventHandler) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.conversionHandlers[eventType] = append(h.conversionHandlers[eventType], fn)
+}
+
+// ServeHTTP will verify and dispatch the webhook delivery (implements http.Handler)
+//
+// Invalid signatures and replays are rejected with a 401, malformed deliveries with a 400
+// and callback errors with a 500 (so the delivery will be retried)
+//
+// Deliveries are not deduplicated (WebhookEvent.ID is not checked), so a delivery that is retried
+// or replayed within the tolerance calls the callbacks again: callbacks must be idempotent
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	event, err := h.ParseRequest(req)
+	if errors.Is(err, ErrWebhookSignature) || errors.Is(err, ErrWebhookExpired) {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err = h.Dispatch(req.Context(), event); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// ParseRequest will read the body of the request, verify the signature and return the event
+func (h *WebhookHandler) ParseRequest(req *http.Request) (*WebhookEvent, error) {
+	body, err := io.ReadAll(io.LimitReader(req.Body, h.maxBodySize+1))
+	if err != nil {
+		return nil, err
+	} else if int64(len(body)) > h.maxBodySize {
+		return nil, fmt.Errorf("webhook body exceeds %d bytes", h.maxBodySize)
+	}
+	return h.ParseEvent(body, req.Header.Get(WebhookSignatureHeader))
+}
+
+// ParseEvent will verify the signature header against the raw body and return the event
+func (h *WebhookHandler) ParseEvent(body []byte, signatureHeader string) (*WebhookEvent, error) {
+	if err := verifyWebhookSignature(h.secret, signatureHeader, body, h.tolerance, h.now()); err != nil {
+		return nil, err
+	}
+	event := new(WebhookEvent)
+	if err := json.Unmarshal(body, event); err != nil {
+		return nil, fmt.Errorf("invalid webhook payload: %w", err)
+	} else if len(event.Type) == 0 {
+		return nil, fmt.Errorf("missing required attribute: %s", "type")
+	}
+	return event, nil
+}
+
+// Dispatch will decode the event data and run all the registered callbacks for the event type
+//
+// Unknown event types and event types without callbacks are ignored
+func (h *WebhookHandler) Dispatch(ctx context.Context, event *WebhookEvent) error {
+	h.mu.RLock()
+	conversionHandlers := h.conversionHandlers[event.Type]
+	campaignHandlers := h.campaignHandlers[event.Type]
+	h.mu.RUnlock()
+
+	if len(conversionHandlers) > 0 {
+		conversion, err := event.Conversion()
+		if err != nil {
+			return fmt.Errorf("invalid %s data: %w", event.Type, err)
+		}
+		for _, fn := range conversionHandlers {
+			if err = fn(ctx, event, conversion); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(campaignHandlers) > 0 {
+		campaign, err := event.Campaign()
+		if err != nil {
+			return fmt.Errorf("invalid %s data: %w", event.Type, err)
+		}
+		for _, fn := range campaignHandlers {
+			if err = fn(ctx, event, campaign); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// SignWebhook will return the signature header value for the body at the given time
+//
+// Format: t=<unix timestamp>,v1=<hex encoded HMAC-SHA256 of "<timestamp>.<body>">
+func SignWebhook(secret string, timestamp time.Time, body []byte) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+	return fmt.Sprintf(
+		"%s=%s,%s=%s",
+		webhookTimestampKey, unix,
+		webhookSignatureVersion, hex.EncodeToString(computeWebhookSignature([]byte(secret), unix, body)),
+	)
+}
+
+// VerifyWebhookSignature will verify the signature header of a webhook delivery
+// using the shared secret and reject deliveries older (or newer) than the tolerance
+//
+// The signature is checked first, ErrWebhookExpired is only returned for a valid signature.
+// Zero or a negative tolerance uses the default tolerance (5 minutes)
+func VerifyWebhookSignature(secret, signatureHeader string, body []byte, tolerance time.Duration) error {
+	if tolerance <= 0 {
+		tolerance = defaultWebhookTolerance
+	}
+	return verifyWebhookSignature([]byte(secret), signatureHeader, body, tolerance, time.Now())
+}
+
+// verifyWebhookSignature will verify the signature header at the given time (a tolerance of zero skips the timestamp)
+func verifyWebhookSignature(secret []byte, signatureHeader string, body []byte,
+	tolerance time.Duration, now time.Time) error {
+
+	// Parse the header
+	var timestamp string
+	var signatures [][]byte
+	for _, part := range strings.Split(signatureHeader, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			continue
+		}
+		switch key {
+		case webhookTimestampKey:
+			timestamp = value
+		case webhookSignatureVersion:
+			if sig, err := hex.DecodeString(value); err == nil {
+				signatures = append(signatures, sig)
+			}
+		}
+	}
+	if len(timestamp) == 0 || len(signatures) == 0 {
+		return ErrWebhookSignature
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrWebhookSignature
+	}
+
+	// Compare the signatures (constant time), before the timestamp so unsigned
+	// deliveries cannot learn the tolerance
+	expected := computeWebhookSignature(secret, timestamp, body)
+	var valid bool
+	for _, sig := range signatures {
+		if hmac.Equal(expected, sig) {
+			valid = true
+		}
+	}
+	if !valid {
+		return ErrWebhookSignature
+	}
+
+	// Check the timestamp (replay protection)
+	if tolerance > 0 {
+		if age := now.Sub(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+			return ErrWebhookExpired
+		}
+	}
+	return nil
+}
+
+// computeWebhookSignature will return the HMAC-SHA256 of "<timestamp>.<body>"
+func computeWebhookSignature(secret []byte, timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write([]byte(timestamp))
+	_, _ = mac.Write([]byte("."))
+	_, _ = mac.Write(body)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,304 @@
+package tonicpow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testWebhookSecret = "TestWebhookSecret12345678987654321"
+
+// newTestWebhookEvent will return a dummy example for tests
+func newTestWebhookEvent(t *testing.T, eventType WebhookEventType, data interface{}) []byte {
+	raw, err := json.Marshal(data)
+	require.NoError(t, err)
+	body, err := json.Marshal(&WebhookEvent{
+		CreatedAt: time.Now().UTC(),
+		Data:      raw,
+		ID:        "b02e13a7001546b1b7efb9df40ab75e5",
+		Type:      eventType,
+	})
+	require.NoError(t, err)
+	return body
+}
+
+// newTestWebhookRequest will return a signed webhook delivery for tests
+func newTestWebhookRequest(body []byte, signedAt time.Time) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(body))
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(testWebhookSecret, signedAt, body))
+	return req
+}
+
+// TestNewWebhookHandler will test the method NewWebhookHandler()
+func TestNewWebhookHandler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("missing secret", func(t *testing.T) {
+		h, err := NewWebhookHandler("")
+		assert.Error(t, err)
+		assert.Nil(t, h)
+	})
+
+	t.Run("default options", func(t *testing.T) {
+		h, err := NewWebhookHandler(testWebhookSecret)
+		assert.NoError(t, err)
+		assert.NotNil(t, h)
+		assert.Equal(t, defaultWebhookTolerance, h.tolerance)
+		assert.Equal(t, defaultWebhookMaxBodySize, h.maxBodySize)
+	})
+
+	t.Run("custom options", func(t *testing.T) {
+		h, err := NewWebhookHandler(
+			testWebhookSecret,
+			WithWebhookTolerance(time.Minute),
+			WithWebhookMaxBodySize(1024),
+		)
+		assert.NoError(t, err)
+		assert.NotNil(t, h)
+		assert.Equal(t, time.Minute, h.tolerance)
+		assert.Equal(t, int64(1024), h.maxBodySize)
+	})
+
+	t.Run("zero or negative tolerance is ignored", func(t *testing.T) {
+		for _, tolerance := range []time.Duration{0, -time.Minute} {
+			h, err := NewWebhookHandler(testWebhookSecret, WithWebhookTolerance(tolerance))
+			assert.NoError(t, err)
+			assert.Equal(t, defaultWebhookTolerance, h.tolerance)
+		}
+	})
+
+	t.Run("without replay protection", func(t *testing.T) {
+		h, err := NewWebhookHandler(testWebhookSecret, WithoutWebhookReplayProtection())
+		assert.NoError(t, err)
+		assert.Equal(t, time.Duration(0), h.tolerance)
+	})
+}
+
+// TestVerifyWebhookSignature will test the method VerifyWebhookSignature()
+func TestVerifyWebhookSignature(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{"id":"123","type":"conversion_paid","data":{}}`)
+
+	t.Run("valid signature", func(t *testing.T) {
+		header := SignWebhook(testWebhookSecret, time.Now(), body)
+		assert.NoError(t, VerifyWebhookSignature(testWebhookSecret, header, body, time.Minute))
+	})
+
+	t.Run("wrong secret", func(t *testing.T) {
+		header := SignWebhook("wrong-secret", time.Now(), body)
+		assert.ErrorIs(t, VerifyWebhookSignature(testWebhookSecret, header, body, time.Minute), ErrWebhookSignature)
+	})
+
+	t.Run("modified body", func(t *testing.T) {
+		header := SignWebhook(testWebhookSecret, time.Now(), body)
+		err := VerifyWebhookSignature(testWebhookSecret, header, []byte(`{"id":"456"}`), time.Minute)
+		assert.ErrorIs(t, err, ErrWebhookSignature)
+	})
+
+	t.Run("missing or malformed header", func(t *testing.T) {
+		for _, header := range []string{"", "t=", "v1=abc", "t=abc,v1=abc", "t=123,v1=zz", "garbage"} {
+			assert.ErrorIs(t, VerifyWebhookSignature(testWebhookSecret, header, body, 0), ErrWebhookSignature)
+		}
+	})
+
+	t.Run("expired timestamp", func(t *testing.T) {
+		header := SignWebhook(testWebhookSecret, time.Now().Add(-10*time.Minute), body)
+		assert.ErrorIs(t, VerifyWebhookSignature(testWebhookSecret, header, body, time.Minute), ErrWebhookExpired)
+	})
+
+	t.Run("future timestamp", func(t *testing.T) {
+		header := SignWebhook(testWebhookSecret, time.Now().Add(10*time.Minute), body)
+		assert.ErrorIs(t, VerifyWebhookSignature(testWebhookSecret, header, body, time.Minute), ErrWebhookExpired)
+	})
+
+	t.Run("expired timestamp with an invalid signature", func(t *testing.T) {
+		header := SignWebhook("wrong-secret", time.Now().Add(-10*time.Minute), body)
+		assert.ErrorIs(t, VerifyWebhookSignature(testWebhookSecret, header, body, time.Minute), ErrWebhookSignature)
+
+		header = SignWebhook(testWebhookSecret, time.Now().Add(-10*time.Minute), body)
+		err := VerifyWebhookSignature(testWebhookSecret, header, []byte(`{"id":"456"}`), time.Minute)
+		assert.ErrorIs(t, err, ErrWebhookSignature)
+	})
+
+	t.Run("tolerance of zero uses the default", func(t *testing.T) {
+		header := SignWebhook(testWebhookSecret, time.Now().Add(-24*time.Hour), body)
+		assert.ErrorIs(t, VerifyWebhookSignature(testWebhookSecret, header, body, 0), ErrWebhookExpired)
+		assert.ErrorIs(t, VerifyWebhookSignature(testWebhookSecret, header, body, -time.Minute), ErrWebhookExpired)
+
+		header = SignWebhook(testWebhookSecret, time.Now().Add(-time.Minute), body)
+		assert.NoError(t, VerifyWebhookSignature(testWebhookSecret, header, body, 0))
+	})
+
+	t.Run("multiple signatures (secret rotation)", func(t *testing.T) {
+		now := time.Now()
+		header := SignWebhook("old-secret", now, body) + ",v1=" +
+			SignWebhook(testWebhookSecret, now, body)[len("t=1234567890,v1="):]
+		assert.NoError(t, VerifyWebhookSignature(testWebhookSecret, header, body, time.Minute))
+	})
+}
+
+// TestWebhookHandler_ServeHTTP will test the method ServeHTTP()
+func TestWebhookHandler_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	t.Run("dispatch conversion events", func(t *testing.T) {
+		h, err := NewWebhookHandler(testWebhookSecret)
+		require.NoError(t, err)
+
+		var created, paid, canceled *Conversion
+		h.OnConversionCreated(func(_ context.Context, _ *WebhookEvent, c *Conversion) error {
+			created = c
+			return nil
+		})
+		h.OnConversionPaid(func(_ context.Context, _ *WebhookEvent, c *Conversion) error {
+			paid = c
+			return nil
+		})
+		h.OnConversionCanceled(func(_ context.Context, _ *WebhookEvent, c *Conversion) error {
+			canceled = c
+			return nil
+		})
+
+		for _, eventType := range []WebhookEventType{
+			WebhookEventConversionCreated, WebhookEventConversionPaid, WebhookEventConversionCanceled,
+		} {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, newTestWebhookRequest(newTestWebhookEvent(t, eventType, newTestConversion()), time.Now()))
+			assert.Equal(t, http.StatusOK, w.Code)
+		}
+		require.NotNil(t, created)
+		require.NotNil(t, paid)
+		require.NotNil(t, canceled)
+		assert.Equal(t, testConversionID, created.ID)
+		assert.Equal(t, testConversionID, paid.ID)
+		assert.Equal(t, testConversionID, canceled.ID)
+	})
+
+	t.Run("dispatch campaign low balance", func(t *testing.T) {
+		h, err := NewWebhookHandler(testWebhookSecret)
+		require.NoError(t, err)
+
+		var campaign *Campaign
+		var event *WebhookEvent
+		h.OnCampaignLowBalance(func(_ context.Context, e *WebhookEvent, c *Campaign) error {
+			campaign = c
+			event = e
+			return nil
+		})
+
+		w := httptest.NewRecorder()
+		body := newTestWebhookEvent(t, WebhookEventCampaignLowBalance, newTestCampaign())
+		h.ServeHTTP(w, newTestWebhookRequest(body, time.Now()))
+		assert.Equal(t, http.StatusOK, w.Code)
+		require.NotNil(t, campaign)
+		assert.Equal(t, testCampaignID, campaign.ID)
+		assert.Equal(t, WebhookEventCampaignLowBalance, event.Type)
+	})
+
+	t.Run("unknown event type is ignored", func(t *testing.T) {
+		h, err := NewWebhookHandler(testWebhookSecret)
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newTestWebhookRequest(newTestWebhookEvent(t, "unknown_event", nil), time.Now()))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("invalid method", func(t *testing.T) {
+		h, err := NewWebhookHandler(testWebhookSecret)
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/webhook", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		h, err := NewWebhookHandler(testWebhookSecret)
+		require.NoError(t, err)
+
+		req := newTestWebhookRequest(newTestWebhookEvent(t, WebhookEventConversionPaid, newTestConversion()), time.Now())
+		req.Header.Set(WebhookSignatureHeader, "t=123,v1=abcdef")
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("replayed delivery", func(t *testing.T) {
+		h, err := NewWebhookHandler(testWebhookSecret)
+		require.NoError(t, err)
+
+		body := newTestWebhookEvent(t, WebhookEventConversionPaid, newTestConversion())
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newTestWebhookRequest(body, time.Now().Add(-time.Hour)))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("old delivery without replay protection", func(t *testing.T) {
+		h, err := NewWebhookHandler(testWebhookSecret, WithoutWebhookReplayProtection())
+		require.NoError(t, err)
+
+		body := newTestWebhookEvent(t, WebhookEventConversionPaid, newTestConversion())
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newTestWebhookRequest(body, time.Now().Add(-time.Hour)))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("malformed payload", func(t *testing.T) {
+		h, err := NewWebhookHandler(testWebhookSecret)
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newTestWebhookRequest([]byte(`{"id":`), time.Now()))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, newTestWebhookRequest([]byte(`{"id":"123"}`), time.Now()))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("body too large", func(t *testing.T) {
+		h, err := NewWebhookHandler(testWebhookSecret, WithWebhookMaxBodySize(10))
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newTestWebhookRequest(newTestWebhookEvent(t, WebhookEventConversionPaid, newTestConversion()), time.Now()))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("callback error", func(t *testing.T) {
+		h, err := NewWebhookHandler(testWebhookSecret)
+		require.NoError(t, err)
+
+		h.OnConversionPaid(func(_ context.Context, _ *WebhookEvent, _ *Conversion) error {
+			return errors.New("database is down")
+		})
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newTestWebhookRequest(newTestWebhookEvent(t, WebhookEventConversionPaid, newTestConversion()), time.Now()))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("invalid event data", func(t *testing.T) {
+		h, err := NewWebhookHandler(testWebhookSecret)
+		require.NoError(t, err)
+
+		h.OnConversionPaid(func(_ context.Context, _ *WebhookEvent, _ *Conversion) error {
+			return nil
+		})
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, newTestWebhookRequest(newTestWebhookEvent(t, WebhookEventConversionPaid, "not-a-conversion"), time.Now()))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}